@@ -191,14 +191,17 @@ func readCSV(filePath string) ([]ParticipantRecord, error) {
 func createTeamWithParticipants(teamName string, participants []ParticipantRecord) (string, error) {
 	// Create team with custom ID and name
 	team := &models.Team{
-		ID:      utils.GenTeamID(),
 		Name:    teamName,
 		Members: []string{},
 		Deleted: false,
 	}
 
 	// Insert team directly into database
-	_, err := db.Teams.InsertOne(db.Ctx, team)
+	err := models.WithNewTeamID(func(ID string) error {
+		team.ID = ID
+		_, err := db.Teams.InsertOne(db.Ctx, team)
+		return err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create team: %w", err)
 	}
@@ -262,7 +265,7 @@ func createAccountFromRecord(record ParticipantRecord, teamID string) (*models.A
 		TeamID:            teamID,
 		CheckedIn:         false,
 		Present:           false,
-		HasVoted:          false,
+		VotedSessions:     []string{},
 		Consumables: models.Consumables{
 			Water:      0,
 			Pizza:      false,
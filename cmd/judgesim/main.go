@@ -0,0 +1,326 @@
+// Command judgesim runs judging offline: it builds the assignment matrix the way
+// /superusers/judging/init does, simulates judges comparing teams of known skill,
+// scores the judgments with Crowd-BT and reports how close the ranking gets to
+// the truth.
+//
+//	go run ./cmd/judgesim -teams 30 -solo 8 -pairs 4 -noise 0.5 -runs 50
+package main
+
+import (
+	"backend/internal/utils"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+)
+
+var (
+	numTeams      = flag.Int("teams", 17, "number of teams")
+	numSolo       = flag.Int("solo", 6, "number of judges judging alone")
+	numPairs      = flag.Int("pairs", 4, "number of judge pairs")
+	numTrios      = flag.Int("trios", 1, "number of judge trios")
+	numUnreliable = flag.Int("unreliable", 0, "number of judges who pick a winner at random")
+	skillSpread   = flag.Float64("spread", 1.0, "standard deviation of true team skill")
+	noise         = flag.Float64("noise", 1.0, "judge noise; a judge prefers a team with probability 1/(1+exp(-skillDiff/noise))")
+	topN          = flag.Int("top", 5, "size of the top group whose stability is tracked")
+	runs          = flag.Int("runs", 20, "number of simulated events")
+	seed          = flag.Int64("seed", 1, "random seed; run i uses seed+i")
+	scorerJSON    = flag.String("scorer", "", "scorer option overrides as JSON, e.g. {\"lambda\":0.5}")
+	jsonOutput    = flag.Bool("json", false, "print the report as JSON")
+)
+
+// RunResult is the outcome of one simulated event
+type RunResult struct {
+	Seed    int64                    `json:"seed"`
+	Steps   int                      `json:"steps"`
+	Matrix  utils.JudgeMatrixMetrics `json:"matrix"`
+	Judged  int                      `json:"judgments"`
+	Tau     float64                  `json:"kendallTau"`
+	Rho     float64                  `json:"spearmanRho"`
+	TopHits int                      `json:"topHits"` // true top-N teams found in the final top-N
+	// StableAfter is the judgment count after which the top-N set stopped changing
+	StableAfter int `json:"stableAfter"`
+}
+
+// Summary averages the runs
+type Summary struct {
+	Runs           int     `json:"runs"`
+	Teams          int     `json:"teams"`
+	Judges         int     `json:"judges"`
+	Groups         int     `json:"groups"`
+	Steps          float64 `json:"steps"`
+	Judgments      float64 `json:"judgments"`
+	GavelScore     float64 `json:"gavelScore"`
+	UniquePairs    float64 `json:"uniquePairs"`
+	TotalPairs     int     `json:"totalPairs"`
+	AvgRedundancy  float64 `json:"avgRedundancy"`
+	Collisions     float64 `json:"collisions"`
+	KendallTau     float64 `json:"kendallTau"`
+	KendallTauMin  float64 `json:"kendallTauMin"`
+	SpearmanRho    float64 `json:"spearmanRho"`
+	TopN           int     `json:"topN"`
+	TopHitRate     float64 `json:"topHitRate"`
+	StableAfter    float64 `json:"stableAfter"`
+	StableAfterMax int     `json:"stableAfterMax"`
+}
+
+func main() {
+	flag.Parse()
+
+	opts := utils.DefaultScorerOptions()
+	if *scorerJSON != "" {
+		if err := json.Unmarshal([]byte(*scorerJSON), &opts); err != nil {
+			log.Fatalf("invalid -scorer: %v", err)
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatalf("invalid -scorer: %v", err)
+	}
+
+	groups := buildGroups()
+	judges := 0
+	for _, group := range groups {
+		judges += len(group)
+	}
+
+	if *numTeams < 2 || len(groups) == 0 {
+		log.Fatal("need at least 2 teams and 1 judge")
+	}
+	if *numUnreliable > judges {
+		log.Fatalf("-unreliable is %d but there are only %d judges", *numUnreliable, judges)
+	}
+	if *topN < 1 || *topN > *numTeams {
+		log.Fatalf("-top must be between 1 and %d", *numTeams)
+	}
+	if *noise <= 0 {
+		log.Fatal("-noise must be positive")
+	}
+
+	results := []RunResult{}
+	for i := range *runs {
+		result, err := simulate(*seed+int64(i), groups, opts)
+		if err != nil {
+			log.Fatalf("run %d: %v", i, err)
+		}
+		results = append(results, result)
+	}
+
+	summary := summarize(results, judges, len(groups))
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]any{"summary": summary, "runs": results}); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	printReport(summary, results)
+}
+
+// buildGroups names the judges and groups them like pair attributes do
+func buildGroups() [][]string {
+	groups := [][]string{}
+	for i := range *numSolo {
+		groups = append(groups, []string{fmt.Sprintf("solo_%d", i)})
+	}
+	for i := range *numPairs {
+		groups = append(groups, []string{fmt.Sprintf("pair_%d_a", i), fmt.Sprintf("pair_%d_b", i)})
+	}
+	for i := range *numTrios {
+		groups = append(groups, []string{fmt.Sprintf("trio_%d_a", i), fmt.Sprintf("trio_%d_b", i), fmt.Sprintf("trio_%d_c", i)})
+	}
+	return groups
+}
+
+// simulate runs one event: every judge walks their group's column of the matrix
+// and compares each team with the one before it
+func simulate(runSeed int64, groups [][]string, opts utils.ScorerOptions) (RunResult, error) {
+	rng := rand.New(rand.NewSource(runSeed))
+
+	teamIDs := make([]string, *numTeams)
+	skill := map[string]float64{}
+	for i := range teamIDs {
+		teamIDs[i] = fmt.Sprintf("team_%02d", i)
+		skill[teamIDs[i]] = rng.NormFloat64() * *skillSpread
+	}
+
+	truth := slices.Clone(teamIDs)
+	sort.Slice(truth, func(i, j int) bool {
+		return skill[truth[i]] > skill[truth[j]]
+	})
+
+	teamOrder := slices.Clone(teamIDs)
+	rng.Shuffle(len(teamOrder), func(i, j int) {
+		teamOrder[i], teamOrder[j] = teamOrder[j], teamOrder[i]
+	})
+
+	matrix, err := utils.BuildJudgeMatrix(rng, teamOrder, len(groups), nil)
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	allJudges := slices.Concat(groups...)
+	unreliable := map[string]bool{}
+	for _, idx := range rng.Perm(len(allJudges))[:*numUnreliable] {
+		unreliable[allJudges[idx]] = true
+	}
+
+	judgments := []utils.JudgmentWithJudge{}
+	stepEnds := []int{} // judgment count at the end of each step
+	previous := map[string]string{}
+	for _, row := range matrix {
+		for groupIdx, teamID := range row {
+			if teamID == "" {
+				continue
+			}
+			for _, judgeID := range groups[groupIdx] {
+				if prev := previous[judgeID]; prev != "" {
+					judgments = append(judgments, judge(rng, judgeID, teamID, prev, skill, unreliable[judgeID]))
+				}
+				previous[judgeID] = teamID
+			}
+		}
+		stepEnds = append(stepEnds, len(judgments))
+	}
+
+	// Refit after every step to find when the top-N set settled
+	topSets := []map[string]bool{}
+	counts := []int{}
+	var ranking []string
+	for i, end := range stepEnds {
+		if end == 0 || (i > 0 && end == stepEnds[i-1]) {
+			continue
+		}
+		scorer := utils.NewCrowdBTScorerWithOptions(opts)
+		scorer.Score(judgments[:end])
+		ranking = scorer.RankTeams()
+		topSets = append(topSets, topSet(ranking, *topN))
+		counts = append(counts, end)
+	}
+
+	result := RunResult{
+		Seed:   runSeed,
+		Steps:  len(matrix),
+		Matrix: utils.MeasureJudgeMatrix(matrix, len(groups), len(teamIDs)),
+		Judged: len(judgments),
+	}
+	if len(topSets) == 0 {
+		return result, nil
+	}
+
+	result.Tau = utils.KendallTau(ranking, truth)
+	result.Rho = utils.SpearmanRho(ranking, truth)
+
+	final := topSets[len(topSets)-1]
+	for teamID := range topSet(truth, *topN) {
+		if final[teamID] {
+			result.TopHits++
+		}
+	}
+
+	result.StableAfter = counts[len(counts)-1]
+	for i := len(topSets) - 1; i >= 0 && sameSet(topSets[i], final); i-- {
+		result.StableAfter = counts[i]
+	}
+
+	return result, nil
+}
+
+// judge compares the current team with the previous one, the way a judge with
+// the configured noise would; an unreliable judge flips a coin
+func judge(rng *rand.Rand, judgeID string, current string, previous string, skill map[string]float64, unreliable bool) utils.JudgmentWithJudge {
+	pCurrent := 0.5
+	if !unreliable {
+		pCurrent = 1 / (1 + math.Exp(-(skill[current]-skill[previous]) / *noise))
+	}
+
+	if rng.Float64() < pCurrent {
+		return utils.JudgmentWithJudge{WinningTeamID: current, LosingTeamID: previous, JudgeID: judgeID}
+	}
+	return utils.JudgmentWithJudge{WinningTeamID: previous, LosingTeamID: current, JudgeID: judgeID}
+}
+
+func topSet(ranking []string, n int) map[string]bool {
+	set := map[string]bool{}
+	for _, teamID := range ranking[:min(n, len(ranking))] {
+		set[teamID] = true
+	}
+	return set
+}
+
+func sameSet(a map[string]bool, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
+
+func summarize(results []RunResult, judges int, groups int) Summary {
+	summary := Summary{
+		Runs:          len(results),
+		Teams:         *numTeams,
+		Judges:        judges,
+		Groups:        groups,
+		TopN:          *topN,
+		KendallTauMin: 1,
+	}
+	if len(results) == 0 {
+		return summary
+	}
+
+	n := float64(len(results))
+	for _, result := range results {
+		summary.Steps += float64(result.Steps) / n
+		summary.Judgments += float64(result.Judged) / n
+		summary.GavelScore += result.Matrix.GavelScore / n
+		summary.UniquePairs += float64(result.Matrix.UniquePairs) / n
+		summary.TotalPairs = result.Matrix.TotalPairs
+		summary.AvgRedundancy += result.Matrix.AvgRedundancy / n
+		summary.Collisions += float64(result.Matrix.Collisions) / n
+		summary.KendallTau += result.Tau / n
+		summary.KendallTauMin = min(summary.KendallTauMin, result.Tau)
+		summary.SpearmanRho += result.Rho / n
+		summary.TopHitRate += float64(result.TopHits) / float64(*topN) / n
+		summary.StableAfter += float64(result.StableAfter) / n
+		summary.StableAfterMax = max(summary.StableAfterMax, result.StableAfter)
+	}
+
+	return summary
+}
+
+func printReport(summary Summary, results []RunResult) {
+	fmt.Printf("Judging simulation: %d runs, %d teams, %d judges in %d groups\n",
+		summary.Runs, summary.Teams, summary.Judges, summary.Groups)
+	fmt.Printf("  noise %.2f, skill spread %.2f, %d unreliable judges\n\n", *noise, *skillSpread, *numUnreliable)
+
+	fmt.Printf("%-6s %5s %9s %8s %7s %9s %7s %7s %7s %7s\n",
+		"seed", "steps", "judgments", "pairs", "redund", "collision", "tau", "rho", "top", "stable")
+	for _, r := range results {
+		fmt.Printf("%-6d %5d %9d %4d/%-3d %7.2f %9d %7.3f %7.3f %3d/%-3d %7d\n",
+			r.Seed, r.Steps, r.Judged, r.Matrix.UniquePairs, r.Matrix.TotalPairs,
+			r.Matrix.AvgRedundancy, r.Matrix.Collisions, r.Tau, r.Rho, r.TopHits, *topN, r.StableAfter)
+	}
+
+	fmt.Printf("\nAverages\n")
+	fmt.Printf("  steps:               %.1f\n", summary.Steps)
+	fmt.Printf("  judgments:           %.1f\n", summary.Judgments)
+	fmt.Printf("  coverage:            %.1f / %d pairs (gavel score %.1f%%), redundancy %.2fx\n",
+		summary.UniquePairs, summary.TotalPairs, summary.GavelScore, summary.AvgRedundancy)
+	fmt.Printf("  collisions:          %.2f\n", summary.Collisions)
+	fmt.Printf("  kendall tau:         %.3f (worst %.3f)\n", summary.KendallTau, summary.KendallTauMin)
+	fmt.Printf("  spearman rho:        %.3f\n", summary.SpearmanRho)
+	fmt.Printf("  true top-%d found:    %.1f%%\n", summary.TopN, summary.TopHitRate*100)
+	fmt.Printf("  top-%d stable after:  %.1f judgments (worst %d)\n", summary.TopN, summary.StableAfter, summary.StableAfterMax)
+}
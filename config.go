@@ -0,0 +1,9 @@
+package backend
+
+import _ "embed"
+
+// FlagsConfig is the flag registry, built into the binary so it always matches
+// the code that checks the flags
+//
+//go:embed flags_config.json
+var FlagsConfig []byte
@@ -94,6 +94,10 @@ func AccountRegisterHandler(c fiber.Ctx) error {
 		account.ID,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
@@ -110,6 +114,7 @@ func AccountRegisterHandler(c fiber.Ctx) error {
 // @Success 200 {object} AccountTokenResponse
 // @Failure 401 {object} errmsg._AccountLoginWrongPassword
 // @Failure 404 {object} errmsg._AccountNotInitialized
+// @Failure 500 {object} errmsg._InternalServerError
 // @Router /accounts/auth/login [post]
 func AccountLoginHandler(c fiber.Ctx) error {
 	var body struct {
@@ -147,6 +152,10 @@ func AccountLoginHandler(c fiber.Ctx) error {
 		account.ID,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
@@ -10,7 +10,7 @@ import (
 
 // GetFlagsHandler returns feature flag assignments for the current account.
 // @Summary Retrieve current feature flags
-// @Description Provides the participant's active stage and boolean flags to drive feature toggles in the client.
+// @Description Provides the participant's active stage and boolean flags to drive feature toggles in the client. Flags are evaluated for the participant, so a flag that is off globally is on when its rule targets their account, team or university, or when they fall within its rollout percentage.
 // @Tags Accounts Flags
 // @Security AccountAuth
 // @Produce json
@@ -25,5 +25,16 @@ func GetFlagsHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
-	return c.JSON(flags)
+	account := models.Account{}
+	utils.GetLocals(c, "account", &account)
+	err = account.Get()
+	if err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	// rules name other accounts, so only the evaluated view is shared
+	return c.JSON(models.Flags{
+		Flags: flags.EvaluateFor(account),
+		Stage: flags.Stage,
+	})
 }
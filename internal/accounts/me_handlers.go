@@ -50,6 +50,10 @@ func AccountEditHandler(c fiber.Ctx) error {
 		account.FirstName+" "+account.LastName,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"backend/internal/errmsg"
 	"backend/internal/models"
 	"backend/internal/utils"
 
@@ -26,10 +27,15 @@ func accountPingHandler(c fiber.Ctx) error {
 // @Produce json
 // @Success 200 {object} models.Account
 // @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 500 {object} errmsg._InternalServerError
 // @Router /accounts/meta/whoami [get]
 func accountWhoAmIHandler(c fiber.Ctx) error {
 	account := models.Account{}
 	utils.GetLocals(c, "account", &account)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(account)
 }
@@ -1,6 +1,9 @@
 package accounts
 
-import "backend/internal/models"
+import (
+	"backend/internal/models"
+	"time"
+)
 
 // AccountCheckRequest mirrors the payload for /accounts/check.
 type AccountCheckRequest struct {
@@ -30,21 +33,37 @@ type AccountEditRequest struct {
 	LastName  string `json:"lastName"`
 }
 
-// VotingStatusResponse returns the current voting status for a participant.
+// VotingStatusResponse returns the state of a voting session for a participant.
 type VotingStatusResponse struct {
-	VotingOpen bool          `json:"votingOpen"`
-	HasVoted   bool          `json:"hasVoted"`
-	Finalists  []models.Team `json:"finalists"`
+	VotingOpen        bool                     `json:"votingOpen"`
+	SessionID         string                   `json:"sessionID"`
+	Name              string                   `json:"name" example:"People's Choice"`
+	Mode              string                   `json:"mode" example:"ranked" description:"single, ranked or approval"`
+	Status            string                   `json:"status" example:"open" description:"scheduled, open or closed; empty without any voting session"`
+	OpensAt           *time.Time               `json:"opensAt"`
+	ClosesAt          *time.Time               `json:"closesAt"`
+	SecondsUntilOpen  int64                    `json:"secondsUntilOpen" example:"0"`
+	SecondsUntilClose int64                    `json:"secondsUntilClose" example:"900"`
+	Eligibility       models.VotingEligibility `json:"eligibility"`
+	Eligible          bool                     `json:"eligible"`
+	IneligibleReason  string                   `json:"ineligibleReason" example:"you need to be checked in to vote in this session"`
+	OwnTeamID         string                   `json:"ownTeamID" description:"Set when the session keeps participants from voting for their own team"`
+	HasVoted          bool                     `json:"hasVoted"`
+	Finalists         []models.Team            `json:"finalists"`
 }
 
 // VotingFinalistsResponse returns the list of finalist teams.
 type VotingFinalistsResponse struct {
+	SessionID string        `json:"sessionID"`
+	Mode      string        `json:"mode" example:"single"`
 	Finalists []models.Team `json:"finalists"`
 }
 
-// VotingCastRequest contains the team ID to vote for.
+// VotingCastRequest contains the team ID to vote for, or a ballot for ranked and approval sessions.
 type VotingCastRequest struct {
-	TeamID string `json:"teamID"`
+	TeamID    string   `json:"teamID"`
+	Choices   []string `json:"choices" description:"Team IDs in order of preference, or every approved team; takes the place of teamID"`
+	SessionID string   `json:"sessionID" description:"Defaults to the current voting session"`
 }
 
 // VotingCastResponse returns confirmation of vote submission.
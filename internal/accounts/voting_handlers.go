@@ -5,64 +5,39 @@ import (
 	"backend/internal/models"
 	"backend/internal/utils"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 )
 
-var finalistSettingNames = []string{
-	models.SettingFinalist1,
-	models.SettingFinalist2,
-	models.SettingFinalist3,
-	models.SettingFinalist4,
-	models.SettingFinalist5,
-}
-
-func loadFinalistTeams(requireAll bool) ([]models.Team, error) {
-	teams := make([]models.Team, 0, len(finalistSettingNames))
-
-	for _, name := range finalistSettingNames {
-		setting := &models.Setting{Name: name}
-		status := setting.Get()
-		if status != errmsg.EmptyStatusError {
-			if requireAll {
-				return nil, fmt.Errorf("failed to load setting %s: %s", name, status.Message)
-			}
-			continue
-		}
-
-		teamID, ok := setting.Value.(string)
-		if !ok || teamID == "" {
-			if requireAll {
-				return nil, fmt.Errorf("setting %s contains no team identifier", name)
-			}
-			continue
-		}
-
-		team := models.Team{ID: teamID}
-		if err := team.Get(); err != nil {
-			return nil, err
-		}
-
-		teams = append(teams, team)
+// resolveVotingSession looks up the requested voting session, or the current one
+// when no ID is given. found is false when there are no sessions at all.
+func resolveVotingSession(sessionID string, now time.Time) (session models.VotingSession, found bool, serr errmsg.StatusError) {
+	if sessionID == "" {
+		return models.CurrentVotingSession(now)
 	}
 
-	if requireAll && len(teams) != len(finalistSettingNames) {
-		return nil, fmt.Errorf("expected %d finalists, found %d", len(finalistSettingNames), len(teams))
+	session = models.VotingSession{ID: sessionID}
+	if serr = session.Get(); serr != errmsg.EmptyStatusError {
+		return session, false, serr
 	}
 
-	return teams, nil
+	return session, true, errmsg.EmptyStatusError
 }
 
 // votingStatusHandler returns voting status and finalists for the participant
 // @Summary Get voting status
-// @Description Returns whether voting is open, if user has voted, and the list of finalists
+// @Description Returns the current voting session (or the one asked for): whether it is open, when it opens and closes with a countdown in seconds, whether the participant may vote in it (and if not, why) and already did, and its finalists. Voting is reported closed without any session, or while the voting flag is off.
 // @Tags Accounts Voting
 // @Security AccountAuth
 // @Produce json
+// @Param sessionID query string false "Voting session to report on; defaults to the current one"
 // @Success 200 {object} VotingStatusResponse
 // @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 404 {object} errmsg._VotingSessionNotFound
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /accounts/voting/status [get]
 func votingStatusHandler(c fiber.Ctx) error {
@@ -73,63 +48,126 @@ func votingStatusHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
-	finalistTeams, err := loadFinalistTeams(false)
-	if err != nil {
+	now := time.Now()
+	session, found, serr := resolveVotingSession(c.Query("sessionID"), now)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	response := VotingStatusResponse{
+		Finalists: []models.Team{},
+	}
+	if !found {
+		return c.JSON(response)
+	}
+
+	finalistTeams, serr := session.LoadFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	// the voting flag stays a master switch over every session
+	flags := models.Flags{}
+	if err := flags.Get(); err != nil {
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
-	response := map[string]interface{}{
-		"votingOpen": true,
-		"hasVoted":   account.HasVoted,
-		"finalists":  finalistTeams,
+	response.Status = session.Status(now)
+	response.VotingOpen = flags.EnabledFor("voting", &account) && response.Status == models.VotingSessionOpen
+	response.SessionID = session.ID
+	response.Name = session.Name
+	response.Mode = session.Mode
+	response.OpensAt = &session.OpensAt
+	response.ClosesAt = &session.ClosesAt
+	response.SecondsUntilOpen = int64(math.Ceil(max(session.OpensAt.Sub(now).Seconds(), 0)))
+	response.SecondsUntilClose = int64(math.Ceil(max(session.ClosesAt.Sub(now).Seconds(), 0)))
+	response.Eligibility = session.Eligibility
+	if serr := session.Eligibility.Check(account, now); serr != errmsg.EmptyStatusError {
+		response.IneligibleReason = serr.Message
+	}
+	response.Eligible = response.IneligibleReason == ""
+	if session.Eligibility.ExcludeOwnTeam {
+		response.OwnTeamID = account.TeamID
 	}
+	response.HasVoted = account.VotedIn(session.ID)
+	response.Finalists = finalistTeams
 
 	return c.JSON(response)
 }
 
 // votingFinalistsHandler returns the list of finalists with team information
 // @Summary Get finalists
-// @Description Returns the 3 finalist teams (middle, right, left order)
+// @Description Returns every finalist team of the open voting session, in finalist order
 // @Tags Accounts Voting
 // @Security AccountAuth
 // @Produce json
+// @Param sessionID query string false "Voting session; defaults to the current one"
 // @Success 200 {object} VotingFinalistsResponse
 // @Failure 401 {object} errmsg._AccountNoToken
 // @Failure 403 {object} errmsg._FlagRequired
+// @Failure 403 {object} errmsg._VotingClosed
+// @Failure 403 {object} errmsg._VotingNotCheckedIn
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 409 {object} errmsg._VotingAlreadyVoted
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /accounts/voting/finalists [get]
 func votingFinalistsHandler(c fiber.Ctx) error {
 	account := models.Account{}
 	utils.GetLocals(c, "account", &account)
+	err := account.Get()
+	if err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	session, found, serr := resolveVotingSession(c.Query("sessionID"), time.Now())
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	if !found || session.Status(time.Now()) != models.VotingSessionOpen {
+		return utils.StatusError(c, errmsg.VotingClosed)
+	}
 
 	// Check if user has already voted
-	if account.HasVoted {
-		statusErr := errmsg.NewStatusError(409, "you have already voted")
-		return utils.StatusError(c, statusErr)
+	if account.VotedIn(session.ID) {
+		return utils.StatusError(c, errmsg.VotingAlreadyVoted)
 	}
 
-	finalistTeams, err := loadFinalistTeams(true)
-	if err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
+	if serr := session.Eligibility.Check(account, time.Now()); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	finalistTeams, serr := session.LoadFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
 	return c.JSON(map[string]interface{}{
+		"sessionID": session.ID,
+		"mode":      session.Mode,
 		"finalists": finalistTeams,
 	})
 }
 
 // votingCastVoteHandler casts a vote for a finalist
 // @Summary Cast a vote
-// @Description Records a vote for one of the finalists
+// @Description Records a ballot in an open voting session: one finalist in single-choice sessions, finalists in order of preference in ranked sessions, or every approved finalist in approval sessions. Each participant votes once per session, and only if they meet the session's eligibility requirements: being checked in, being present, or having had their badge scanned recently. Sessions can also keep finalists' members from voting for their own team.
 // @Tags Accounts Voting
 // @Security AccountAuth
 // @Accept json
 // @Produce json
-// @Param payload body VotingCastRequest true "Team ID to vote for"
+// @Param payload body VotingCastRequest true "Team ID or ballot to vote for, and optionally the voting session"
 // @Success 200 {object} VotingCastResponse
+// @Failure 400 {object} errmsg._VotingInvalidBallot
+// @Failure 400 {object} errmsg._VotingInvalidFinalist
 // @Failure 401 {object} errmsg._AccountNoToken
 // @Failure 403 {object} errmsg._FlagRequired
-// @Failure 409 {object} errmsg._AccountAlreadyRegistered
+// @Failure 403 {object} errmsg._VotingClosed
+// @Failure 403 {object} errmsg._VotingNotCheckedIn
+// @Failure 403 {object} errmsg._VotingNotPresent
+// @Failure 403 {object} errmsg._VotingScanRequired
+// @Failure 403 {object} errmsg._VotingOwnTeam
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 409 {object} errmsg._VotingAlreadyVoted
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /accounts/voting/vote [post]
 func votingCastVoteHandler(c fiber.Ctx) error {
@@ -140,53 +178,54 @@ func votingCastVoteHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
-	// Check if user has already voted
-	if account.HasVoted {
-		statusErr := errmsg.NewStatusError(409, "you have already voted")
-		return utils.StatusError(c, statusErr)
-	}
-
 	// Parse request body
-	var body struct {
-		TeamID string `json:"teamID"`
-	}
+	var body VotingCastRequest
 	json.Unmarshal(c.Body(), &body)
 
-	// Validate team ID is one of the finalists
-	finalist1Setting := &models.Setting{Name: models.SettingFinalist1}
-	finalist2Setting := &models.Setting{Name: models.SettingFinalist2}
-	finalist3Setting := &models.Setting{Name: models.SettingFinalist3}
+	session, found, serr := resolveVotingSession(body.SessionID, time.Now())
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	if !found || session.Status(time.Now()) != models.VotingSessionOpen {
+		return utils.StatusError(c, errmsg.VotingClosed)
+	}
 
-	finalist1Setting.Get()
-	finalist2Setting.Get()
-	finalist3Setting.Get()
+	if serr := session.Eligibility.Check(account, time.Now()); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
 
-	isFinalist := false
-	if val, ok := finalist1Setting.Value.(string); ok && val == body.TeamID {
-		isFinalist = true
-	} else if val, ok := finalist2Setting.Value.(string); ok && val == body.TeamID {
-		isFinalist = true
-	} else if val, ok := finalist3Setting.Value.(string); ok && val == body.TeamID {
-		isFinalist = true
+	// A lone team ID is a one-team ballot
+	ballot := body.Choices
+	if len(ballot) == 0 && body.TeamID != "" {
+		ballot = []string{body.TeamID}
 	}
 
-	if !isFinalist {
-		statusErr := errmsg.NewStatusError(400, "invalid finalist team")
-		return utils.StatusError(c, statusErr)
+	// Validate the ballot only holds the session's finalists
+	if serr := session.ValidateBallot(account, ballot); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
-	// Set hasVoted = true and cache it
-	errSetVote := account.SetHasVoted()
-	if errSetVote != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(errSetVote))
+	// Claim this session's vote; a concurrent request that got here first wins
+	marked, errMark := account.MarkVoted(session.ID)
+	if errMark != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(errMark))
+	}
+	if !marked {
+		return utils.StatusError(c, errmsg.VotingAlreadyVoted)
 	}
 
 	// Create anonymous vote
 	vote := &models.Vote{
-		Choice: body.TeamID,
+		SessionID: session.ID,
+		Choice:    ballot[0],
+		Choices:   ballot,
 	}
 	errCreate := vote.Create()
 	if errCreate != nil {
+		// Without a stored ballot the account hasn't voted, so let it try again
+		if errUnmark := account.UnmarkVoted(session.ID); errUnmark != nil {
+			return utils.StatusError(c, errmsg.InternalServerError(errors.Join(errCreate, errUnmark)))
+		}
 		return utils.StatusError(c, errmsg.InternalServerError(errCreate))
 	}
 
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"backend"
 	"backend/internal/accounts"
 	"backend/internal/db"
 	"backend/internal/env"
@@ -9,6 +10,7 @@ import (
 	"backend/internal/judge"
 	"backend/internal/meta"
 	"backend/internal/models"
+	"backend/internal/scheduler"
 	"backend/internal/superusers"
 	"backend/internal/teams"
 	"log"
@@ -41,6 +43,21 @@ func getEmitterConfig(deployment string) events.Config {
 	}
 }
 
+func getSchedulerConfig(deployment string) scheduler.Config {
+	switch deployment {
+	case "test":
+		return scheduler.Config{
+			PollEvery: 100 * time.Millisecond,
+			Lease:     time.Minute,
+		}
+	default:
+		return scheduler.Config{
+			PollEvery: 5 * time.Second,
+			Lease:     time.Minute,
+		}
+	}
+}
+
 func initBadgePileSalt() {
 	setting := &models.Setting{Name: models.SettingBadgePileSalt}
 
@@ -57,6 +74,15 @@ func initBadgePileSalt() {
 	}
 }
 
+func initFlagRegistry() {
+	registry, err := models.LoadFlagRegistry(backend.FlagsConfig)
+	if err != nil {
+		log.Fatalf("failed to load the flag registry: %v", err)
+	}
+
+	models.FlagsRegistry = registry
+}
+
 func SetupApp(deployment string, envRoot string, appVersion string) *fiber.App {
 	app := fiber.New()
 
@@ -67,6 +93,9 @@ func SetupApp(deployment string, envRoot string, appVersion string) *fiber.App {
 	// initializing environment
 	env.Init(envRoot, appVersion)
 
+	// loading the flags registry before anything can change flags
+	initFlagRegistry()
+
 	// initializing db
 	if err := db.InitDB(deployment); err != nil {
 		log.Fatal("Could not connect to MongoDB")
@@ -86,6 +115,11 @@ func SetupApp(deployment string, envRoot string, appVersion string) *fiber.App {
 		deployment,
 	)
 
+	// running scheduled flag stages
+	scheduler.Sc = scheduler.NewScheduler(
+		getSchedulerConfig(deployment),
+	)
+
 	// loading the BADGE_PILE_SALT
 	initBadgePileSalt()
 
@@ -3,9 +3,12 @@ package db
 import (
 	"backend/internal/env"
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -26,6 +29,20 @@ var Settings *mongo.Collection
 var Judges *mongo.Collection
 var Judgments *mongo.Collection
 var Votes *mongo.Collection
+var Leaderboard *mongo.Collection
+var RubricScores *mongo.Collection
+var JudgeNotes *mongo.Collection
+var JudgeSkips *mongo.Collection
+var JudgeConflicts *mongo.Collection
+var VotingSessions *mongo.Collection
+var VoteVoids *mongo.Collection
+var FlagStageSchedules *mongo.Collection
+var FlagStageExecutions *mongo.Collection
+var ConfigHistory *mongo.Collection
+
+// IDIndex names the unique index on id that collections with drawn IDs have,
+// so a write can tell a taken ID from any other duplicate key
+const IDIndex = "id_unique"
 
 func InitDB(deployment string) error {
 	DB_DEPLOYMENT = deployment
@@ -57,10 +74,182 @@ func InitDB(deployment string) error {
 	Judges = GetCollection(deployment, "judges", Client)
 	Judgments = GetCollection(deployment, "judgments", Client)
 	Votes = GetCollection(deployment, "votes", Client)
+	Leaderboard = GetCollection(deployment, "leaderboard", Client)
+	RubricScores = GetCollection(deployment, "rubricscores", Client)
+	JudgeNotes = GetCollection(deployment, "judgenotes", Client)
+	JudgeSkips = GetCollection(deployment, "judgeskips", Client)
+	JudgeConflicts = GetCollection(deployment, "judgeconflicts", Client)
+	VotingSessions = GetCollection(deployment, "votingsessions", Client)
+	VoteVoids = GetCollection(deployment, "votevoids", Client)
+	FlagStageSchedules = GetCollection(deployment, "flagstageschedules", Client)
+	FlagStageExecutions = GetCollection(deployment, "flagstageexecutions", Client)
+	// old and new values of rules and stages are documents; reading them back as
+	// maps keeps them objects when the history is served as JSON
+	ConfigHistory = Client.Database(deployment).Collection("confighistory",
+		options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true}),
+	)
+
+	// IDs are drawn at random; the index is what keeps two writers from
+	// storing the same one
+	for _, collection := range []*mongo.Collection{
+		Accounts, Teams, FlagStages, Judges, Judgments, Votes, RubricScores,
+		JudgeNotes, JudgeSkips, JudgeConflicts, VotingSessions, VoteVoids,
+		FlagStageSchedules, FlagStageExecutions, ConfigHistory,
+	} {
+		err = createIDIndex(collection)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the unique judge and step index first only covered judgments with a step;
+	// its replacement has the same keys, so it needs another name and the old
+	// one has to go before it can be created
+	err = dropIndexIfExists(Judgments, "judgeID_1_step_1")
+	if err != nil {
+		return err
+	}
+
+	// judgments are read back by judge and by team when scoring
+	_, err = Judgments.Indexes().CreateMany(Ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "judgeID", Value: 1}}},
+		{Keys: bson.D{{Key: "winningTeamID", Value: 1}}},
+		{Keys: bson.D{{Key: "losingTeamID", Value: 1}}},
+		// one valid judgment per judge per step; retracted, invalidated and archived ones don't count
+		{
+			Keys: bson.D{{Key: "judgeID", Value: 1}, {Key: "step", Value: 1}},
+			Options: options.Index().
+				SetName("judgeID_1_step_1_valid").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "valid"}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// a judge keeps a single rubric score sheet per team
+	_, err = RubricScores.Indexes().CreateOne(Ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "judgeID", Value: 1}, {Key: "teamID", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// likewise a single private note per judge and team
+	_, err = JudgeNotes.Indexes().CreateOne(Ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "judgeID", Value: 1}, {Key: "teamID", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// a conflict names either a team or a university, so both are part of the key
+	_, err = JudgeConflicts.Indexes().CreateOne(Ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "judgeID", Value: 1},
+			{Key: "teamID", Value: 1},
+			{Key: "universityNormalized", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// votes are tallied per voting session
+	_, err = Votes.Indexes().CreateOne(Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sessionID", Value: 1}, {Key: "choice", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// schedulers look for the earliest due schedule on every poll
+	_, err = FlagStageSchedules.Indexes().CreateOne(Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "runAt", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the history is read as a timeline, and per key when restoring
+	_, err = ConfigHistory.Indexes().CreateMany(Ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "changedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "key", Value: 1}, {Key: "changedAt", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// duplicateIDsShown bounds how many duplicated IDs are logged per collection
+const duplicateIDsShown = 10
+
+// createIDIndex creates the unique id index unless the collection already holds
+// duplicated IDs, which older IDs from a weaker generator can have. Those are
+// logged and the index is left out rather than failing startup; it is created
+// on the next start once they are fixed.
+func createIDIndex(collection *mongo.Collection) error {
+	// the check reads the whole collection, so it only runs until the index exists
+	specs, err := collection.Indexes().ListSpecifications(Ctx)
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.Name == IDIndex {
+			return nil
+		}
+	}
+
+	cursor, err := collection.Aggregate(Ctx, []bson.M{
+		{"$match": bson.M{"id": bson.M{"$exists": true}}},
+		{"$group": bson.M{"_id": "$id", "count": bson.M{"$sum": 1}}},
+		{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+		{"$limit": duplicateIDsShown},
+	})
+	if err != nil {
+		return err
+	}
+
+	var duplicates []struct {
+		ID    any   `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err = cursor.All(Ctx, &duplicates); err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		log.Printf("NOT CREATING THE UNIQUE ID INDEX ON %s: IDS ARE DUPLICATED (up to %d shown): %v",
+			collection.Name(), duplicateIDsShown, duplicates)
+		return nil
+	}
+
+	_, err = collection.Indexes().CreateOne(Ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetName(IDIndex).SetUnique(true),
+	})
+
+	return err
+}
+
+// dropIndexIfExists drops an index by name, doing nothing when the index or
+// the collection doesn't exist
+func dropIndexIfExists(collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(Ctx, name)
+	var cerr mongo.CommandError
+	if errors.As(err, &cerr) && (cerr.HasErrorCode(26) || cerr.HasErrorCode(27)) {
+		// NamespaceNotFound or IndexNotFound
+		return nil
+	}
+
+	return err
+}
+
 func GetCollection(database string, collectionName string, client *mongo.Client) *mongo.Collection {
 	return client.Database(database).Collection(collectionName)
 }
@@ -109,6 +298,29 @@ func CacheSetBytes(key string, value []byte) error {
 	return RDB.Set(Ctx, key, value, 0).Err()
 }
 
+// setIfNotOlder only replaces the value when its version isn't older than the
+// cached one's, so writers finishing out of order can't leave an older value
+// behind. The version is kept next to the value and expires with it.
+var setIfNotOlder = redis.NewScript(`
+local cached = tonumber(redis.call("GET", KEYS[2]))
+if cached and cached > tonumber(ARGV[1]) then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+redis.call("SET", KEYS[2], ARGV[1], "PX", ARGV[3])
+return 1
+`)
+
+// CacheSetBytesIfNotOlder caches value at key for ttl unless a newer version of
+// it is already cached
+func CacheSetBytesIfNotOlder(key string, version int64, value []byte, ttl time.Duration) error {
+	if DB_DEPLOYMENT != "prod" {
+		return redis.Nil
+	}
+
+	return setIfNotOlder.Run(Ctx, RDB, []string{key, key + ":version"}, version, value, ttl.Milliseconds()).Err()
+}
+
 func CacheGet(key string) (string, error) {
 	if DB_DEPLOYMENT != "prod" {
 		return "", redis.Nil
@@ -134,3 +346,62 @@ func CacheDel(key string) error {
 
 	return err
 }
+
+func CacheHGetAll(key string) (map[string]string, error) {
+	if DB_DEPLOYMENT != "prod" {
+		return nil, redis.Nil
+	}
+
+	values, err := RDB.HGetAll(Ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, redis.Nil
+	}
+
+	return values, nil
+}
+
+// CacheHSetAll replaces the hash at key with values, expiring it after ttl
+func CacheHSetAll(key string, values map[string]any, ttl time.Duration) error {
+	if DB_DEPLOYMENT != "prod" {
+		return redis.Nil
+	}
+
+	_, err := RDB.TxPipelined(Ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(Ctx, key)
+		if len(values) > 0 {
+			pipe.HSet(Ctx, key, values)
+		}
+		pipe.Expire(Ctx, key, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// hincrbyIfExists only increments counters of a hash that is already cached, so
+// a missing hash is rebuilt from the source of truth instead of starting at zero
+var hincrbyIfExists = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+for i = 1, #ARGV, 2 do
+	redis.call("HINCRBY", KEYS[1], ARGV[i], ARGV[i + 1])
+end
+return 1
+`)
+
+func CacheHIncrByIfExists(key string, increments map[string]int64) error {
+	if DB_DEPLOYMENT != "prod" {
+		return redis.Nil
+	}
+
+	args := make([]any, 0, 2*len(increments))
+	for field, by := range increments {
+		args = append(args, field, by)
+	}
+
+	return hincrbyIfExists.Run(Ctx, RDB, []string{key}, args...).Err()
+}
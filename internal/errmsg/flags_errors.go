@@ -1,15 +1,54 @@
 package errmsg
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
 var (
 	FlagRequired = NewStatusError(
 		http.StatusUnauthorized,
 		"this feature is not available right now",
 	)
+
+	FlagRuleInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"a flag rule needs a flag name and a percentage between 0 and 100",
+	)
 )
 
+// FlagUnknown names the flags missing from the flag registry
+func FlagUnknown(flags []string) StatusError {
+	return NewStatusError(
+		http.StatusBadRequest,
+		"unknown flags: "+strings.Join(flags, ", "),
+	)
+}
+
+// FlagInconsistent lists the dependencies and conflicts a change would break
+func FlagInconsistent(problems []string) StatusError {
+	return NewStatusError(
+		http.StatusConflict,
+		"inconsistent flags: "+strings.Join(problems, "; "),
+	)
+}
+
 type _FlagRequired struct {
 	StatusCode int    `json:"statusCode" example:"401"`
 	Message    string `json:"message" example:"this feature is not available right now"`
 }
+
+type _FlagRuleInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"a flag rule needs a flag name and a percentage between 0 and 100"`
+}
+
+type _FlagUnknown struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"unknown flags: submission_write"`
+}
+
+type _FlagInconsistent struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"inconsistent flags: submissions_write requires teams_read"`
+}
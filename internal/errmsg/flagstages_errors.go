@@ -7,9 +7,59 @@ var (
 		http.StatusNotFound,
 		"flag stage not found",
 	)
+
+	FlagStageScheduleNotFound = NewStatusError(
+		http.StatusNotFound,
+		"flag stage schedule not found",
+	)
+
+	FlagStageScheduleInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"a flag stage schedule needs a stage and a time in the future",
+	)
+
+	FlagStageScheduleNotPending = NewStatusError(
+		http.StatusConflict,
+		"the flag stage schedule has already run or been cancelled",
+	)
+
+	FlagStageExecutionNotFound = NewStatusError(
+		http.StatusNotFound,
+		"flag stage execution not found",
+	)
+
+	FlagStageRollbackConflict = NewStatusError(
+		http.StatusConflict,
+		"only the latest flag stage execution that hasn't been rolled back can be rolled back",
+	)
 )
 
 type _FlagStageNotFound struct {
 	StatusCode int    `json:"statusCode" example:"404"`
 	Message    string `json:"message" example:"flag stage not found"`
 }
+
+type _FlagStageScheduleNotFound struct {
+	StatusCode int    `json:"statusCode" example:"404"`
+	Message    string `json:"message" example:"flag stage schedule not found"`
+}
+
+type _FlagStageScheduleInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"a flag stage schedule needs a stage and a time in the future"`
+}
+
+type _FlagStageScheduleNotPending struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"the flag stage schedule has already run or been cancelled"`
+}
+
+type _FlagStageExecutionNotFound struct {
+	StatusCode int    `json:"statusCode" example:"404"`
+	Message    string `json:"message" example:"flag stage execution not found"`
+}
+
+type _FlagStageRollbackConflict struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"only the latest flag stage execution that hasn't been rolled back can be rolled back"`
+}
@@ -0,0 +1,25 @@
+package errmsg
+
+import "net/http"
+
+var (
+	ConfigHistoryInvalidQuery = NewStatusError(
+		http.StatusBadRequest,
+		"the history can be filtered by kind (flag or setting), key and RFC 3339 times",
+	)
+
+	ConfigRestoreInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"a restore needs a time in the past and a kind of flag, setting or neither for both",
+	)
+)
+
+type _ConfigHistoryInvalidQuery struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"the history can be filtered by kind (flag or setting), key and RFC 3339 times"`
+}
+
+type _ConfigRestoreInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"a restore needs a time in the past and a kind of flag, setting or neither for both"`
+}
@@ -21,8 +21,124 @@ var (
 		http.StatusAccepted,
 		"judge resting",
 	)
+	JudgingInvalidBootstrapOptions = NewStatusError(
+		http.StatusBadRequest,
+		"invalid bootstrap options",
+	)
+	JudgingInvalidOutlierOptions = NewStatusError(
+		http.StatusBadRequest,
+		"invalid outlier options",
+	)
+	JudgeWeightInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"judge weight must be between 0 and 1",
+	)
+	FinalistsInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"finalists must be distinct teams, no more than the finalist count",
+	)
+	FinalistsLocked = NewStatusError(
+		http.StatusConflict,
+		"finalists are locked",
+	)
+	JudgingInvalidInitMode = NewStatusError(
+		http.StatusBadRequest,
+		"init mode must be empty or replan",
+	)
+	JudgingInvalidSeed = NewStatusError(
+		http.StatusBadRequest,
+		"seed must be an integer",
+	)
+	JudgingNotInitialized = NewStatusError(
+		http.StatusConflict,
+		"judging has not been initialized",
+	)
+	JudgmentSameTeam = NewStatusError(
+		http.StatusBadRequest,
+		"judgment must compare two different teams",
+	)
+	JudgmentTeamNotAssigned = NewStatusError(
+		http.StatusForbidden,
+		"judgment must compare the judge's current and previous teams",
+	)
+	JudgmentTeamDeleted = NewStatusError(
+		http.StatusGone,
+		"judged team has been deleted",
+	)
+	JudgmentDuplicate = NewStatusError(
+		http.StatusConflict,
+		"judgment already submitted for this step",
+	)
+	JudgmentTooEarly = NewStatusError(
+		http.StatusTooEarly,
+		"judgment submitted before the wait window ended",
+	)
+	JudgmentNotFound = NewStatusError(
+		http.StatusNotFound,
+		"judgment not found",
+	)
+	JudgmentNotValid = NewStatusError(
+		http.StatusConflict,
+		"judgment was already retracted, invalidated or archived",
+	)
+	JudgmentRetractWindowClosed = NewStatusError(
+		http.StatusForbidden,
+		"judgment can no longer be retracted",
+	)
+	JudgmentReasonRequired = NewStatusError(
+		http.StatusBadRequest,
+		"a reason is required to invalidate a judgment",
+	)
+	JudgingInvalidRubric = NewStatusError(
+		http.StatusBadRequest,
+		"invalid rubric",
+	)
+	RubricScoreInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"rubric scores must cover every criterion within its scale",
+	)
+	JudgeNoteTooLong = NewStatusError(
+		http.StatusBadRequest,
+		"note is too long",
+	)
+	JudgeSkipInvalidReason = NewStatusError(
+		http.StatusBadRequest,
+		"skip reason must be absent, conflict or technical",
+	)
+	JudgeSkipDuplicate = NewStatusError(
+		http.StatusConflict,
+		"team already skipped at this step",
+	)
+	JudgeSkipAlreadyJudged = NewStatusError(
+		http.StatusConflict,
+		"team already judged at this step",
+	)
+	JudgmentTeamSkipped = NewStatusError(
+		http.StatusConflict,
+		"team was skipped at this step",
+	)
+	JudgeConflictInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"conflict must name either a team or a university",
+	)
+	JudgeConflictDuplicate = NewStatusError(
+		http.StatusConflict,
+		"conflict already declared",
+	)
+	JudgeConflictNotFound = NewStatusError(
+		http.StatusNotFound,
+		"conflict not found",
+	)
 )
 
+// JudgingInvalidScorerOptions says why the scorer options can't be used
+func JudgingInvalidScorerOptions(err error) StatusError {
+	return NewStatusError(
+		http.StatusBadRequest,
+		"invalid scorer options: "+err.Error(),
+	)
+}
+
 type _JudgeNotFound struct {
 	StatusCode int    `json:"statusCode" example:"404"`
 	Message    string `json:"message" example:"judge not found"`
@@ -42,3 +158,143 @@ type _JudgeResting struct {
 	StatusCode int    `json:"statusCode" example:"202"`
 	Message    string `json:"message" example:"judge resting"`
 }
+
+type _JudgingInvalidScorerOptions struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"invalid scorer options: maxIterations must be positive"`
+}
+
+type _JudgingInvalidBootstrapOptions struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"invalid bootstrap options"`
+}
+
+type _JudgmentSameTeam struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"judgment must compare two different teams"`
+}
+
+type _JudgmentTeamNotAssigned struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"judgment must compare the judge's current and previous teams"`
+}
+
+type _JudgmentTeamDeleted struct {
+	StatusCode int    `json:"statusCode" example:"410"`
+	Message    string `json:"message" example:"judged team has been deleted"`
+}
+
+type _JudgmentDuplicate struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"judgment already submitted for this step"`
+}
+
+type _JudgmentTooEarly struct {
+	StatusCode int    `json:"statusCode" example:"425"`
+	Message    string `json:"message" example:"judgment submitted before the wait window ended"`
+}
+
+type _JudgmentNotFound struct {
+	StatusCode int    `json:"statusCode" example:"404"`
+	Message    string `json:"message" example:"judgment not found"`
+}
+
+type _JudgmentNotValid struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"judgment was already retracted, invalidated or archived"`
+}
+
+type _JudgmentRetractWindowClosed struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"judgment can no longer be retracted"`
+}
+
+type _JudgmentReasonRequired struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"a reason is required to invalidate a judgment"`
+}
+
+type _JudgingInvalidRubric struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"invalid rubric"`
+}
+
+type _RubricScoreInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"rubric scores must cover every criterion within its scale"`
+}
+
+type _JudgeNoteTooLong struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"note is too long"`
+}
+
+type _JudgeSkipInvalidReason struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"skip reason must be absent, conflict or technical"`
+}
+
+type _JudgeSkipDuplicate struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"team already skipped at this step"`
+}
+
+type _JudgeSkipAlreadyJudged struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"team already judged at this step"`
+}
+
+type _JudgmentTeamSkipped struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"team was skipped at this step"`
+}
+
+type _JudgeConflictInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"conflict must name either a team or a university"`
+}
+
+type _JudgeConflictDuplicate struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"conflict already declared"`
+}
+
+type _JudgeConflictNotFound struct {
+	StatusCode int    `json:"statusCode" example:"404"`
+	Message    string `json:"message" example:"conflict not found"`
+}
+
+type _JudgingInvalidInitMode struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"init mode must be empty or replan"`
+}
+
+type _JudgingNotInitialized struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"judging has not been initialized"`
+}
+
+type _JudgingInvalidSeed struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"seed must be an integer"`
+}
+
+type _JudgingInvalidOutlierOptions struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"invalid outlier options"`
+}
+
+type _JudgeWeightInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"judge weight must be between 0 and 1"`
+}
+
+type _FinalistsInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"finalists must be distinct teams, no more than the finalist count"`
+}
+
+type _FinalistsLocked struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"finalists are locked"`
+}
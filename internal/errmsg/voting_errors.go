@@ -0,0 +1,157 @@
+package errmsg
+
+import (
+	"net/http"
+)
+
+var (
+	VotingSessionNotFound = NewStatusError(
+		http.StatusNotFound,
+		"voting session not found",
+	)
+
+	VotingSessionInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"voting session needs a name, a closing time after its opening time, distinct finalists, a known voting mode, and a badge scan window of at most a day",
+	)
+
+	VotingSessionStarted = NewStatusError(
+		http.StatusConflict,
+		"only the name, closing time and tally visibility can change once the voting session has opened",
+	)
+
+	VotingInvalidBallot = NewStatusError(
+		http.StatusBadRequest,
+		"ballot doesn't fit the session's voting mode",
+	)
+
+	VotingClosed = NewStatusError(
+		http.StatusForbidden,
+		"voting is not open",
+	)
+
+	VotingNotCheckedIn = NewStatusError(
+		http.StatusForbidden,
+		"you need to be checked in to vote in this session",
+	)
+
+	VotingNotPresent = NewStatusError(
+		http.StatusForbidden,
+		"you need to be at the venue to vote in this session",
+	)
+
+	VotingScanRequired = NewStatusError(
+		http.StatusForbidden,
+		"your badge needs to have been scanned recently to vote in this session",
+	)
+
+	VotingOwnTeam = NewStatusError(
+		http.StatusForbidden,
+		"you can't vote for your own team in this session",
+	)
+
+	VotingAlreadyVoted = NewStatusError(
+		http.StatusConflict,
+		"you have already voted",
+	)
+
+	VotingInvalidFinalist = NewStatusError(
+		http.StatusBadRequest,
+		"invalid finalist team",
+	)
+
+	VotingInvalidAnomalyOptions = NewStatusError(
+		http.StatusBadRequest,
+		"invalid anomaly detection options",
+	)
+
+	VoteVoidInvalid = NewStatusError(
+		http.StatusBadRequest,
+		"voiding votes needs a justification and a bucket of the voting session",
+	)
+
+	VoteVoidNoVotes = NewStatusError(
+		http.StatusNotFound,
+		"no counted votes in that bucket",
+	)
+
+	VotingStreamTokenInvalid = NewStatusError(
+		http.StatusUnauthorized,
+		"tally stream token is invalid, expired or for another voting session",
+	)
+)
+
+type _VotingSessionNotFound struct {
+	StatusCode int    `json:"statusCode" example:"404"`
+	Message    string `json:"message" example:"voting session not found"`
+}
+
+type _VotingSessionInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"voting session needs a name, a closing time after its opening time, distinct finalists, a known voting mode, and a badge scan window of at most a day"`
+}
+
+type _VotingSessionStarted struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"only the name, closing time and tally visibility can change once the voting session has opened"`
+}
+
+type _VotingInvalidBallot struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"ballot doesn't fit the session's voting mode"`
+}
+
+type _VotingClosed struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"voting is not open"`
+}
+
+type _VotingNotCheckedIn struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"you need to be checked in to vote in this session"`
+}
+
+type _VotingNotPresent struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"you need to be at the venue to vote in this session"`
+}
+
+type _VotingScanRequired struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"your badge needs to have been scanned recently to vote in this session"`
+}
+
+type _VotingOwnTeam struct {
+	StatusCode int    `json:"statusCode" example:"403"`
+	Message    string `json:"message" example:"you can't vote for your own team in this session"`
+}
+
+type _VotingAlreadyVoted struct {
+	StatusCode int    `json:"statusCode" example:"409"`
+	Message    string `json:"message" example:"you have already voted"`
+}
+
+type _VotingInvalidFinalist struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"invalid finalist team"`
+}
+
+type _VotingInvalidAnomalyOptions struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"invalid anomaly detection options"`
+}
+
+type _VoteVoidInvalid struct {
+	StatusCode int    `json:"statusCode" example:"400"`
+	Message    string `json:"message" example:"voiding votes needs a justification and a bucket of the voting session"`
+}
+
+type _VoteVoidNoVotes struct {
+	StatusCode int    `json:"statusCode" example:"404"`
+	Message    string `json:"message" example:"no counted votes in that bucket"`
+}
+
+type _VotingStreamTokenInvalid struct {
+	StatusCode int    `json:"statusCode" example:"401"`
+	Message    string `json:"message" example:"tally stream token is invalid, expired or for another voting session"`
+}
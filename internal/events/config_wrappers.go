@@ -0,0 +1,37 @@
+package events
+
+import (
+	"backend/internal/models"
+	"time"
+)
+
+func (e *Emitter) ConfigRestored(
+	superuserID string,
+	at time.Time,
+	kind string,
+	keys []string,
+	changes []models.ConfigChange,
+) {
+	changed := make([]string, 0, len(changes))
+	for _, change := range changes {
+		changed = append(changed, change.Kind+":"+change.Key)
+	}
+
+	evt := models.Event{
+		Action: "config.restored",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "config",
+		TargetID:   kind,
+
+		Props: map[string]any{
+			"at":      at,
+			"keys":    keys,
+			"changed": changed,
+		},
+	}
+
+	e.Emit(evt)
+}
@@ -10,6 +10,7 @@ var (
 	ActorParticipant = "participant"
 	ActorSuperUser   = "superuser"
 	ActorJudge       = "judge"
+	ActorSystem      = "system"
 )
 
 var (
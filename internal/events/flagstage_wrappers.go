@@ -0,0 +1,157 @@
+package events
+
+import (
+	"backend/internal/models"
+	"time"
+)
+
+func (e *Emitter) FlagStageScheduled(
+	superuserID string,
+	scheduleID string,
+	stageID string,
+	runAt time.Time,
+) {
+	evt := models.Event{
+		Action: "flagstage.schedule.created",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "flagStageSchedule",
+		TargetID:   scheduleID,
+
+		Props: map[string]any{
+			"stageID": stageID,
+			"runAt":   runAt,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FlagStageRescheduled(
+	superuserID string,
+	scheduleID string,
+	stageID string,
+	oldRunAt time.Time,
+	runAt time.Time,
+) {
+	evt := models.Event{
+		Action: "flagstage.schedule.rescheduled",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "flagStageSchedule",
+		TargetID:   scheduleID,
+
+		Props: map[string]any{
+			"stageID":  stageID,
+			"oldRunAt": oldRunAt,
+			"runAt":    runAt,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FlagStageScheduleCancelled(
+	superuserID string,
+	scheduleID string,
+	stageID string,
+) {
+	evt := models.Event{
+		Action: "flagstage.schedule.cancelled",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "flagStageSchedule",
+		TargetID:   scheduleID,
+
+		Props: map[string]any{
+			"stageID": stageID,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FlagStageScheduleExecuted(
+	schedulerID string,
+	scheduleID string,
+	stageID string,
+	executionID string,
+	runAt time.Time,
+	executedAt time.Time,
+	errMessage string,
+) {
+	evt := models.Event{
+		Action: "flagstage.schedule.executed",
+
+		ActorRole: ActorSystem,
+		ActorID:   schedulerID,
+
+		TargetType: "flagStageSchedule",
+		TargetID:   scheduleID,
+
+		Props: map[string]any{
+			"stageID":     stageID,
+			"executionID": executionID,
+			"runAt":       runAt,
+			"executedAt":  executedAt,
+			"success":     errMessage == "",
+			"error":       errMessage,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FlagStageExecuted(
+	superuserID string,
+	stageID string,
+	executionID string,
+	changes []models.FlagChange,
+) {
+	evt := models.Event{
+		Action: "flagstage.executed",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "flagStage",
+		TargetID:   stageID,
+
+		Props: map[string]any{
+			"executionID": executionID,
+			"changes":     changes,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FlagStageRolledBack(
+	superuserID string,
+	stageID string,
+	executionID string,
+	changes []models.FlagChange,
+) {
+	evt := models.Event{
+		Action: "flagstage.rolledback",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "flagStage",
+		TargetID:   stageID,
+
+		Props: map[string]any{
+			"executionID": executionID,
+			"changes":     changes,
+		},
+	}
+
+	e.Emit(evt)
+}
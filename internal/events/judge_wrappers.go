@@ -188,3 +188,262 @@ func (e *Emitter) JudgmentCreated(
 
 	e.Emit(evt)
 }
+
+func (e *Emitter) JudgmentRetracted(
+	judgeID string,
+	judgmentID string,
+) {
+	evt := models.Event{
+		Action: "judgment.retracted",
+
+		ActorRole: ActorJudge,
+		ActorID:   judgeID,
+
+		TargetType: "judgment",
+		TargetID:   judgmentID,
+
+		Props: nil,
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgmentInvalidated(
+	superuserID string,
+	judgmentID string,
+	reason string,
+) {
+	evt := models.Event{
+		Action: "judgment.invalidated",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "judgment",
+		TargetID:   judgmentID,
+
+		Props: map[string]any{
+			"reason": reason,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) RubricScoreSubmitted(
+	judgeID string,
+	teamID string,
+	scores map[string]int,
+) {
+	evt := models.Event{
+		Action: "judge.rubric.score.submitted",
+
+		ActorRole: ActorJudge,
+		ActorID:   judgeID,
+
+		TargetType: TargetTeam,
+		TargetID:   teamID,
+
+		Props: map[string]any{
+			"scores": scores,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) RubricUpdated(
+	superuserID string,
+	criteria []string,
+) {
+	evt := models.Event{
+		Action: "judging.rubric.updated",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "judging",
+		TargetID:   "judging",
+
+		Props: map[string]any{
+			"criteria": criteria,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgeNoteSaved(
+	judgeID string,
+	teamID string,
+) {
+	evt := models.Event{
+		Action: "judge.note.saved",
+
+		ActorRole: ActorJudge,
+		ActorID:   judgeID,
+
+		TargetType: TargetTeam,
+		TargetID:   teamID,
+
+		Props: nil,
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgeTeamSkipped(
+	judgeID string,
+	teamID string,
+	reason string,
+	queued bool,
+) {
+	evt := models.Event{
+		Action: "judge.team.skipped",
+
+		ActorRole: ActorJudge,
+		ActorID:   judgeID,
+
+		TargetType: TargetTeam,
+		TargetID:   teamID,
+
+		Props: map[string]any{
+			"reason": reason,
+			"queued": queued,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgeConflictDeclared(
+	actorRole string,
+	actorID string,
+	conflict models.JudgeConflict,
+) {
+	evt := models.Event{
+		Action: "judge.conflict.declared",
+
+		ActorRole: actorRole,
+		ActorID:   actorID,
+
+		TargetType: TargetJudge,
+		TargetID:   conflict.JudgeID,
+
+		Props: map[string]any{
+			"conflictID": conflict.ID,
+			"teamID":     conflict.TeamID,
+			"university": conflict.University,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgeConflictRemoved(
+	superuserID string,
+	conflict models.JudgeConflict,
+) {
+	evt := models.Event{
+		Action: "judge.conflict.removed",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: TargetJudge,
+		TargetID:   conflict.JudgeID,
+
+		Props: map[string]any{
+			"conflictID": conflict.ID,
+			"teamID":     conflict.TeamID,
+			"university": conflict.University,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgeWeightSet(
+	superuserID string,
+	judgeID string,
+	weight float64,
+) {
+	evt := models.Event{
+		Action: "judge.weight.set",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: TargetJudge,
+		TargetID:   judgeID,
+
+		Props: map[string]any{
+			"weight": weight,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FinalistsUpdated(
+	superuserID string,
+	teamIDs []string,
+	count int,
+) {
+	evt := models.Event{
+		Action: "finalists.updated",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "judging",
+		TargetID:   "finalists",
+
+		Props: map[string]any{
+			"teams": teamIDs,
+			"count": count,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) FinalistsLockSet(
+	superuserID string,
+	locked bool,
+) {
+	evt := models.Event{
+		Action: "finalists.lock.set",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "judging",
+		TargetID:   "finalists",
+
+		Props: map[string]any{
+			"locked": locked,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) JudgingReplanned(
+	superuserID string,
+	summary map[string]any,
+) {
+	evt := models.Event{
+		Action: "judging.replanned",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "judging",
+		TargetID:   "judging",
+
+		Props: summary,
+	}
+
+	e.Emit(evt)
+}
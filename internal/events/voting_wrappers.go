@@ -0,0 +1,111 @@
+package events
+
+import (
+	"backend/internal/models"
+	"time"
+)
+
+func (e *Emitter) VotingSessionCreated(
+	superuserID string,
+	sessionID string,
+	name string,
+	mode string,
+	opensAt time.Time,
+	closesAt time.Time,
+	finalists []string,
+) {
+	evt := models.Event{
+		Action: "voting.session.created",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "votingSession",
+		TargetID:   sessionID,
+
+		Props: map[string]any{
+			"name":      name,
+			"mode":      mode,
+			"opensAt":   opensAt,
+			"closesAt":  closesAt,
+			"finalists": finalists,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) VotingSessionUpdated(
+	superuserID string,
+	sessionID string,
+	opensAt time.Time,
+	closesAt time.Time,
+	finalists []string,
+) {
+	evt := models.Event{
+		Action: "voting.session.updated",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "votingSession",
+		TargetID:   sessionID,
+
+		Props: map[string]any{
+			"opensAt":   opensAt,
+			"closesAt":  closesAt,
+			"finalists": finalists,
+		},
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) VotingTallyStreamTokenIssued(
+	superuserID string,
+	sessionID string,
+) {
+	evt := models.Event{
+		Action: "voting.tally.stream.token.issued",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "votingSession",
+		TargetID:   sessionID,
+
+		Props: nil,
+	}
+
+	e.Emit(evt)
+}
+
+func (e *Emitter) VotesVoided(
+	superuserID string,
+	sessionID string,
+	voidID string,
+	bucket time.Time,
+	teamID string,
+	votes int64,
+	justification string,
+) {
+	evt := models.Event{
+		Action: "voting.votes.voided",
+
+		ActorRole: ActorSuperUser,
+		ActorID:   superuserID,
+
+		TargetType: "votingSession",
+		TargetID:   sessionID,
+
+		Props: map[string]any{
+			"voidID":        voidID,
+			"bucket":        bucket,
+			"teamID":        teamID,
+			"votes":         votes,
+			"justification": justification,
+		},
+	}
+
+	e.Emit(evt)
+}
@@ -0,0 +1,79 @@
+package judge
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getConflictsHandler lists the judge's declared conflicts of interest.
+// @Summary List judge conflicts
+// @Description Returns the conflicts of interest declared for the authenticated judge, by the judge or by admins.
+// @Tags Judges
+// @Security JudgeAuth
+// @Produce json
+// @Success 200 {array} models.JudgeConflict
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/conflicts [get]
+func getConflictsHandler(c fiber.Ctx) error {
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	conflicts, serr := models.GetJudgeConflicts(judge.ID)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(conflicts)
+}
+
+// declareConflictHandler records a conflict of interest for the judge.
+// @Summary Declare a conflict of interest
+// @Description Declares that the judge should not judge a team, or any team with a member from a university. Conflicted teams are kept out of the judge's group on the next judging init, and judgments involving them are ignored by the scorer.
+// @Tags Judges
+// @Security JudgeAuth
+// @Accept json
+// @Produce json
+// @Param payload body DeclareConflictRequest true "Team ID or university, and reason"
+// @Success 200 {object} models.JudgeConflict
+// @Failure 400 {object} errmsg._JudgeConflictInvalid
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 404 {object} errmsg._TeamNotFound
+// @Failure 409 {object} errmsg._JudgeConflictDuplicate
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/conflicts [post]
+func declareConflictHandler(c fiber.Ctx) error {
+	var body DeclareConflictRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.JudgeConflictInvalid)
+	}
+
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	conflict := models.JudgeConflict{
+		JudgeID:    judge.ID,
+		TeamID:     body.TeamID,
+		University: body.University,
+		Reason:     body.Reason,
+		ActorRole:  events.ActorJudge,
+		ActorID:    judge.ID,
+	}
+	if serr := conflict.Create(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to rebuild leaderboard after conflict %s: %s", conflict.ID, serr.Message)
+	}
+
+	events.Em.JudgeConflictDeclared(events.ActorJudge, judge.ID, conflict)
+
+	return c.JSON(conflict)
+}
@@ -8,6 +8,7 @@ import (
 	"backend/internal/utils"
 	"encoding/json"
 	"fmt"
+	"log"
 
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/bson"
@@ -99,11 +100,11 @@ func nextTeamHandler(c fiber.Ctx) error {
 
 // currentTeamHandler retrieves the currently assigned team for the judge.
 // @Summary Get current team for judging
-// @Description Returns the full team details for the judge's current assignment based on their rotation state.
+// @Description Returns the full team details for the judge's current assignment based on their rotation state, along with the judge's private note on the team if they wrote one.
 // @Tags Judges
 // @Security JudgeAuth
 // @Produce json
-// @Success 200 {object} models.Team
+// @Success 200 {object} TeamWithNoteResponse
 // @Failure 202 {object} errmsg._JudgeResting
 // @Failure 401 {object} errmsg._AccountNoToken
 // @Failure 404 {object} errmsg._TeamNotFound
@@ -129,16 +130,16 @@ func currentTeamHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, errmsg.TeamNotFound)
 	}
 
-	return c.JSON(team)
+	return withJudgeNote(c, judge.ID, team)
 }
 
 // previousTeamHandler retrieves the previous team for the authenticated judge.
 // @Summary Get previous team for judging
-// @Description Returns the previous team ID for the judge to evaluate. Moves backward in the judge's rotation.
+// @Description Returns the previous team ID for the judge to evaluate. Moves backward in the judge's rotation. The judge's private note on the team is included if they wrote one.
 // @Tags Judges
 // @Security JudgeAuth
 // @Produce json
-// @Success 200 {object} models.Team
+// @Success 200 {object} TeamWithNoteResponse
 // @Failure 202 {object} errmsg._JudgeResting
 // @Failure 401 {object} errmsg._AccountNoToken
 // @Failure 500 {object} errmsg._InternalServerError
@@ -167,7 +168,7 @@ func previousTeamHandler(c fiber.Ctx) error {
 		)
 	}
 
-	return c.JSON(team)
+	return withJudgeNote(c, judge.ID, team)
 }
 
 // getTeamHandler retrieves team information by team ID for the authenticated judge.
@@ -199,14 +200,21 @@ func getTeamHandler(c fiber.Ctx) error {
 
 // createJudgmentHandler records a pairwise comparison judgment between two teams.
 // @Summary Create a judgment
-// @Description Records a judgment where a judge compares two teams and selects a winner.
+// @Description Records a judgment where a judge compares their current team against their previous team and selects a winner. Only one judgment is accepted per step, and only once the wait window on the current team has passed.
 // @Tags Judges
 // @Security JudgeAuth
 // @Accept json
 // @Produce json
 // @Param payload body CreateJudgmentRequest true "Judgment details"
 // @Success 200 {object} models.Judgment
+// @Failure 202 {object} errmsg._JudgeResting
+// @Failure 400 {object} errmsg._JudgmentSameTeam
 // @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 403 {object} errmsg._JudgmentTeamNotAssigned
+// @Failure 404 {object} errmsg._TeamNotFound
+// @Failure 409 {object} errmsg._JudgmentDuplicate
+// @Failure 410 {object} errmsg._JudgmentTeamDeleted
+// @Failure 425 {object} errmsg._JudgmentTooEarly
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /judge/judgment [post]
 func createJudgmentHandler(c fiber.Ctx) error {
@@ -227,16 +235,72 @@ func createJudgmentHandler(c fiber.Ctx) error {
 		JudgeID:       judge.ID,
 	}
 
+	if serr := judgment.Validate(judge); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	err := judgment.Create()
 	if err != nil {
+		if models.IsDuplicateJudgment(err) {
+			return utils.StatusError(c, errmsg.JudgmentDuplicate)
+		}
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
+	// The judgment is already stored, so a stale leaderboard is fixed by the next refit
+	if serr := models.ApplyJudgmentToLeaderboard(judgment); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to update leaderboard with judgment %s: %s", judgment.ID, serr.Message)
+	}
+
 	events.Em.JudgmentCreated(judge.ID, judgment.WinningTeamID, judgment.LosingTeamID)
 
 	return c.JSON(judgment)
 }
 
+// retractJudgmentHandler lets a judge take back a judgment they just submitted.
+// @Summary Retract a judgment
+// @Description Marks one of the judge's own judgments as retracted so it no longer counts towards rankings. Only allowed within a short window after submitting; the judge can then submit a corrected judgment for the same step.
+// @Tags Judges
+// @Security JudgeAuth
+// @Accept json
+// @Produce json
+// @Param payload body RetractJudgmentRequest true "Judgment ID"
+// @Success 200 {object} models.Judgment
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 403 {object} errmsg._JudgmentRetractWindowClosed
+// @Failure 404 {object} errmsg._JudgmentNotFound
+// @Failure 409 {object} errmsg._JudgmentNotValid
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/judgment [delete]
+func retractJudgmentHandler(c fiber.Ctx) error {
+	var body struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	if body.ID == "" {
+		return utils.StatusError(c, errmsg.JudgmentNotFound)
+	}
+
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	judgment := models.Judgment{ID: body.ID}
+	if serr := judgment.Retract(judge.ID); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to rebuild leaderboard after retracting judgment %s: %s", judgment.ID, serr.Message)
+	}
+
+	events.Em.JudgmentRetracted(judge.ID, judgment.ID)
+
+	return c.JSON(judgment)
+}
+
 // getAllTeamsHandler retrieves all teams from the database.
 // @Summary Get all teams
 // @Description Returns a list of all teams in the database.
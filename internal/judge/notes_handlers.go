@@ -0,0 +1,98 @@
+package judge
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// withJudgeNote responds with the team and the judge's own note on it, if any.
+func withJudgeNote(c fiber.Ctx, judgeID string, team models.Team) error {
+	note, found, serr := models.GetJudgeNote(judgeID, team.ID)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	response := TeamWithNoteResponse{Team: team}
+	if found {
+		response.Note = &note
+	}
+
+	return c.JSON(response)
+}
+
+// getNotesHandler lists the judge's private notes.
+// @Summary List judge notes
+// @Description Returns the authenticated judge's private notes, most recently updated first. Can be narrowed to one team.
+// @Tags Judges
+// @Security JudgeAuth
+// @Produce json
+// @Param teamID query string false "Only the note for this team"
+// @Success 200 {array} models.JudgeNote
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/notes [get]
+func getNotesHandler(c fiber.Ctx) error {
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	notes, serr := models.GetJudgeNotes(judge.ID, c.Query("teamID"))
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(notes)
+}
+
+// saveNoteHandler creates or updates the judge's private note on a team.
+// @Summary Save a judge note
+// @Description Creates the judge's private note on a team, or replaces its text if one exists. Notes are only visible to the judge and to admins.
+// @Tags Judges
+// @Security JudgeAuth
+// @Accept json
+// @Produce json
+// @Param payload body SaveNoteRequest true "Team ID and note text"
+// @Success 200 {object} models.JudgeNote
+// @Failure 400 {object} errmsg._JudgeNoteTooLong
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 404 {object} errmsg._TeamNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/notes [post]
+func saveNoteHandler(c fiber.Ctx) error {
+	var body struct {
+		TeamID string `json:"teamID"`
+		Text   string `json:"text"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	if body.TeamID == "" {
+		return utils.StatusError(c, errmsg.TeamNotFound)
+	}
+
+	team := models.Team{ID: body.TeamID}
+	if err := team.Get(); err != nil {
+		return utils.StatusError(c, errmsg.TeamNotFound)
+	}
+
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	note := models.JudgeNote{
+		JudgeID: judge.ID,
+		TeamID:  team.ID,
+		Text:    body.Text,
+	}
+	if serr := note.Save(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.JudgeNoteSaved(judge.ID, team.ID)
+
+	return c.JSON(note)
+}
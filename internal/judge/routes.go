@@ -48,4 +48,48 @@ func Routes(r fiber.Router) {
 		models.FlagsMiddlewareBuilder([]string{"judging"}),
 		createJudgmentHandler,
 	)
+	r.Delete("/judgment",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		retractJudgmentHandler,
+	)
+
+	r.Post("/skip",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		skipTeamHandler,
+	)
+
+	r.Get("/rubric",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		getRubricHandler,
+	)
+	r.Post("/rubric-score",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		submitRubricScoreHandler,
+	)
+
+	r.Get("/conflicts",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		getConflictsHandler,
+	)
+	r.Post("/conflicts",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		declareConflictHandler,
+	)
+
+	r.Get("/notes",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		getNotesHandler,
+	)
+	r.Post("/notes",
+		models.JudgeMiddleware,
+		models.FlagsMiddlewareBuilder([]string{"judging"}),
+		saveNoteHandler,
+	)
 }
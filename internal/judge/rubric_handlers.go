@@ -0,0 +1,109 @@
+package judge
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getRubricHandler returns the rubric and the judge's scores for their current team.
+// @Summary Get rubric for the current team
+// @Description Returns the judging rubric along with the current team ID and any scores the judge already gave that team.
+// @Tags Judges
+// @Security JudgeAuth
+// @Produce json
+// @Success 200 {object} RubricResponse
+// @Failure 202 {object} errmsg._JudgeResting
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 410 {object} errmsg._JudgingFinished
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/rubric [get]
+func getRubricHandler(c fiber.Ctx) error {
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	rubric, serr := models.GetRubric()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teamID, serr := judge.GetCurrentTeamID()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	response := RubricResponse{
+		Rubric: rubric,
+		TeamID: teamID,
+	}
+
+	score, found, serr := models.GetRubricScore(judge.ID, teamID)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	if found {
+		response.Score = &score
+	}
+
+	return c.JSON(response)
+}
+
+// submitRubricScoreHandler records the judge's rubric scores for their current team.
+// @Summary Submit rubric scores
+// @Description Scores the judge's current team on every rubric criterion. Submitting again while on the same team replaces the earlier scores.
+// @Tags Judges
+// @Security JudgeAuth
+// @Accept json
+// @Produce json
+// @Param payload body SubmitRubricScoreRequest true "Score per criterion ID"
+// @Success 200 {object} models.RubricScore
+// @Failure 202 {object} errmsg._JudgeResting
+// @Failure 400 {object} errmsg._RubricScoreInvalid
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 410 {object} errmsg._JudgingFinished
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/rubric-score [post]
+func submitRubricScoreHandler(c fiber.Ctx) error {
+	var body struct {
+		Scores map[string]int `json:"scores"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.RubricScoreInvalid)
+	}
+
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	rubric, serr := models.GetRubric()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teamID, serr := judge.GetCurrentTeamID()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	score := models.RubricScore{
+		JudgeID: judge.ID,
+		TeamID:  teamID,
+		Step:    judge.CurrentTeam,
+		Scores:  body.Scores,
+	}
+
+	if serr := score.Validate(rubric); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := score.Save(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.RubricScoreSubmitted(judge.ID, teamID, score.Scores)
+
+	return c.JSON(score)
+}
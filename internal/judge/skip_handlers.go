@@ -0,0 +1,52 @@
+package judge
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// skipTeamHandler skips the judge's current team.
+// @Summary Skip the current team
+// @Description Records that the judge could not evaluate their current team. Reasons are absent, conflict (of interest) or technical. Absent and technical skips queue the team to be retried once, on a later rest step or after the rotation ends. A skipped team cannot be used in a judgment for that visit.
+// @Tags Judges
+// @Security JudgeAuth
+// @Accept json
+// @Produce json
+// @Param payload body SkipTeamRequest true "Skip reason"
+// @Success 200 {object} models.JudgeSkip
+// @Failure 202 {object} errmsg._JudgeResting
+// @Failure 400 {object} errmsg._JudgeSkipInvalidReason
+// @Failure 401 {object} errmsg._AccountNoToken
+// @Failure 409 {object} errmsg._JudgeSkipDuplicate
+// @Failure 410 {object} errmsg._JudgingFinished
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /judge/skip [post]
+func skipTeamHandler(c fiber.Ctx) error {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.JudgeSkipInvalidReason)
+	}
+
+	if !models.IsValidSkipReason(body.Reason) {
+		return utils.StatusError(c, errmsg.JudgeSkipInvalidReason)
+	}
+
+	judge := models.Judge{}
+	utils.GetLocals(c, "judge", &judge)
+
+	skip, serr := judge.SkipCurrentTeam(body.Reason)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.JudgeTeamSkipped(judge.ID, skip.TeamID, skip.Reason, skip.Queued)
+
+	return c.JSON(skip)
+}
@@ -27,8 +27,49 @@ type CreateJudgmentRequest struct {
 	LosingTeamID  string `json:"losingTeamID" example:"team_002"`
 }
 
+// RetractJudgmentRequest identifies the judgment a judge wants to take back.
+type RetractJudgmentRequest struct {
+	ID string `json:"id" example:"abc123"`
+}
+
+// RubricResponse returns the rubric with the judge's scores for their current team, if any.
+type RubricResponse struct {
+	Rubric models.Rubric       `json:"rubric"`
+	TeamID string              `json:"teamID" example:"team_001"`
+	Score  *models.RubricScore `json:"score,omitempty"`
+}
+
+// SubmitRubricScoreRequest contains a score for every rubric criterion.
+type SubmitRubricScoreRequest struct {
+	Scores map[string]int `json:"scores"`
+}
+
+// TeamWithNoteResponse returns a team together with the judge's private note on it, if any.
+type TeamWithNoteResponse struct {
+	models.Team
+	Note *models.JudgeNote `json:"note,omitempty"`
+}
+
+// SaveNoteRequest contains the team a note is about and its text.
+type SaveNoteRequest struct {
+	TeamID string `json:"teamID" example:"team_001"`
+	Text   string `json:"text" example:"Strong demo, unclear business model"`
+}
+
+// SkipTeamRequest contains why the judge is skipping their current team.
+type SkipTeamRequest struct {
+	Reason string `json:"reason" example:"absent" enums:"absent,conflict,technical"`
+}
+
 // JudgeInfoResponse returns the judge's current progress and timing information.
 type JudgeInfoResponse struct {
 	CurrentTeam  int       `json:"currentTeam" example:"0"`
 	NextTeamTime time.Time `json:"nextTeamTime" example:"2024-01-01T12:05:00Z"`
 }
+
+// DeclareConflictRequest names either a team or a university
+type DeclareConflictRequest struct {
+	TeamID     string `json:"teamID,omitempty" example:"team_001"`
+	University string `json:"university,omitempty" example:"Example University"`
+	Reason     string `json:"reason" example:"mentored this team"`
+}
@@ -6,6 +6,7 @@ import (
 	"backend/internal/errmsg"
 	"backend/internal/utils"
 	"encoding/json"
+	"slices"
 	"strings"
 	"time"
 
@@ -44,9 +45,16 @@ type Account struct {
 
 	Present bool `json:"present" bson:"present"`
 
+	// when staff last scanned the account's badge
+	LastScannedAt time.Time `json:"lastScannedAt" bson:"lastScannedAt"`
+
 	TeamID string `json:"teamID" bson:"teamID"`
 
-	HasVoted bool `json:"hasVoted" bson:"hasVoted"`
+	// IDs of the voting sessions this account has voted in
+	VotedSessions []string `json:"votedSessions" bson:"votedSessions"`
+	// whether the account voted in the current voting session, for clients from
+	// before there were sessions; derived by LoadHasVoted, never stored
+	HasVoted bool `json:"hasVoted" bson:"-"`
 
 	Promotionals map[string]string `json:"promotionals" bson:"promotionals"`
 }
@@ -126,9 +134,11 @@ func (acc *Account) Initialize() (serr errmsg.StatusError) {
 		return errmsg.AccountAlreadyInitialized
 	}
 
-	acc.ID = utils.GenID(6)
-
-	_, err := db.Accounts.InsertOne(db.Ctx, acc)
+	err := WithNewID(func(ID string) error {
+		acc.ID = ID
+		_, err := db.Accounts.InsertOne(db.Ctx, acc)
+		return err
+	})
 	if err != nil {
 		return errmsg.InternalServerError(err)
 	}
@@ -296,6 +306,26 @@ func (acc *Account) UpdatePresent(pres bool) (err error) {
 	return
 }
 
+func (acc *Account) MarkScanned(at time.Time) (err error) {
+	_, err = db.Accounts.UpdateOne(db.Ctx, bson.M{
+		"id": acc.ID,
+	}, bson.M{
+		"$set": bson.M{
+			"lastScannedAt": at,
+		},
+	})
+
+	if err != nil {
+		return
+	}
+
+	acc.LastScannedAt = at
+
+	cacheAccount(acc)
+
+	return
+}
+
 func (acc *Account) SetCheckedIn(checkedIn bool) (err error) {
 	_, err = db.Accounts.UpdateOne(db.Ctx, bson.M{
 		"id": acc.ID,
@@ -356,23 +386,66 @@ func (acc *Account) RemoveFromTeam(teamID string) (err error) {
 	return
 }
 
-func (acc *Account) SetHasVoted() (err error) {
-	_, err = db.Accounts.UpdateOne(db.Ctx, bson.M{
-		"id": acc.ID,
+// MarkVoted records that the account voted in the session. It reports false if
+// the account had already voted there, so concurrent votes can't both count.
+func (acc *Account) MarkVoted(sessionID string) (marked bool, err error) {
+	result, err := db.Accounts.UpdateOne(db.Ctx, bson.M{
+		"id":            acc.ID,
+		"votedSessions": bson.M{"$ne": sessionID},
 	}, bson.M{
-		"$set": bson.M{
-			"hasVoted": true,
+		"$addToSet": bson.M{
+			"votedSessions": sessionID,
 		},
 	})
 
 	if err != nil {
 		return
 	}
+	if result.ModifiedCount == 0 {
+		return false, nil
+	}
 
-	acc.HasVoted = true
+	acc.VotedSessions = append(acc.VotedSessions, sessionID)
 	cacheAccount(acc)
 
-	return
+	return true, nil
+}
+
+// UnmarkVoted takes back MarkVoted, for when the vote it claimed could not be stored
+func (acc *Account) UnmarkVoted(sessionID string) error {
+	_, err := db.Accounts.UpdateOne(db.Ctx, bson.M{
+		"id": acc.ID,
+	}, bson.M{
+		"$pull": bson.M{
+			"votedSessions": sessionID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	acc.VotedSessions = slices.DeleteFunc(acc.VotedSessions, func(ID string) bool { return ID == sessionID })
+	cacheAccount(acc)
+
+	return nil
+}
+
+// VotedIn reports whether the account has voted in the session
+func (acc Account) VotedIn(sessionID string) bool {
+	return slices.Contains(acc.VotedSessions, sessionID)
+}
+
+// LoadHasVoted derives HasVoted from the voting session participants currently
+// see: the open one, otherwise the next or the last one
+func (acc *Account) LoadHasVoted() errmsg.StatusError {
+	session, found, serr := CurrentVotingSession(time.Now())
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	acc.HasVoted = found && acc.VotedIn(session.ID)
+
+	return errmsg.EmptyStatusError
 }
 
 func cacheAccount(acc *Account) {
@@ -0,0 +1,397 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ConfigKindFlag = "flag"
+var ConfigKindRule = "rule"
+var ConfigKindStage = "stage"
+var ConfigKindSetting = "setting"
+
+// ConfigKeyStage is the key the current flag stage is recorded under
+var ConfigKeyStage = "stage"
+
+// ConfigChange is one version of a flag, flag rule, the flag stage or a
+// setting: its value before and after a change, who made it, when, and through
+// what. Restoring reads the history back, so entries are never edited or removed.
+type ConfigChange struct {
+	ID      string `json:"id" bson:"id"`
+	Kind    string `json:"kind" bson:"kind"`       // flag, rule, stage or setting
+	Key     string `json:"key" bson:"key"`         // the flag or setting name, or stage
+	Existed bool   `json:"existed" bson:"existed"` // false when the change added the key
+	Old     any    `json:"old" bson:"old"`
+	Removed bool   `json:"removed" bson:"removed"` // true when the change removed the key
+	New     any    `json:"new" bson:"new"`
+	// what made the change, such as a flag stage being executed
+	Source    string    `json:"source" bson:"source"`
+	ChangedBy string    `json:"changedBy" bson:"changedBy"`
+	ChangedAt time.Time `json:"changedAt" bson:"changedAt"`
+}
+
+// ConfigHistoryFilter narrows the history; empty fields match everything
+type ConfigHistoryFilter struct {
+	Kind string
+	Key  string
+	From time.Time
+	To   time.Time
+}
+
+// changed reports whether the change did anything
+func (cc ConfigChange) changed() bool {
+	if !cc.Existed {
+		// removing a key that isn't there does nothing
+		return !cc.Removed
+	}
+	if cc.Removed {
+		return true
+	}
+
+	return !reflect.DeepEqual(cc.Old, cc.New)
+}
+
+// recordConfigChanges adds the changes that did anything to the history, all
+// at the same time
+func recordConfigChanges(changes []ConfigChange, changedBy string, source string) error {
+	now := time.Now()
+	for _, change := range changes {
+		if !change.changed() {
+			continue
+		}
+
+		change.Source = source
+		change.ChangedBy = changedBy
+		change.ChangedAt = now
+
+		err := WithNewID(func(ID string) error {
+			change.ID = ID
+			_, err := db.ConfigHistory.InsertOne(db.Ctx, change)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flagChanges lists what setting and unsetting flags does to the flags before,
+// sorted by flag
+func flagChanges(before map[string]bool, set map[string]bool, unset []string) []ConfigChange {
+	return keyedChanges(ConfigKindFlag, before, set, unset)
+}
+
+// ruleChanges lists what setting and unsetting rules does to the rules before,
+// sorted by flag
+func ruleChanges(before map[string]FlagRule, set map[string]FlagRule, unset []string) []ConfigChange {
+	return keyedChanges(ConfigKindRule, before, set, unset)
+}
+
+// stageChanges lists the change of stage, if the stage changed
+func stageChanges(before FlagStage, after FlagStage) []ConfigChange {
+	change := ConfigChange{Kind: ConfigKindStage, Key: ConfigKeyStage, Existed: true, Old: before, New: after}
+	if !change.changed() {
+		return []ConfigChange{}
+	}
+
+	return []ConfigChange{change}
+}
+
+// keyedChanges lists what setting and unsetting keys of a kind does to the
+// values before, sorted by key
+func keyedChanges[V any](kind string, before map[string]V, set map[string]V, unset []string) []ConfigChange {
+	changes := []ConfigChange{}
+	for key, value := range set {
+		change := ConfigChange{Kind: kind, Key: key, New: value}
+		if old, ok := before[key]; ok {
+			change.Existed = true
+			change.Old = old
+		}
+		changes = append(changes, change)
+	}
+
+	for _, key := range unset {
+		if old, ok := before[key]; ok {
+			changes = append(changes, ConfigChange{Kind: kind, Key: key, Existed: true, Old: old, Removed: true})
+		}
+	}
+
+	changes = slices.DeleteFunc(changes, func(c ConfigChange) bool { return !c.changed() })
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// recordFlagChanges adds what an update did to the flags, given the flags
+// before it, to the history
+func recordFlagChanges(before map[string]bool, set map[string]bool, unset []string, changedBy string, source string) error {
+	return recordConfigChanges(flagChanges(before, set, unset), changedBy, source)
+}
+
+// decodeConfigValue reads a value back from the history into out. Rules and
+// stages come back from Mongo as documents, so they go through BSON again.
+func decodeConfigValue(value any, out any) error {
+	bytes, err := bson.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return bson.Unmarshal(bytes, out)
+}
+
+// GetConfigHistory lists the recorded changes matching the filter, latest first
+func GetConfigHistory(filter ConfigHistoryFilter) ([]ConfigChange, errmsg.StatusError) {
+	query := bson.M{}
+	if filter.Kind != "" {
+		query["kind"] = filter.Kind
+	}
+	if filter.Key != "" {
+		query["key"] = filter.Key
+	}
+
+	changedAt := bson.M{}
+	if !filter.From.IsZero() {
+		changedAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		changedAt["$lte"] = filter.To
+	}
+	if len(changedAt) > 0 {
+		query["changedAt"] = changedAt
+	}
+
+	cursor, err := db.ConfigHistory.Find(db.Ctx, query,
+		options.Find().SetSort(bson.D{{Key: "changedAt", Value: -1}, {Key: "key", Value: 1}}),
+	)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	history := []ConfigChange{}
+	if err = cursor.All(db.Ctx, &history); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return history, errmsg.EmptyStatusError
+}
+
+// configStateAt finds the value every key of the kind changed after at had at
+// that time: the value before its earliest change since
+func configStateAt(at time.Time, kind string, keys []string) ([]ConfigChange, errmsg.StatusError) {
+	query := bson.M{"kind": kind, "changedAt": bson.M{"$gt": at}}
+	if len(keys) > 0 {
+		query["key"] = bson.M{"$in": keys}
+	}
+
+	cursor, err := db.ConfigHistory.Find(db.Ctx, query,
+		options.Find().SetSort(bson.D{{Key: "changedAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	var since []ConfigChange
+	if err = cursor.All(db.Ctx, &since); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	earliest := []ConfigChange{}
+	for _, change := range since {
+		if !slices.ContainsFunc(earliest, func(c ConfigChange) bool { return c.Key == change.Key }) {
+			earliest = append(earliest, change)
+		}
+	}
+
+	sort.Slice(earliest, func(i, j int) bool {
+		return earliest[i].Key < earliest[j].Key
+	})
+
+	return earliest, errmsg.EmptyStatusError
+}
+
+// RestoreConfig puts the flags, their rules and stage, and the settings back the
+// way they were at a point in time by undoing every change recorded since. kind
+// and keys narrow what is restored. The restore is recorded as changes of its
+// own, so it can be undone the same way. With dryRun nothing is changed and the
+// changes it would make are returned.
+func RestoreConfig(at time.Time, kind string, keys []string, restoredBy string, dryRun bool) ([]ConfigChange, errmsg.StatusError) {
+	source := "restored to " + at.UTC().Format(time.RFC3339)
+	restored := []ConfigChange{}
+
+	if kind != ConfigKindSetting {
+		changes, serr := restoreFlags(at, kind, keys, restoredBy, source, dryRun)
+		if serr != errmsg.EmptyStatusError {
+			return nil, serr
+		}
+		restored = append(restored, changes...)
+	}
+
+	if kind == "" || kind == ConfigKindSetting {
+		changes, serr := restoreSettings(at, keys, restoredBy, source, dryRun)
+		if serr != errmsg.EmptyStatusError {
+			return nil, serr
+		}
+		restored = append(restored, changes...)
+	}
+
+	for i := range restored {
+		restored[i].Source = source
+		restored[i].ChangedBy = restoredBy
+	}
+
+	return restored, errmsg.EmptyStatusError
+}
+
+// restoreFlags restores the flags, rules and stage of the kind, or all three
+// when kind is empty
+func restoreFlags(at time.Time, kind string, keys []string, restoredBy string, source string, dryRun bool) ([]ConfigChange, errmsg.StatusError) {
+	flags := Flags{}
+	if err := flags.Get(); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	set := map[string]bool{}
+	unset := []string{}
+	if kind == "" || kind == ConfigKindFlag {
+		state, serr := configStateAt(at, ConfigKindFlag, keys)
+		if serr != errmsg.EmptyStatusError {
+			return nil, serr
+		}
+
+		checked := map[string]bool{}
+		for _, past := range state {
+			if past.Existed {
+				value, _ := past.Old.(bool)
+				set[past.Key] = value
+				checked[past.Key] = value
+			} else {
+				unset = append(unset, past.Key)
+				checked[past.Key] = false
+			}
+		}
+
+		if problems := FlagsRegistry.Check(flags.Flags, checked); len(problems) > 0 {
+			return nil, errmsg.FlagInconsistent(problems)
+		}
+	}
+
+	setRules := map[string]FlagRule{}
+	unsetRules := []string{}
+	if kind == "" || kind == ConfigKindRule {
+		state, serr := configStateAt(at, ConfigKindRule, keys)
+		if serr != errmsg.EmptyStatusError {
+			return nil, serr
+		}
+
+		for _, past := range state {
+			if !past.Existed {
+				unsetRules = append(unsetRules, past.Key)
+				continue
+			}
+
+			var rule FlagRule
+			if err := decodeConfigValue(past.Old, &rule); err != nil {
+				return nil, errmsg.InternalServerError(err)
+			}
+			setRules[past.Key] = rule
+		}
+	}
+
+	stage := flags.Stage
+	if kind == "" || kind == ConfigKindStage {
+		state, serr := configStateAt(at, ConfigKindStage, keys)
+		if serr != errmsg.EmptyStatusError {
+			return nil, serr
+		}
+
+		if len(state) > 0 {
+			stage = FlagStage{}
+			if err := decodeConfigValue(state[0].Old, &stage); err != nil {
+				return nil, errmsg.InternalServerError(err)
+			}
+		}
+	}
+
+	flagsChanged := flagChanges(flags.Flags, set, unset)
+	rulesChanged := ruleChanges(flags.Rules, setRules, unsetRules)
+	stageChanged := stageChanges(flags.Stage, stage)
+
+	changes := append(append(flagsChanged, rulesChanged...), stageChanged...)
+	if dryRun {
+		return changes, errmsg.EmptyStatusError
+	}
+
+	if len(flagsChanged) > 0 {
+		if err := flags.apply(set, unset, restoredBy, source); err != nil {
+			return nil, errmsg.InternalServerError(err)
+		}
+	}
+	if len(rulesChanged) > 0 {
+		if err := flags.applyRules(setRules, unsetRules, restoredBy, source); err != nil {
+			return nil, errmsg.InternalServerError(err)
+		}
+	}
+	if len(stageChanged) > 0 {
+		if err := flags.SetStage(stage, restoredBy, source); err != nil {
+			return nil, errmsg.InternalServerError(err)
+		}
+	}
+
+	return changes, errmsg.EmptyStatusError
+}
+
+func restoreSettings(at time.Time, keys []string, restoredBy string, source string, dryRun bool) ([]ConfigChange, errmsg.StatusError) {
+	state, serr := configStateAt(at, ConfigKindSetting, keys)
+	if serr != errmsg.EmptyStatusError {
+		return nil, serr
+	}
+
+	changes := []ConfigChange{}
+	for _, past := range state {
+		current := Setting{Name: past.Key}
+		serr := current.Get()
+		if serr != errmsg.EmptyStatusError && serr != errmsg.SettingNotFound {
+			return nil, serr
+		}
+
+		change := ConfigChange{
+			Kind:    ConfigKindSetting,
+			Key:     past.Key,
+			Existed: serr == errmsg.EmptyStatusError,
+			Old:     current.Value,
+			Removed: !past.Existed,
+			New:     past.Old,
+		}
+		if !change.changed() {
+			continue
+		}
+		changes = append(changes, change)
+
+		if dryRun {
+			continue
+		}
+
+		setting := Setting{Name: past.Key, Value: past.Old}
+		if change.Removed {
+			serr = setting.delete(restoredBy, source)
+		} else {
+			serr = setting.save(restoredBy, source)
+		}
+		if serr != errmsg.EmptyStatusError {
+			return nil, serr
+		}
+	}
+
+	return changes, errmsg.EmptyStatusError
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFlagChanges(t *testing.T) {
+	before := map[string]bool{"judging": true, "voting": false, "teams_read": true}
+
+	changes := flagChanges(before,
+		map[string]bool{"judging": false, "voting": false, "submissions_read": true},
+		[]string{"teams_read", "missing"},
+	)
+
+	require.Equal(t, []ConfigChange{
+		{Kind: ConfigKindFlag, Key: "judging", Existed: true, Old: true, New: false},
+		{Kind: ConfigKindFlag, Key: "submissions_read", Existed: false, New: true},
+		{Kind: ConfigKindFlag, Key: "teams_read", Existed: true, Old: true, Removed: true},
+	}, changes, "flags set to the value they had and removing missing flags are left out")
+}
+
+func TestRuleAndStageChanges(t *testing.T) {
+	before := map[string]FlagRule{"judging": {Percentage: 10}, "voting": {TeamIDs: []string{"team_1"}}}
+
+	changes := ruleChanges(before,
+		map[string]FlagRule{"judging": {Percentage: 20}, "voting": {TeamIDs: []string{"team_1"}}},
+		[]string{"missing"},
+	)
+	require.Equal(t, []ConfigChange{
+		{Kind: ConfigKindRule, Key: "judging", Existed: true, Old: FlagRule{Percentage: 10}, New: FlagRule{Percentage: 20}},
+	}, changes)
+
+	stage := FlagStage{ID: "stage_1", Name: "judging"}
+	require.Empty(t, stageChanges(stage, stage), "executing the current stage again")
+	require.Equal(t, []ConfigChange{
+		{Kind: ConfigKindStage, Key: ConfigKeyStage, Existed: true, Old: FlagStage{}, New: stage},
+	}, stageChanges(FlagStage{}, stage))
+}
+
+func TestDecodeConfigValue(t *testing.T) {
+	rule := FlagRule{AccountIDs: []string{"acc_1"}, Universities: []string{"Politehnica Bucharest"}, Percentage: 25}
+
+	// what a rule reads back as from the history
+	bytes, err := bson.Marshal(bson.M{"old": rule})
+	require.NoError(t, err)
+	var stored bson.M
+	require.NoError(t, bson.Unmarshal(bytes, &stored))
+
+	var decoded FlagRule
+	require.NoError(t, decodeConfigValue(stored["old"], &decoded))
+	require.Equal(t, rule, decoded)
+}
+
+func TestConfigChangeChanged(t *testing.T) {
+	require.False(t, ConfigChange{Existed: true, Old: "5", New: "5"}.changed())
+	require.True(t, ConfigChange{Existed: true, Old: "5", New: "0"}.changed())
+	require.True(t, ConfigChange{Existed: false, New: "5"}.changed(), "adding a setting")
+	require.True(t, ConfigChange{Existed: true, Old: "5", Removed: true}.changed())
+	require.False(t, ConfigChange{Existed: false, Removed: true}.changed())
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"backend/internal/errmsg"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var FinalistsSourceComputed = "computed"
+var FinalistsSourceManual = "manual"
+
+// DefaultFinalistCount is how many teams reach the final until a superuser changes it
+var DefaultFinalistCount = 5
+
+// MaxFinalistCount bounds the configurable finalist count
+var MaxFinalistCount = 20
+
+// Finalists is the ordered finalist list, stored in SettingFinalists. Computing
+// rankings fills it with the top Count teams unless it is locked; superusers can
+// override and reorder it at any time until they lock it.
+type Finalists struct {
+	Count     int       `json:"count"`
+	Teams     []string  `json:"teams"`
+	Locked    bool      `json:"locked"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UpdatedBy string    `json:"updatedBy"`
+}
+
+// GetFinalists returns the finalist list, or an empty one of the default size if none was saved
+func GetFinalists() (finalists Finalists, serr errmsg.StatusError) {
+	setting := &Setting{Name: SettingFinalists}
+	serr = setting.Get()
+	if serr == errmsg.SettingNotFound {
+		return Finalists{Count: DefaultFinalistCount, Teams: []string{}}, errmsg.EmptyStatusError
+	}
+	if serr != errmsg.EmptyStatusError {
+		return finalists, serr
+	}
+
+	value, ok := setting.Value.(string)
+	if !ok {
+		return finalists, errmsg.InternalServerError(errors.New("finalists setting is not a string"))
+	}
+	if err := json.Unmarshal([]byte(value), &finalists); err != nil {
+		return finalists, errmsg.InternalServerError(err)
+	}
+	if finalists.Teams == nil {
+		finalists.Teams = []string{}
+	}
+
+	return finalists, errmsg.EmptyStatusError
+}
+
+// Validate checks the count and that the teams are distinct, existing teams that fit in it
+func (f Finalists) Validate() errmsg.StatusError {
+	if f.Count < 1 || f.Count > MaxFinalistCount || len(f.Teams) > f.Count {
+		return errmsg.FinalistsInvalid
+	}
+
+	for i, teamID := range f.Teams {
+		if slices.Contains(f.Teams[:i], teamID) {
+			return errmsg.FinalistsInvalid
+		}
+
+		team := Team{ID: teamID}
+		if err := team.Get(); err != nil || team.Deleted {
+			return errmsg.TeamNotFound
+		}
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// SaveFinalists validates and stores the finalist list
+func SaveFinalists(f Finalists) errmsg.StatusError {
+	if serr := f.Validate(); serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	f.UpdatedAt = time.Now()
+
+	finalistsJSON, err := json.Marshal(f)
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	setting := &Setting{Name: SettingFinalists, Value: string(finalistsJSON)}
+	return setting.Save(f.UpdatedBy)
+}
+
+// SetComputedFinalists replaces the finalists with the top of a fresh ranking.
+// Teams deleted since they were judged are passed over for the next ones down.
+// Locked finalists are kept as they are; the returned list is the one in effect.
+func SetComputedFinalists(rankedTeams []string, actorID string) (Finalists, errmsg.StatusError) {
+	finalists, serr := GetFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return finalists, serr
+	}
+	if finalists.Locked {
+		return finalists, errmsg.EmptyStatusError
+	}
+
+	teams, err := topTeams(rankedTeams, finalists.Count, func(teamID string) (bool, error) {
+		team := Team{ID: teamID}
+		err := team.Get()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return err == nil && !team.Deleted, err
+	})
+	if err != nil {
+		return finalists, errmsg.InternalServerError(err)
+	}
+
+	finalists.Teams = teams
+	finalists.Source = FinalistsSourceComputed
+	finalists.UpdatedBy = actorID
+
+	return finalists, SaveFinalists(finalists)
+}
+
+// topTeams returns the first count ranked teams that are still competing
+func topTeams(rankedTeams []string, count int, competing func(teamID string) (bool, error)) ([]string, error) {
+	teams := []string{}
+	for _, teamID := range rankedTeams {
+		if len(teams) == count {
+			break
+		}
+
+		ok, err := competing(teamID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			teams = append(teams, teamID)
+		}
+	}
+
+	return teams, nil
+}
+
+// IsFinalist reports whether the team is one of the current finalists
+func (f Finalists) IsFinalist(teamID string) bool {
+	return teamID != "" && slices.Contains(f.Teams, teamID)
+}
+
+// LoadTeams fetches the finalist teams in finalist order
+func (f Finalists) LoadTeams() (teams []Team, serr errmsg.StatusError) {
+	teams = make([]Team, 0, len(f.Teams))
+	for _, teamID := range f.Teams {
+		team := Team{ID: teamID}
+		if err := team.Get(); err != nil {
+			return nil, errmsg.InternalServerError(err)
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, errmsg.EmptyStatusError
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopTeams(t *testing.T) {
+	ranked := []string{"a", "deleted", "b", "c", "d"}
+	competing := func(teamID string) (bool, error) {
+		return teamID != "deleted", nil
+	}
+
+	teams, err := topTeams(ranked, 3, competing)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, teams, "a deleted team is passed over for the next one down")
+
+	teams, err = topTeams(ranked, 10, competing)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c", "d"}, teams, "fewer teams than the count")
+
+	failure := errors.New("unreachable")
+	_, err = topTeams(ranked, 3, func(string) (bool, error) { return false, failure })
+	require.ErrorIs(t, err, failure)
+}
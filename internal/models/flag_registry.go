@@ -0,0 +1,264 @@
+package models
+
+import (
+	"backend/internal/errmsg"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// FlagDefinition describes a flag in the registry. A flag can only be on while
+// every flag it requires is on and every flag it conflicts with is off.
+type FlagDefinition struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Requires    []string `json:"requires"`
+	Conflicts   []string `json:"conflicts"`
+}
+
+// FlagRegistry lists every flag the backend knows, as declared in flags_config.json
+type FlagRegistry struct {
+	Flags []FlagDefinition `json:"flags"`
+
+	byName     map[string]FlagDefinition
+	dependents map[string][]string // the flags requiring each flag
+	conflicts  map[string][]string // conflicts go both ways
+}
+
+// FlagsRegistry is the registry loaded at startup
+var FlagsRegistry FlagRegistry
+
+// LoadFlagRegistry parses a registry and checks that it can be satisfied: every
+// flag it mentions is declared, and no flag needs, directly or not, two flags
+// that conflict.
+func LoadFlagRegistry(data []byte) (registry FlagRegistry, err error) {
+	if err = json.Unmarshal(data, &registry); err != nil {
+		return registry, fmt.Errorf("flag registry: %w", err)
+	}
+
+	registry.byName = map[string]FlagDefinition{}
+	registry.dependents = map[string][]string{}
+	registry.conflicts = map[string][]string{}
+
+	for _, def := range registry.Flags {
+		if def.Name == "" {
+			return registry, fmt.Errorf("flag registry: a flag has no name")
+		}
+		if _, ok := registry.byName[def.Name]; ok {
+			return registry, fmt.Errorf("flag registry: %s is declared twice", def.Name)
+		}
+		registry.byName[def.Name] = def
+	}
+
+	for _, def := range registry.Flags {
+		for _, required := range def.Requires {
+			if _, ok := registry.byName[required]; !ok || required == def.Name {
+				return registry, fmt.Errorf("flag registry: %s requires %s, which is not another declared flag", def.Name, required)
+			}
+			registry.dependents[required] = append(registry.dependents[required], def.Name)
+		}
+
+		for _, conflict := range def.Conflicts {
+			if _, ok := registry.byName[conflict]; !ok || conflict == def.Name {
+				return registry, fmt.Errorf("flag registry: %s conflicts with %s, which is not another declared flag", def.Name, conflict)
+			}
+			if !slices.Contains(registry.conflicts[def.Name], conflict) {
+				registry.conflicts[def.Name] = append(registry.conflicts[def.Name], conflict)
+				registry.conflicts[conflict] = append(registry.conflicts[conflict], def.Name)
+			}
+		}
+	}
+
+	for _, def := range registry.Flags {
+		needed := registry.requirements(def.Name)
+		for flag := range needed {
+			for _, conflict := range registry.conflicts[flag] {
+				if needed[conflict] {
+					return registry, fmt.Errorf("flag registry: %s can never be on, it needs both %s and %s", def.Name, flag, conflict)
+				}
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// requirements collects the flag and every flag it needs, directly or not
+func (r FlagRegistry) requirements(flag string) map[string]bool {
+	needed := map[string]bool{}
+	pending := []string{flag}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		if needed[current] {
+			continue
+		}
+		needed[current] = true
+		pending = append(pending, r.byName[current].Requires...)
+	}
+
+	return needed
+}
+
+// Unknown lists the flags that are not in the registry, sorted and without duplicates
+func (r FlagRegistry) Unknown(flags []string) []string {
+	unknown := []string{}
+	for _, flag := range flags {
+		if _, ok := r.byName[flag]; !ok && !slices.Contains(unknown, flag) {
+			unknown = append(unknown, flag)
+		}
+	}
+	slices.Sort(unknown)
+
+	return unknown
+}
+
+// Check lists the dependencies and conflicts setting the changes on top of the
+// current flags would break. Only problems involving a changed flag are
+// reported, so an inconsistency that is already there doesn't block unrelated
+// changes. Flags missing from current count as off.
+func (r FlagRegistry) Check(current map[string]bool, changes map[string]bool) []string {
+	state := make(map[string]bool, len(current)+len(changes))
+	for flag, value := range current {
+		state[flag] = value
+	}
+	for flag, value := range changes {
+		state[flag] = value
+	}
+
+	return r.check(state, changes, false)
+}
+
+// Contradictions lists the dependencies and conflicts the changes break among
+// themselves, whatever the other flags are
+func (r FlagRegistry) Contradictions(changes map[string]bool) []string {
+	return r.check(changes, changes, true)
+}
+
+// check compares every changed flag with the flags it is related to. When
+// partial, flags missing from state are unknown rather than off and are skipped.
+func (r FlagRegistry) check(state map[string]bool, changes map[string]bool, partial bool) []string {
+	isSet := func(flag string, value bool) bool {
+		current, ok := state[flag]
+		return (ok || !partial) && current == value
+	}
+
+	problems := []string{}
+	add := func(problem string) {
+		if !slices.Contains(problems, problem) {
+			problems = append(problems, problem)
+		}
+	}
+
+	for flag, value := range changes {
+		def, ok := r.byName[flag]
+		if !ok {
+			continue
+		}
+
+		if value {
+			for _, required := range def.Requires {
+				if isSet(required, false) {
+					add(fmt.Sprintf("%s requires %s", flag, required))
+				}
+			}
+			for _, conflict := range r.conflicts[flag] {
+				if isSet(conflict, true) {
+					pair := []string{flag, conflict}
+					slices.Sort(pair)
+					add(fmt.Sprintf("%s conflicts with %s", pair[0], pair[1]))
+				}
+			}
+		} else {
+			for _, dependent := range r.dependents[flag] {
+				if isSet(dependent, true) {
+					add(fmt.Sprintf("%s requires %s", dependent, flag))
+				}
+			}
+		}
+	}
+	slices.Sort(problems)
+
+	return problems
+}
+
+// Resolve extends the changes so that they keep the flags consistent: turning
+// a flag on turns on the flags it requires and turns off the ones it conflicts
+// with, and turning a flag off turns off the flags requiring it, as far as that
+// goes. Flags set explicitly are never overridden; whatever can't be resolved
+// is returned as problems.
+func (r FlagRegistry) Resolve(current map[string]bool, changes map[string]bool) (resolved map[string]bool, problems []string) {
+	state := make(map[string]bool, len(current)+len(changes))
+	for flag, value := range current {
+		state[flag] = value
+	}
+
+	resolved = make(map[string]bool, len(changes))
+	pending := make([]string, 0, len(changes))
+	for flag, value := range changes {
+		state[flag] = value
+		resolved[flag] = value
+		pending = append(pending, flag)
+	}
+	slices.Sort(pending)
+
+	apply := func(flag string, value bool) {
+		if state[flag] == value {
+			return
+		}
+		// explicit changes and flags already flipped the other way are left
+		// for the check to report
+		if _, ok := resolved[flag]; ok {
+			return
+		}
+		state[flag] = value
+		resolved[flag] = value
+		pending = append(pending, flag)
+	}
+
+	for len(pending) > 0 {
+		flag := pending[0]
+		pending = pending[1:]
+
+		if state[flag] {
+			for _, required := range r.byName[flag].Requires {
+				apply(required, true)
+			}
+			for _, conflict := range r.conflicts[flag] {
+				apply(conflict, false)
+			}
+		} else {
+			for _, dependent := range r.dependents[flag] {
+				apply(dependent, false)
+			}
+		}
+	}
+
+	return resolved, r.Check(current, resolved)
+}
+
+// Reconcile validates changes coming from an admin. Unknown flags are
+// rejected. With resolve, the changes are extended to keep the flags
+// consistent, otherwise they have to be consistent already. It returns every
+// flag to set.
+func (r FlagRegistry) Reconcile(current map[string]bool, changes map[string]bool, resolve bool) (map[string]bool, errmsg.StatusError) {
+	names := make([]string, 0, len(changes))
+	for flag := range changes {
+		names = append(names, flag)
+	}
+	if unknown := r.Unknown(names); len(unknown) > 0 {
+		return nil, errmsg.FlagUnknown(unknown)
+	}
+
+	problems := []string{}
+	if resolve {
+		changes, problems = r.Resolve(current, changes)
+	} else {
+		problems = r.Check(current, changes)
+	}
+	if len(problems) > 0 {
+		return nil, errmsg.FlagInconsistent(problems)
+	}
+
+	return changes, errmsg.EmptyStatusError
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"backend"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testFlagRegistry = []byte(`{"flags": [
+	{"name": "teams_read"},
+	{"name": "teams_write", "requires": ["teams_read"]},
+	{"name": "submissions_read", "requires": ["teams_read"]},
+	{"name": "submissions_write", "requires": ["submissions_read"], "conflicts": ["judging"]},
+	{"name": "judging", "requires": ["submissions_read"]}
+]}`)
+
+func TestLoadFlagRegistry(t *testing.T) {
+	_, err := LoadFlagRegistry(testFlagRegistry)
+	require.NoError(t, err)
+
+	invalid := map[string]string{
+		"unnamed":            `{"flags": [{"name": ""}]}`,
+		"duplicate":          `{"flags": [{"name": "a"}, {"name": "a"}]}`,
+		"unknown dependency": `{"flags": [{"name": "a", "requires": ["b"]}]}`,
+		"self dependency":    `{"flags": [{"name": "a", "requires": ["a"]}]}`,
+		"unknown conflict":   `{"flags": [{"name": "a", "conflicts": ["b"]}]}`,
+		"unsatisfiable": `{"flags": [
+			{"name": "a"}, {"name": "b", "requires": ["a"]},
+			{"name": "c", "conflicts": ["a"]}, {"name": "d", "requires": ["b", "c"]}
+		]}`,
+	}
+	for name, data := range invalid {
+		_, err := LoadFlagRegistry([]byte(data))
+		require.Error(t, err, name)
+	}
+}
+
+func TestFlagRegistryCheck(t *testing.T) {
+	registry, err := LoadFlagRegistry(testFlagRegistry)
+	require.NoError(t, err)
+
+	current := map[string]bool{"teams_read": true, "submissions_read": true, "submissions_write": true}
+
+	require.Empty(t, registry.Check(current, map[string]bool{"teams_write": true}))
+	require.Equal(t, []string{"submissions_read requires teams_read", "teams_write requires teams_read"},
+		registry.Check(map[string]bool{"submissions_read": true}, map[string]bool{"teams_write": true, "teams_read": false}))
+	require.Equal(t, []string{"submissions_write requires submissions_read"},
+		registry.Check(current, map[string]bool{"submissions_read": false}))
+	require.Equal(t, []string{"judging conflicts with submissions_write"},
+		registry.Check(current, map[string]bool{"judging": true}), "conflicts go both ways")
+	require.Empty(t, registry.Check(map[string]bool{"submissions_write": true}, map[string]bool{"unregistered": true}),
+		"problems that are already there don't block unrelated changes")
+
+	require.Empty(t, registry.Contradictions(map[string]bool{"submissions_write": true, "judging": false}),
+		"flags a stage doesn't set are left to the current state")
+	require.Equal(t, []string{"submissions_write requires submissions_read"},
+		registry.Contradictions(map[string]bool{"submissions_write": true, "submissions_read": false}))
+}
+
+func TestFlagRegistryResolve(t *testing.T) {
+	registry, err := LoadFlagRegistry(testFlagRegistry)
+	require.NoError(t, err)
+
+	resolved, problems := registry.Resolve(map[string]bool{}, map[string]bool{"submissions_write": true})
+	require.Empty(t, problems)
+	require.Equal(t, map[string]bool{"submissions_write": true, "submissions_read": true, "teams_read": true}, resolved)
+
+	current := map[string]bool{"teams_read": true, "teams_write": true, "submissions_read": true, "submissions_write": true}
+	resolved, problems = registry.Resolve(current, map[string]bool{"teams_read": false})
+	require.Empty(t, problems)
+	require.Equal(t, map[string]bool{"teams_read": false, "teams_write": false, "submissions_read": false, "submissions_write": false}, resolved)
+
+	resolved, problems = registry.Resolve(current, map[string]bool{"judging": true})
+	require.Empty(t, problems)
+	require.Equal(t, map[string]bool{"judging": true, "submissions_write": false}, resolved)
+
+	_, problems = registry.Resolve(current, map[string]bool{"judging": true, "submissions_write": true})
+	require.Equal(t, []string{"judging conflicts with submissions_write"}, problems, "explicit changes are never overridden")
+
+	_, serr := registry.Reconcile(current, map[string]bool{"judging": true, "nope": true}, true)
+	require.Equal(t, http.StatusBadRequest, serr.StatusCode)
+	require.Equal(t, "unknown flags: nope", serr.Message)
+
+	_, serr = registry.Reconcile(current, map[string]bool{"judging": true}, false)
+	require.Equal(t, http.StatusConflict, serr.StatusCode)
+}
+
+func TestFlagRegistryConfig(t *testing.T) {
+	registry, err := LoadFlagRegistry(backend.FlagsConfig)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile("../../flagstages_config.json")
+	require.NoError(t, err)
+	var stages []FlagStage
+	require.NoError(t, json.Unmarshal(data, &stages))
+
+	FlagsRegistry = registry
+	defer func() { FlagsRegistry = FlagRegistry{} }()
+
+	for _, stage := range stages {
+		require.Empty(t, stage.Validate().Message, "stage %s", stage.Name)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"backend/internal/utils"
 	"encoding/json"
 	"maps"
+	"slices"
 
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,8 +15,101 @@ import (
 type Flags struct {
 	Flags map[string]bool `json:"flags" bson:"flags"`
 	Stage FlagStage       `json:"stage" bson:"stage"`
+	// audiences that get a flag while it is off for everyone else
+	Rules map[string]FlagRule `json:"rules,omitempty" bson:"rules,omitempty"`
 }
 
+// FlagRulePercentageMax is a percentage rollout reaching every account
+var FlagRulePercentageMax = 100
+
+// FlagRule turns a flag on for the accounts it targets while the flag is off.
+// An account is targeted when any of the lists names it, its team or its
+// university, or when it falls within the rollout percentage.
+type FlagRule struct {
+	AccountIDs   []string `json:"accountIDs" bson:"accountIDs"`
+	TeamIDs      []string `json:"teamIDs" bson:"teamIDs"`
+	Universities []string `json:"universities" bson:"universities"` // compared regardless of case and spacing
+	// share of accounts from 0 to 100; each account's place is fixed by hashing
+	// its ID with the flag name, so raising the percentage only adds accounts
+	Percentage int `json:"percentage" bson:"percentage"`
+}
+
+// Validate checks that the percentage is in range
+func (r FlagRule) Validate() errmsg.StatusError {
+	if r.Percentage < 0 || r.Percentage > FlagRulePercentageMax {
+		return errmsg.FlagRuleInvalid
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// Matches reports whether the rule targets the account for the flag
+func (r FlagRule) Matches(flag string, acc Account) bool {
+	if acc.ID == "" {
+		return false
+	}
+
+	if slices.Contains(r.AccountIDs, acc.ID) {
+		return true
+	}
+	if acc.TeamID != "" && slices.Contains(r.TeamIDs, acc.TeamID) {
+		return true
+	}
+	if acc.University != "" {
+		university := normalizeUniversity(acc.University)
+		for _, u := range r.Universities {
+			if normalizeUniversity(u) == university {
+				return true
+			}
+		}
+	}
+
+	return utils.PileWithSalt(acc.ID, utils.Hash32(flag), FlagRulePercentageMax) < r.Percentage
+}
+
+// EnabledFor reports whether the flag is on for the account: on for everyone,
+// or targeted by the flag's rule. A nil account only sees the global value.
+func (f Flags) EnabledFor(flag string, acc *Account) bool {
+	if f.Flags[flag] {
+		return true
+	}
+	if acc == nil {
+		return false
+	}
+
+	rule, ok := f.Rules[flag]
+	return ok && rule.Matches(flag, *acc)
+}
+
+// EvaluateFor resolves every flag, including the ones only set through a rule,
+// for the account
+func (f Flags) EvaluateFor(acc Account) map[string]bool {
+	evaluated := make(map[string]bool, len(f.Flags)+len(f.Rules))
+	for flag := range f.Flags {
+		evaluated[flag] = f.EnabledFor(flag, &acc)
+	}
+	for flag := range f.Rules {
+		evaluated[flag] = f.EnabledFor(flag, &acc)
+	}
+
+	return evaluated
+}
+
+// needsAudience reports whether any of the flags is off globally but has a
+// rule, so the caller's identity decides
+func (f Flags) needsAudience(flags []string) bool {
+	for _, flag := range flags {
+		if _, ok := f.Rules[flag]; ok && !f.Flags[flag] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FlagsMiddlewareBuilder requires every flag to be on for the caller. Flags
+// with rules are evaluated against the account signed in by AccountMiddleware;
+// other callers only see the global values.
 func FlagsMiddlewareBuilder(flags []string) fiber.Handler {
 	return func(c fiber.Ctx) error {
 		f := Flags{}
@@ -27,8 +121,19 @@ func FlagsMiddlewareBuilder(flags []string) fiber.Handler {
 			)
 		}
 
+		var account *Account
+		if f.needsAudience(flags) && c.Locals("account") != nil {
+			acc := Account{}
+			utils.GetLocals(c, "account", &acc)
+
+			// the token may predate a team or university change
+			if acc.ID != "" && acc.Get() == nil {
+				account = &acc
+			}
+		}
+
 		for _, flagName := range flags {
-			if !f.Flags[flagName] {
+			if !f.EnabledFor(flagName, account) {
 				return utils.StatusError(c, errmsg.FlagRequired)
 			}
 		}
@@ -59,64 +164,25 @@ func (f *Flags) Get() (err error) {
 	return nil
 }
 
-func (f *Flags) Set(flag string, value bool) (err error) {
-	_, err = db.Flags.UpdateOne(db.Ctx, bson.M{},
-		bson.M{
-			"$set": bson.M{
-				"flags." + flag: value,
-			},
-		},
-	)
-
-	if err != nil {
-		return err
-	}
-
-	if f.Flags == nil {
-		f.Flags = map[string]bool{}
-	}
-	f.Flags[flag] = value
-
-	cacheFlags(f)
-
-	return
+func (f *Flags) Set(flag string, value bool, changedBy string) (err error) {
+	return f.apply(map[string]bool{flag: value}, nil, changedBy, "flag set")
 }
 
-func (f *Flags) SetBulk(rawFlags map[string]bool) (err error) {
-	marshaledFlags := bson.M{}
-
-	for k, v := range rawFlags {
-		marshaledFlags["flags."+k] = v
-	}
-
-	_, err = db.Flags.UpdateOne(db.Ctx, bson.M{},
-		bson.M{
-			"$set": marshaledFlags,
-		},
-	)
-
-	if err != nil {
-		return err
-	}
-
-	if f.Flags == nil {
-		f.Flags = map[string]bool{}
-	}
-	maps.Copy(f.Flags, rawFlags)
-
-	cacheFlags(f)
-
-	return
+func (f *Flags) SetBulk(rawFlags map[string]bool, changedBy string) (err error) {
+	return f.apply(rawFlags, nil, changedBy, "flags set in bulk")
 }
 
-func (f *Flags) Unset(flag string) (err error) {
-	_, err = db.Flags.UpdateOne(db.Ctx, bson.M{},
+// Unset removes the flag along with its rule
+func (f *Flags) Unset(flag string, changedBy string) (err error) {
+	var before Flags
+	err = db.Flags.FindOneAndUpdate(db.Ctx, bson.M{},
 		bson.M{
 			"$unset": bson.M{
 				"flags." + flag: "",
+				"rules." + flag: "",
 			},
 		},
-	)
+	).Decode(&before)
 
 	if err != nil {
 		return err
@@ -129,20 +195,25 @@ func (f *Flags) Unset(flag string) (err error) {
 		}
 	}
 	f.Flags = newFlags
+	delete(f.Rules, flag)
 
 	cacheFlags(f)
 
-	return
+	changes := append(
+		flagChanges(before.Flags, nil, []string{flag}),
+		ruleChanges(before.Rules, nil, []string{flag})...,
+	)
+	return recordConfigChanges(changes, changedBy, "flag unset")
 }
 
-func (f *Flags) Reset() (err error) {
+func (f *Flags) Reset(changedBy string) (err error) {
 	resetFlags := map[string]bool{}
 
 	for f := range f.Flags {
 		resetFlags[f] = false
 	}
 
-	err = f.SetBulk(resetFlags)
+	err = f.apply(resetFlags, nil, changedBy, "flags reset")
 	if err != nil {
 		return
 	}
@@ -151,6 +222,48 @@ func (f *Flags) Reset() (err error) {
 	return
 }
 
+// apply sets and removes flags in one update and records what it changed in
+// the history, as done by changedBy through source
+func (f *Flags) apply(set map[string]bool, unset []string, changedBy string, source string) (err error) {
+	update := bson.M{}
+	if len(set) > 0 {
+		marshaledFlags := bson.M{}
+		for k, v := range set {
+			marshaledFlags["flags."+k] = v
+		}
+		update["$set"] = marshaledFlags
+	}
+	if len(unset) > 0 {
+		unsetFlags := bson.M{}
+		for _, k := range unset {
+			unsetFlags["flags."+k] = ""
+		}
+		update["$unset"] = unsetFlags
+	}
+	if len(update) == 0 {
+		return nil
+	}
+
+	var before Flags
+	err = db.Flags.FindOneAndUpdate(db.Ctx, bson.M{}, update).Decode(&before)
+
+	if err != nil {
+		return err
+	}
+
+	if f.Flags == nil {
+		f.Flags = map[string]bool{}
+	}
+	maps.Copy(f.Flags, set)
+	for _, k := range unset {
+		delete(f.Flags, k)
+	}
+
+	cacheFlags(f)
+
+	return recordFlagChanges(before.Flags, set, unset, changedBy, source)
+}
+
 func cacheFlags(f *Flags) {
 	if f == nil {
 		return
@@ -182,17 +295,17 @@ func flagsCacheKey() string {
 	return "flags"
 }
 
-// this will not update the cache,
-// as it will always be executed when changing a stage,
-// which automatically does a SetBulk on the flags
-func (f *Flags) SetStage(flagStage FlagStage) (err error) {
-	_, err = db.Flags.UpdateOne(db.Ctx, bson.M{},
+// SetStage records flagStage as the current stage, as done by changedBy through
+// source. It doesn't change any flag; executing the stage does that.
+func (f *Flags) SetStage(flagStage FlagStage, changedBy string, source string) (err error) {
+	var before Flags
+	err = db.Flags.FindOneAndUpdate(db.Ctx, bson.M{},
 		bson.M{
 			"$set": bson.M{
 				"stage": flagStage,
 			},
 		},
-	)
+	).Decode(&before)
 
 	if err != nil {
 		return err
@@ -200,5 +313,134 @@ func (f *Flags) SetStage(flagStage FlagStage) (err error) {
 
 	f.Stage = flagStage
 
-	return
+	cacheFlags(f)
+
+	return recordConfigChanges(stageChanges(before.Stage, flagStage), changedBy, source)
+}
+
+// ExecuteStage records flagStage as the current stage and sets the given flags
+// in one update, so an execution never leaves only one of them applied
+func (f *Flags) ExecuteStage(flagStage FlagStage, flags map[string]bool, changedBy string, source string) (err error) {
+	set := bson.M{"stage": flagStage}
+	for k, v := range flags {
+		set["flags."+k] = v
+	}
+
+	var before Flags
+	err = db.Flags.FindOneAndUpdate(db.Ctx, bson.M{}, bson.M{"$set": set}).Decode(&before)
+	if err != nil {
+		return err
+	}
+
+	if f.Flags == nil {
+		f.Flags = map[string]bool{}
+	}
+	maps.Copy(f.Flags, flags)
+	f.Stage = flagStage
+
+	cacheFlags(f)
+
+	configChanges := append(
+		flagChanges(before.Flags, flags, nil),
+		stageChanges(before.Stage, flagStage)...,
+	)
+	return recordConfigChanges(configChanges, changedBy, source)
+}
+
+// Revert sets the changed flags back to the values they had before the change,
+// removes the ones the change added, and puts the stage back
+func (f *Flags) Revert(changes []FlagChange, stage FlagStage, changedBy string, source string) (err error) {
+	set := bson.M{"stage": stage}
+	unset := bson.M{}
+	reverted := map[string]bool{}
+	removed := []string{}
+	for _, change := range changes {
+		if change.Existed {
+			set["flags."+change.Flag] = change.From
+			reverted[change.Flag] = change.From
+		} else {
+			unset["flags."+change.Flag] = ""
+			removed = append(removed, change.Flag)
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	var before Flags
+	err = db.Flags.FindOneAndUpdate(db.Ctx, bson.M{}, update).Decode(&before)
+	if err != nil {
+		return err
+	}
+
+	if f.Flags == nil {
+		f.Flags = map[string]bool{}
+	}
+	maps.Copy(f.Flags, reverted)
+	for _, flag := range removed {
+		delete(f.Flags, flag)
+	}
+	f.Stage = stage
+
+	cacheFlags(f)
+
+	configChanges := append(
+		flagChanges(before.Flags, reverted, removed),
+		stageChanges(before.Stage, stage)...,
+	)
+	return recordConfigChanges(configChanges, changedBy, source)
+}
+
+// SetRule targets the flag at an audience, replacing any rule it had
+func (f *Flags) SetRule(flag string, rule FlagRule, changedBy string) (err error) {
+	return f.applyRules(map[string]FlagRule{flag: rule}, nil, changedBy, "rule set")
+}
+
+// UnsetRule stops targeting the flag, leaving its global value
+func (f *Flags) UnsetRule(flag string, changedBy string) (err error) {
+	return f.applyRules(nil, []string{flag}, changedBy, "rule unset")
+}
+
+// applyRules sets and removes rules in one update and records what it changed
+// in the history, as done by changedBy through source
+func (f *Flags) applyRules(set map[string]FlagRule, unset []string, changedBy string, source string) (err error) {
+	update := bson.M{}
+	if len(set) > 0 {
+		marshaledRules := bson.M{}
+		for k, v := range set {
+			marshaledRules["rules."+k] = v
+		}
+		update["$set"] = marshaledRules
+	}
+	if len(unset) > 0 {
+		unsetRules := bson.M{}
+		for _, k := range unset {
+			unsetRules["rules."+k] = ""
+		}
+		update["$unset"] = unsetRules
+	}
+	if len(update) == 0 {
+		return nil
+	}
+
+	var before Flags
+	err = db.Flags.FindOneAndUpdate(db.Ctx, bson.M{}, update).Decode(&before)
+
+	if err != nil {
+		return err
+	}
+
+	if f.Rules == nil {
+		f.Rules = map[string]FlagRule{}
+	}
+	maps.Copy(f.Rules, set)
+	for _, k := range unset {
+		delete(f.Rules, k)
+	}
+
+	cacheFlags(f)
+
+	return recordConfigChanges(ruleChanges(before.Rules, set, unset), changedBy, source)
 }
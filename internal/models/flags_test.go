@@ -0,0 +1,87 @@
+package models
+
+import (
+	"backend/internal/errmsg"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagRuleMatches(t *testing.T) {
+	acc := Account{ID: "acc_1", TeamID: "team_1", University: "Politehnica  Bucharest"}
+
+	require.True(t, FlagRule{AccountIDs: []string{"acc_1"}}.Matches("beta", acc))
+	require.True(t, FlagRule{TeamIDs: []string{"team_1"}}.Matches("beta", acc))
+	require.True(t, FlagRule{Universities: []string{"politehnica bucharest"}}.Matches("beta", acc), "universities are compared regardless of case and spacing")
+	require.True(t, FlagRule{Percentage: 100}.Matches("beta", acc))
+	require.False(t, FlagRule{Percentage: 0}.Matches("beta", acc))
+	require.False(t, FlagRule{AccountIDs: []string{"acc_2"}, TeamIDs: []string{"team_2"}}.Matches("beta", acc))
+	require.False(t, FlagRule{Percentage: 100}.Matches("beta", Account{}), "anonymous callers are never targeted")
+	require.False(t, FlagRule{TeamIDs: []string{""}}.Matches("beta", Account{ID: "acc_3"}), "accounts without a team don't match an empty team ID")
+}
+
+// TestFlagRulePercentage checks that rollouts reach about the requested share
+// of accounts, and that raising the percentage keeps the accounts already in
+func TestFlagRulePercentage(t *testing.T) {
+	const numAccounts = 10000
+
+	inAt := func(percentage int) map[string]bool {
+		in := map[string]bool{}
+		for i := range numAccounts {
+			acc := Account{ID: fmt.Sprintf("acc_%d", i)}
+			if (FlagRule{Percentage: percentage}).Matches("rollout", acc) {
+				in[acc.ID] = true
+			}
+		}
+		return in
+	}
+
+	ten := inAt(10)
+	twenty := inAt(20)
+	require.InDelta(t, numAccounts/10, len(ten), numAccounts/100)
+	require.InDelta(t, numAccounts/5, len(twenty), numAccounts/100)
+	for id := range ten {
+		require.True(t, twenty[id], "%s left the rollout when it grew", id)
+	}
+
+	// different flags roll out to different accounts
+	other := 0
+	for i := range numAccounts {
+		acc := Account{ID: fmt.Sprintf("acc_%d", i)}
+		if (FlagRule{Percentage: 10}).Matches("other", acc) && ten[acc.ID] {
+			other++
+		}
+	}
+	require.Less(t, other, len(ten)/2)
+}
+
+func TestFlagsEnabledFor(t *testing.T) {
+	flags := Flags{
+		Flags: map[string]bool{"global": true, "targeted": false, "off": false},
+		Rules: map[string]FlagRule{
+			"targeted": {TeamIDs: []string{"team_1"}},
+			"ruleonly": {AccountIDs: []string{"acc_1"}},
+		},
+	}
+	acc := Account{ID: "acc_1", TeamID: "team_1"}
+
+	require.True(t, flags.EnabledFor("global", nil))
+	require.False(t, flags.EnabledFor("targeted", nil), "without an account only the global value counts")
+	require.True(t, flags.EnabledFor("targeted", &acc))
+	require.False(t, flags.EnabledFor("targeted", &Account{ID: "acc_2"}))
+
+	require.Equal(t, map[string]bool{
+		"global":   true,
+		"targeted": true,
+		"off":      false,
+		"ruleonly": true,
+	}, flags.EvaluateFor(acc))
+
+	require.True(t, flags.needsAudience([]string{"global", "targeted"}))
+	require.False(t, flags.needsAudience([]string{"global", "off"}))
+
+	require.Equal(t, errmsg.FlagRuleInvalid, FlagRule{Percentage: 101}.Validate())
+	require.Equal(t, errmsg.FlagRuleInvalid, FlagRule{Percentage: -1}.Validate())
+	require.Equal(t, errmsg.EmptyStatusError, FlagRule{Percentage: 100}.Validate())
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FlagChange is one flag a stage sets to a different value than it has
+type FlagChange struct {
+	Flag    string `json:"flag" bson:"flag"`
+	Existed bool   `json:"existed" bson:"existed"` // false when the stage adds the flag
+	From    bool   `json:"from" bson:"from"`
+	To      bool   `json:"to" bson:"to"`
+}
+
+// FlagStageExecution records a stage being applied along with the flags and
+// stage it replaced, so that it can be rolled back
+type FlagStageExecution struct {
+	ID            string          `json:"id" bson:"id"`
+	StageID       string          `json:"stageID" bson:"stageID"`
+	StageName     string          `json:"stageName" bson:"stageName"`
+	PreviousFlags map[string]bool `json:"previousFlags" bson:"previousFlags"`
+	PreviousStage FlagStage       `json:"previousStage" bson:"previousStage"`
+	Changes       []FlagChange    `json:"changes" bson:"changes"`
+	ExecutedBy    string          `json:"executedBy" bson:"executedBy"`
+	ExecutedAt    time.Time       `json:"executedAt" bson:"executedAt"`
+	RolledBackBy  string          `json:"rolledBackBy,omitempty" bson:"rolledBackBy,omitempty"`
+	RolledBackAt  *time.Time      `json:"rolledBackAt,omitempty" bson:"rolledBackAt,omitempty"`
+}
+
+// Instructions combines the stage's lists; a flag in both is turned on
+func (fstage FlagStage) Instructions() map[string]bool {
+	instructions := map[string]bool{}
+	for _, v := range fstage.TurnOff {
+		instructions[v] = false
+	}
+
+	for _, v := range fstage.TurnOn {
+		instructions[v] = true
+	}
+
+	return instructions
+}
+
+// Diff lists the flags the stage would change, sorted by name. Flags the stage
+// sets to the value they already have are left out.
+func (fstage FlagStage) Diff(current map[string]bool) []FlagChange {
+	changes := []FlagChange{}
+	for flag, to := range fstage.Instructions() {
+		from, existed := current[flag]
+		if existed && from == to {
+			continue
+		}
+		changes = append(changes, FlagChange{Flag: flag, Existed: existed, From: from, To: to})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Flag < changes[j].Flag
+	})
+
+	return changes
+}
+
+func (fe *FlagStageExecution) Create() error {
+	fe.ExecutedAt = time.Now()
+
+	return WithNewID(func(ID string) error {
+		fe.ID = ID
+		_, err := db.FlagStageExecutions.InsertOne(db.Ctx, fe)
+		return err
+	})
+}
+
+func (fe *FlagStageExecution) Get() errmsg.StatusError {
+	err := db.FlagStageExecutions.FindOne(db.Ctx, bson.M{"id": fe.ID}).Decode(fe)
+	if err == mongo.ErrNoDocuments {
+		return errmsg.FlagStageExecutionNotFound
+	}
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// GetFlagStageExecutions lists stage executions, latest first
+func GetFlagStageExecutions() ([]FlagStageExecution, errmsg.StatusError) {
+	cursor, err := db.FlagStageExecutions.Find(db.Ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "executedAt", Value: -1}}),
+	)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	executions := []FlagStageExecution{}
+	if err = cursor.All(db.Ctx, &executions); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return executions, errmsg.EmptyStatusError
+}
+
+// RollBack puts the flags the execution changed and the stage back the way they
+// were before it. Flags it added are removed again; flags it didn't change are
+// left alone. Only the latest execution that hasn't been rolled back can be
+// rolled back, so that executions are undone in reverse order.
+func (fe *FlagStageExecution) RollBack(rolledBackBy string) errmsg.StatusError {
+	var latest FlagStageExecution
+	err := db.FlagStageExecutions.FindOne(db.Ctx,
+		bson.M{"rolledBackAt": bson.M{"$exists": false}},
+		options.FindOne().SetSort(bson.D{{Key: "executedAt", Value: -1}}),
+	).Decode(&latest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return errmsg.InternalServerError(err)
+	}
+	if err == mongo.ErrNoDocuments || latest.ID != fe.ID {
+		return errmsg.FlagStageRollbackConflict
+	}
+
+	// claiming the rollback first keeps two admins from both applying it
+	now := time.Now()
+	result, err := db.FlagStageExecutions.UpdateOne(db.Ctx,
+		bson.M{"id": fe.ID, "rolledBackAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"rolledBackAt": now, "rolledBackBy": rolledBackBy}},
+	)
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if result.ModifiedCount == 0 {
+		return errmsg.FlagStageRollbackConflict
+	}
+	fe.RolledBackAt = &now
+	fe.RolledBackBy = rolledBackBy
+
+	flags := Flags{}
+	if err := flags.Get(); err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if err := flags.Revert(fe.Changes, fe.PreviousStage, rolledBackBy, "flag stage "+fe.StageName+" rolled back"); err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var FlagStageSchedulePending = "pending"
+var FlagStageScheduleRunning = "running"
+var FlagStageScheduleDone = "done"
+var FlagStageScheduleFailed = "failed"
+var FlagStageScheduleCancelled = "cancelled"
+
+// ErrFlagStageScheduleLeaseLost is returned when the schedule is no longer
+// running under the caller's lease, usually because it ran out and the schedule
+// was marked failed
+var ErrFlagStageScheduleLeaseLost = errors.New("flag stage schedule lease was lost")
+
+// FlagStageSchedule runs a flag stage at RunAt. A scheduler claims it by taking
+// a lease, so however many processes poll, only one executes it. A schedule
+// whose lease runs out before it finished is marked failed rather than run again.
+type FlagStageSchedule struct {
+	ID         string     `json:"id" bson:"id"`
+	StageID    string     `json:"stageID" bson:"stageID"`
+	StageName  string     `json:"stageName" bson:"stageName"`
+	RunAt      time.Time  `json:"runAt" bson:"runAt"`
+	Status     string     `json:"status" bson:"status"`
+	CreatedBy  string     `json:"createdBy" bson:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	LeaseOwner string     `json:"leaseOwner,omitempty" bson:"leaseOwner,omitempty"`
+	LeaseUntil time.Time  `json:"-" bson:"leaseUntil,omitempty"`
+	ExecutedAt *time.Time `json:"executedAt,omitempty" bson:"executedAt,omitempty"`
+	Error      string     `json:"error,omitempty" bson:"error,omitempty"`
+	// the execution the scheduler recorded, which can be rolled back
+	ExecutionID string `json:"executionID,omitempty" bson:"executionID,omitempty"`
+}
+
+func (s *FlagStageSchedule) Create() errmsg.StatusError {
+	if !s.RunAt.After(time.Now()) {
+		return errmsg.FlagStageScheduleInvalid
+	}
+
+	s.Status = FlagStageSchedulePending
+	s.CreatedAt = time.Now()
+
+	err := WithNewID(func(ID string) error {
+		s.ID = ID
+		_, err := db.FlagStageSchedules.InsertOne(db.Ctx, s)
+		return err
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+func (s *FlagStageSchedule) Get() errmsg.StatusError {
+	err := db.FlagStageSchedules.FindOne(db.Ctx, bson.M{"id": s.ID}).Decode(s)
+	if err == mongo.ErrNoDocuments {
+		return errmsg.FlagStageScheduleNotFound
+	}
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// Reschedule moves a pending schedule to runAt
+func (s *FlagStageSchedule) Reschedule(runAt time.Time) errmsg.StatusError {
+	if !runAt.After(time.Now()) {
+		return errmsg.FlagStageScheduleInvalid
+	}
+
+	return s.updatePending(bson.M{"runAt": runAt})
+}
+
+// Cancel stops a pending schedule from running
+func (s *FlagStageSchedule) Cancel() errmsg.StatusError {
+	return s.updatePending(bson.M{"status": FlagStageScheduleCancelled})
+}
+
+// updatePending only applies the change while the schedule is still pending, so
+// it can't race with a scheduler claiming it
+func (s *FlagStageSchedule) updatePending(set bson.M) errmsg.StatusError {
+	err := db.FlagStageSchedules.FindOneAndUpdate(db.Ctx,
+		bson.M{"id": s.ID, "status": FlagStageSchedulePending},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(s)
+	if err == nil {
+		return errmsg.EmptyStatusError
+	}
+	if err != mongo.ErrNoDocuments {
+		return errmsg.InternalServerError(err)
+	}
+
+	if serr := s.Get(); serr != errmsg.EmptyStatusError {
+		return serr
+	}
+	return errmsg.FlagStageScheduleNotPending
+}
+
+// Finish records how the claimed schedule's execution went. Only the lease owner
+// can finish it; once the lease is lost the schedule has already been marked
+// failed and ErrFlagStageScheduleLeaseLost is returned.
+func (s *FlagStageSchedule) Finish(executionID string, executeErr error) error {
+	now := time.Now()
+	set := bson.M{"status": FlagStageScheduleDone, "executedAt": now, "executionID": executionID}
+	if executeErr != nil {
+		set["status"] = FlagStageScheduleFailed
+		set["error"] = executeErr.Error()
+	}
+
+	result, err := db.FlagStageSchedules.UpdateOne(db.Ctx,
+		bson.M{"id": s.ID, "status": FlagStageScheduleRunning, "leaseOwner": s.LeaseOwner},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrFlagStageScheduleLeaseLost
+	}
+
+	s.Status = set["status"].(string)
+	s.ExecutedAt = &now
+	s.ExecutionID = executionID
+	if executeErr != nil {
+		s.Error = executeErr.Error()
+	}
+
+	return nil
+}
+
+// RenewLease extends the claimed schedule's lease to until, so a stage that
+// takes longer than one lease isn't marked failed while it is still running
+func (s *FlagStageSchedule) RenewLease(until time.Time) error {
+	result, err := db.FlagStageSchedules.UpdateOne(db.Ctx,
+		bson.M{"id": s.ID, "status": FlagStageScheduleRunning, "leaseOwner": s.LeaseOwner},
+		bson.M{"$set": bson.M{"leaseUntil": until}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrFlagStageScheduleLeaseLost
+	}
+
+	s.LeaseUntil = until
+	return nil
+}
+
+func GetFlagStageSchedules() ([]FlagStageSchedule, errmsg.StatusError) {
+	cursor, err := db.FlagStageSchedules.Find(db.Ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "runAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	schedules := []FlagStageSchedule{}
+	if err = cursor.All(db.Ctx, &schedules); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return schedules, errmsg.EmptyStatusError
+}
+
+// ClaimDueFlagStageSchedule leases the earliest pending schedule that is due to
+// owner. The claim is a single atomic update, so two schedulers can never both
+// claim the same schedule.
+func ClaimDueFlagStageSchedule(owner string, now time.Time, lease time.Duration) (schedule FlagStageSchedule, found bool, err error) {
+	err = db.FlagStageSchedules.FindOneAndUpdate(db.Ctx,
+		bson.M{"status": FlagStageSchedulePending, "runAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{
+			"status":     FlagStageScheduleRunning,
+			"leaseOwner": owner,
+			"leaseUntil": now.Add(lease),
+		}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "runAt", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&schedule)
+	if err == mongo.ErrNoDocuments {
+		return schedule, false, nil
+	}
+	if err != nil {
+		return schedule, false, err
+	}
+
+	return schedule, true, nil
+}
+
+// FailExpiredFlagStageSchedules marks schedules whose lease ran out while running
+// as failed. Their stage may or may not have been applied, so they are not
+// retried; an admin can execute or schedule the stage again.
+func FailExpiredFlagStageSchedules(now time.Time) error {
+	_, err := db.FlagStageSchedules.UpdateMany(db.Ctx,
+		bson.M{"status": FlagStageScheduleRunning, "leaseUntil": bson.M{"$lt": now}},
+		bson.M{"$set": bson.M{
+			"status": FlagStageScheduleFailed,
+			"error":  "the scheduler stopped before the stage finished executing",
+		}},
+	)
+
+	return err
+}
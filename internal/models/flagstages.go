@@ -3,8 +3,9 @@ package models
 import (
 	"backend/internal/db"
 	"backend/internal/errmsg"
-	"backend/internal/utils"
 	"encoding/json"
+	"maps"
+	"slices"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -44,10 +45,27 @@ func (fstage *FlagStage) Get() (serr errmsg.StatusError) {
 	return errmsg.EmptyStatusError
 }
 
-func (fstage *FlagStage) Create() (err error) {
-	fstage.ID = utils.GenID(6)
+// Validate checks that the stage only names registered flags and that its
+// instructions don't contradict the registry among themselves
+func (fstage FlagStage) Validate() errmsg.StatusError {
+	names := append(slices.Clone(fstage.TurnOn), fstage.TurnOff...)
+	if unknown := FlagsRegistry.Unknown(names); len(unknown) > 0 {
+		return errmsg.FlagUnknown(unknown)
+	}
 
-	_, err = db.FlagStages.InsertOne(db.Ctx, fstage)
+	if problems := FlagsRegistry.Contradictions(fstage.Instructions()); len(problems) > 0 {
+		return errmsg.FlagInconsistent(problems)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+func (fstage *FlagStage) Create() (err error) {
+	err = WithNewID(func(ID string) error {
+		fstage.ID = ID
+		_, err := db.FlagStages.InsertOne(db.Ctx, fstage)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -75,34 +93,45 @@ func (fstage *FlagStage) Delete() (err error) {
 	return nil
 }
 
-func (fstage *FlagStage) Execute() (err error) {
-	// combine the two lists
-	instructions := map[string]bool{}
-	for _, v := range fstage.TurnOff {
-		instructions[v] = false
+// Execute applies the stage and records the flags and stage it replaced, so
+// the execution can be rolled back. A stage that would leave the flags
+// inconsistent with the registry is not applied.
+func (fstage *FlagStage) Execute(executedBy string) (execution FlagStageExecution, serr errmsg.StatusError) {
+	flags := Flags{}
+	err := flags.Get()
+	if err != nil {
+		return execution, errmsg.InternalServerError(err)
 	}
 
-	for _, v := range fstage.TurnOn {
-		instructions[v] = true
+	instructions := fstage.Instructions()
+	if problems := FlagsRegistry.Check(flags.Flags, instructions); len(problems) > 0 {
+		return execution, errmsg.FlagInconsistent(problems)
 	}
 
-	flags := Flags{}
-	err = flags.Get()
-	if err != nil {
-		return
+	execution = FlagStageExecution{
+		StageID:       fstage.ID,
+		StageName:     fstage.Name,
+		PreviousFlags: maps.Clone(flags.Flags),
+		PreviousStage: flags.Stage,
+		Changes:       fstage.Diff(flags.Flags),
+		ExecutedBy:    executedBy,
+	}
+	if execution.PreviousFlags == nil {
+		execution.PreviousFlags = map[string]bool{}
 	}
 
-	err = flags.SetStage(*fstage)
+	source := "flag stage " + fstage.Name + " executed"
+	err = flags.ExecuteStage(*fstage, instructions, executedBy, source)
 	if err != nil {
-		return
+		return execution, errmsg.InternalServerError(err)
 	}
 
-	err = flags.SetBulk(instructions)
+	err = execution.Create()
 	if err != nil {
-		return
+		return execution, errmsg.InternalServerError(err)
 	}
 
-	return
+	return execution, errmsg.EmptyStatusError
 }
 
 func cacheFlagStage(stage FlagStage) {
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagStageDiff(t *testing.T) {
+	stage := FlagStage{
+		TurnOn:  []string{"submissions", "voting", "judging"},
+		TurnOff: []string{"registration", "teams", "judging"},
+	}
+
+	current := map[string]bool{
+		"registration": true,
+		"teams":        false,
+		"voting":       true,
+		"unrelated":    true,
+	}
+
+	require.Equal(t, []FlagChange{
+		{Flag: "judging", Existed: false, From: false, To: true},
+		{Flag: "registration", Existed: true, From: true, To: false},
+		{Flag: "submissions", Existed: false, From: false, To: true},
+	}, stage.Diff(current), "a flag in both lists is turned on, and flags already set are left out")
+
+	require.Empty(t, FlagStage{}.Diff(current))
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/utils"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// idMaxAttempts bounds how many IDs are drawn before giving up on a free one
+const idMaxAttempts = 10
+
+// isDuplicateID reports whether a write failed because its ID is already taken,
+// rather than because of another unique index
+func isDuplicateID(err error) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), "index: "+db.IDIndex+" ")
+}
+
+// withNewID runs write with IDs drawn from gen until the collection's unique id
+// index accepts one. Checking an ID before writing it would let two writers
+// pick the same one, so the index decides.
+func withNewID(gen func() string, write func(ID string) error) error {
+	for range idMaxAttempts {
+		err := write(gen())
+		if !isDuplicateID(err) {
+			return err
+		}
+	}
+
+	return errors.New("could not generate an unused id")
+}
+
+// WithNewID runs write with a random 6 character ID, drawing another one as
+// long as the ID is already taken
+func WithNewID(write func(ID string) error) error {
+	return withNewID(func() string { return utils.GenID(6) }, write)
+}
+
+// WithNewTeamID runs write with a random team ID, drawing another one as long
+// as the ID is already taken by another team
+func WithNewTeamID(write func(ID string) error) error {
+	return withNewID(utils.GenTeamID, write)
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"backend/internal/db"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func duplicateKey(index string) error {
+	return mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    11000,
+		Message: "E11000 duplicate key error collection: test.votes index: " + index + " dup key: { id: \"abc\" }",
+	}}}
+}
+
+func TestWithNewID(t *testing.T) {
+	drawn := []string{}
+	gen := func() string {
+		drawn = append(drawn, string(rune('a'+len(drawn))))
+		return drawn[len(drawn)-1]
+	}
+
+	// a taken ID is drawn again until one is accepted
+	stored := ""
+	err := withNewID(gen, func(ID string) error {
+		if ID != "c" {
+			return duplicateKey(db.IDIndex)
+		}
+		stored = ID
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "c", stored)
+	require.Equal(t, []string{"a", "b", "c"}, drawn)
+
+	// other duplicates and errors are not about the ID and are returned as is
+	drawn = nil
+	err = withNewID(gen, func(ID string) error { return duplicateKey("judgeID_1_teamID_1") })
+	require.True(t, mongo.IsDuplicateKeyError(err))
+	require.Len(t, drawn, 1)
+
+	failure := errors.New("unreachable")
+	drawn = nil
+	require.Equal(t, failure, withNewID(gen, func(ID string) error { return failure }))
+	require.Len(t, drawn, 1)
+
+	// the number of draws is bounded
+	drawn = nil
+	err = withNewID(gen, func(ID string) error { return duplicateKey(db.IDIndex) })
+	require.Error(t, err)
+	require.False(t, mongo.IsDuplicateKeyError(err))
+	require.Len(t, drawn, idMaxAttempts)
+}
@@ -6,6 +6,7 @@ import (
 	"backend/internal/errmsg"
 	"backend/internal/utils"
 	"encoding/json"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,12 +16,28 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+var JudgeSkipAbsent = "absent"
+var JudgeSkipConflict = "conflict"
+var JudgeSkipTechnical = "technical"
+
 type Judge struct {
-	ID           string    `bson:"id" json:"id"`
-	Name         string    `bson:"name" json:"name"`
-	CurrentTeam  int       `bson:"currentTeam" json:"currentTeam"`
-	Pair         string    `bson:"pair" json:"pair"`
-	NextTeamTime time.Time `bson:"nextTeamTime" json:"nextTeamTime"`
+	ID           string       `bson:"id" json:"id"`
+	Name         string       `bson:"name" json:"name"`
+	CurrentTeam  int          `bson:"currentTeam" json:"currentTeam"`
+	Pair         string       `bson:"pair" json:"pair"`
+	NextTeamTime time.Time    `bson:"nextTeamTime" json:"nextTeamTime"`
+	Visits       []JudgeVisit `bson:"visits" json:"visits"`
+	RetryQueue   []string     `bson:"retryQueue" json:"retryQueue"`
+	RetryTeam    string       `bson:"retryTeam" json:"retryTeam"` // team being retried at the current step
+}
+
+// JudgeVisit is one team a judge was sent to during their rotation
+type JudgeVisit struct {
+	Step       int    `bson:"step" json:"step"`
+	TeamID     string `bson:"teamID" json:"teamID"`
+	Retry      bool   `bson:"retry" json:"retry"`
+	Skipped    bool   `bson:"skipped" json:"skipped"`
+	SkipReason string `bson:"skipReason,omitempty" json:"skipReason,omitempty"`
 }
 
 func (j *Judge) IssueJudgeConnectToken() (token string) {
@@ -60,7 +77,11 @@ func (j *Judge) Initialize() (serr errmsg.StatusError) {
 		return errmsg.JudgeAlreadyExists
 	}
 
-	_, err = db.Judges.InsertOne(db.Ctx, j)
+	err = WithNewID(func(ID string) error {
+		j.ID = ID
+		_, err := db.Judges.InsertOne(db.Ctx, j)
+		return err
+	})
 	if err != nil {
 		return errmsg.InternalServerError(err)
 	}
@@ -149,6 +170,29 @@ func (j *Judge) resolveAssignmentContext() (*judgeAssignmentContext, errmsg.Stat
 	}, errmsg.EmptyStatusError
 }
 
+// finishJudging marks the judge as done with the rotation
+func (j *Judge) finishJudging() errmsg.StatusError {
+	j.CurrentTeam = 9000
+	j.RetryTeam = ""
+
+	result, err := db.Judges.UpdateOne(db.Ctx, bson.M{
+		"id": j.ID,
+	}, bson.M{
+		"$set": bson.M{
+			"currentTeam": j.CurrentTeam,
+			"retryTeam":   j.RetryTeam,
+		},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if result.MatchedCount == 0 {
+		return errmsg.InternalServerError(&errorMessage{message: "judge not found for update"})
+	}
+
+	return errmsg.JudgingFinished
+}
+
 func (j *Judge) GetNextTeam() (teamID string, serr errmsg.StatusError) {
 	context, serr := j.resolveAssignmentContext()
 	if serr != errmsg.EmptyStatusError {
@@ -156,25 +200,6 @@ func (j *Judge) GetNextTeam() (teamID string, serr errmsg.StatusError) {
 	}
 
 	nextStep := j.CurrentTeam + 1
-	if nextStep >= context.steps {
-		j.CurrentTeam = 9000
-
-		result, err := db.Judges.UpdateOne(db.Ctx, bson.M{
-			"id": j.ID,
-		}, bson.M{
-			"$set": bson.M{
-				"currentTeam": j.CurrentTeam,
-			},
-		})
-		if err != nil {
-			return "", errmsg.InternalServerError(err)
-		}
-		if result.MatchedCount == 0 {
-			return "", errmsg.InternalServerError(&errorMessage{message: "judge not found for update"})
-		}
-
-		return "", errmsg.JudgingFinished
-	}
 
 	// Check if all remaining steps are empty
 	hasTeamInRemainingSteps := false
@@ -188,47 +213,49 @@ func (j *Judge) GetNextTeam() (teamID string, serr errmsg.StatusError) {
 		}
 	}
 
-	// If all remaining steps are empty, judging is finished
-	if !hasTeamInRemainingSteps {
-		j.CurrentTeam = 9000
-
-		result, err := db.Judges.UpdateOne(db.Ctx, bson.M{
-			"id": j.ID,
-		}, bson.M{
-			"$set": bson.M{
-				"currentTeam": j.CurrentTeam,
-			},
-		})
-		if err != nil {
-			return "", errmsg.InternalServerError(err)
-		}
-		if result.MatchedCount == 0 {
-			return "", errmsg.InternalServerError(&errorMessage{message: "judge not found for update"})
-		}
-
-		return "", errmsg.JudgingFinished
+	// Once the matrix is exhausted, judging is finished unless skipped teams are waiting for a retry
+	if !hasTeamInRemainingSteps && len(j.RetryQueue) == 0 {
+		return "", j.finishJudging()
 	}
 
 	// Read the assignment for this step from the matrix
 	assignedTeamID := ""
-	if len(context.matrix) > nextStep && len(context.matrix[nextStep]) > context.groupIdx {
-		assignedTeamID = context.matrix[nextStep][context.groupIdx]
+	var row []string
+	if nextStep < context.steps && len(context.matrix) > nextStep {
+		row = context.matrix[nextStep]
+		if len(row) > context.groupIdx {
+			assignedTeamID = row[context.groupIdx]
+		}
+	}
+
+	// Rest steps, and steps past the end of the matrix, are used to retry skipped
+	// teams. A retry waits if another group visits the team at this step.
+	j.RetryTeam = ""
+	if assignedTeamID == "" {
+		for i, queuedTeamID := range j.RetryQueue {
+			if slices.Contains(row, queuedTeamID) {
+				continue
+			}
+			j.RetryTeam = queuedTeamID
+			j.RetryQueue = slices.Delete(j.RetryQueue, i, i+1)
+			assignedTeamID = queuedTeamID
+			break
+		}
 	}
 
 	// Persist the judge's current step (whether resting or working)
 	j.CurrentTeam = nextStep
-
-	// Get wait minutes from settings
-	waitSetting := &Setting{Name: SettingWaitMinutes}
-	if err := waitSetting.Get(); err != errmsg.EmptyStatusError {
-		return "", err
+	if assignedTeamID != "" {
+		j.Visits = append(j.Visits, JudgeVisit{
+			Step:   nextStep,
+			TeamID: assignedTeamID,
+			Retry:  j.RetryTeam != "",
+		})
 	}
 
-	waitMinutes := 5 // default to 5 minutes
-	if val, ok := waitSetting.Value.(string); ok {
-		if parsed, err := strconv.Atoi(val); err == nil {
-			waitMinutes = parsed
-		}
+	waitMinutes, serr := GetJudgingWaitMinutes()
+	if serr != errmsg.EmptyStatusError {
+		return "", serr
 	}
 
 	// Set nextTeamTime to current time + waitMinutes
@@ -241,6 +268,9 @@ func (j *Judge) GetNextTeam() (teamID string, serr errmsg.StatusError) {
 		"$set": bson.M{
 			"currentTeam":  j.CurrentTeam,
 			"nextTeamTime": j.NextTeamTime,
+			"visits":       j.Visits,
+			"retryQueue":   j.RetryQueue,
+			"retryTeam":    j.RetryTeam,
 		},
 	})
 	if err != nil {
@@ -253,7 +283,36 @@ func (j *Judge) GetNextTeam() (teamID string, serr errmsg.StatusError) {
 	return assignedTeamID, errmsg.EmptyStatusError
 }
 
+// GetJudgingWaitMinutes returns how long a judge spends with each team
+func GetJudgingWaitMinutes() (int, errmsg.StatusError) {
+	waitSetting := &Setting{Name: SettingWaitMinutes}
+	if err := waitSetting.Get(); err != errmsg.EmptyStatusError {
+		return 0, err
+	}
+
+	waitMinutes := 5 // default to 5 minutes
+	if val, ok := waitSetting.Value.(string); ok {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			waitMinutes = parsed
+		}
+	}
+
+	return waitMinutes, errmsg.EmptyStatusError
+}
+
 func (j *Judge) GetPreviousTeam() (teamID string, serr errmsg.StatusError) {
+	// Judges that have a visit log use it, since it includes retries and skips
+	if len(j.Visits) > 0 {
+		for i := len(j.Visits) - 1; i >= 0; i-- {
+			visit := j.Visits[i]
+			if visit.Step < j.CurrentTeam && !visit.Skipped {
+				return visit.TeamID, errmsg.EmptyStatusError
+			}
+		}
+
+		return "", errmsg.JudgeResting
+	}
+
 	context, serr := j.resolveAssignmentContext()
 	if serr != errmsg.EmptyStatusError {
 		return "", serr
@@ -292,6 +351,10 @@ func (j *Judge) GetCurrentTeamID() (string, errmsg.StatusError) {
 		return "", errmsg.JudgeResting
 	}
 
+	if j.RetryTeam != "" {
+		return j.RetryTeam, errmsg.EmptyStatusError
+	}
+
 	if currentStep >= context.steps {
 		return "", errmsg.JudgingFinished
 	}
@@ -308,6 +371,134 @@ func (j *Judge) GetCurrentTeamID() (string, errmsg.StatusError) {
 	return assignedTeamID, errmsg.EmptyStatusError
 }
 
+// CurrentVisit returns the visit for the judge's current step, if they are visiting a team
+func (j *Judge) CurrentVisit() (JudgeVisit, bool) {
+	if len(j.Visits) == 0 {
+		return JudgeVisit{}, false
+	}
+
+	last := j.Visits[len(j.Visits)-1]
+	if last.Step != j.CurrentTeam {
+		return JudgeVisit{}, false
+	}
+
+	return last, true
+}
+
+// SkipCurrentTeam marks the judge's current visit as skipped and records the skip.
+// Teams that were absent or had a technical issue are queued to be retried once,
+// later in the rotation.
+func (j *Judge) SkipCurrentTeam(reason string) (skip JudgeSkip, serr errmsg.StatusError) {
+	teamID, serr := j.GetCurrentTeamID()
+	if serr != errmsg.EmptyStatusError {
+		return skip, serr
+	}
+
+	visit, ok := j.CurrentVisit()
+	if !ok || visit.TeamID != teamID {
+		return skip, errmsg.JudgeResting
+	}
+	if visit.Skipped {
+		return skip, errmsg.JudgeSkipDuplicate
+	}
+
+	judged, err := db.Judgments.CountDocuments(db.Ctx, bson.M{
+		"judgeID": j.ID,
+		"step":    j.CurrentTeam,
+		"status":  validJudgmentFilter["status"],
+	})
+	if err != nil {
+		return skip, errmsg.InternalServerError(err)
+	}
+	if judged > 0 {
+		return skip, errmsg.JudgeSkipAlreadyJudged
+	}
+
+	j.Visits[len(j.Visits)-1].Skipped = true
+	j.Visits[len(j.Visits)-1].SkipReason = reason
+
+	skip = JudgeSkip{
+		JudgeID: j.ID,
+		TeamID:  teamID,
+		Step:    j.CurrentTeam,
+		Reason:  reason,
+		Retry:   visit.Retry,
+	}
+
+	if (reason == JudgeSkipAbsent || reason == JudgeSkipTechnical) && !visit.Retry && !slices.Contains(j.RetryQueue, teamID) {
+		j.RetryQueue = append(j.RetryQueue, teamID)
+		skip.Queued = true
+	}
+
+	result, err := db.Judges.UpdateOne(db.Ctx, bson.M{
+		"id": j.ID,
+	}, bson.M{
+		"$set": bson.M{
+			"visits":     j.Visits,
+			"retryQueue": j.RetryQueue,
+		},
+	})
+	if err != nil {
+		return skip, errmsg.InternalServerError(err)
+	}
+	if result.MatchedCount == 0 {
+		return skip, errmsg.InternalServerError(&errorMessage{message: "judge not found for update"})
+	}
+
+	if serr := skip.Create(); serr != errmsg.EmptyStatusError {
+		return skip, serr
+	}
+
+	return skip, errmsg.EmptyStatusError
+}
+
+// ResetJudgingProgress sends every judge back to the start of the rotation, as
+// if they had just been created, for when judging starts over with a new matrix
+func ResetJudgingProgress() errmsg.StatusError {
+	_, err := db.Judges.UpdateMany(db.Ctx, bson.M{}, bson.M{
+		"$set": bson.M{
+			"currentTeam":  -1,
+			"nextTeamTime": time.Time{},
+			"visits":       []JudgeVisit{},
+			"retryQueue":   []string{},
+			"retryTeam":    "",
+		},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// DropRetries takes teams out of the judge's retry queue, and stops retrying
+// the current one if it is among them
+func (j *Judge) DropRetries(teamIDs []string) errmsg.StatusError {
+	j.RetryQueue = slices.DeleteFunc(j.RetryQueue, func(teamID string) bool {
+		return slices.Contains(teamIDs, teamID)
+	})
+	if slices.Contains(teamIDs, j.RetryTeam) {
+		j.RetryTeam = ""
+	}
+
+	result, err := db.Judges.UpdateOne(db.Ctx, bson.M{
+		"id": j.ID,
+	}, bson.M{
+		"$set": bson.M{
+			"retryQueue": j.RetryQueue,
+			"retryTeam":  j.RetryTeam,
+		},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if result.MatchedCount == 0 {
+		return errmsg.InternalServerError(&errorMessage{message: "judge not found for update"})
+	}
+
+	return errmsg.EmptyStatusError
+}
+
 // errorMessage is a simple error wrapper for the InternalServerError function
 type errorMessage struct {
 	message string
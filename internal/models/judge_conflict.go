@@ -0,0 +1,195 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JudgeConflict is a declared conflict of interest between a judge and either a
+// single team or every team with a member from a university
+type JudgeConflict struct {
+	ID         string    `json:"id" bson:"id"`
+	JudgeID    string    `json:"judgeID" bson:"judgeID"`
+	TeamID     string    `json:"teamID,omitempty" bson:"teamID"`
+	University string    `json:"university,omitempty" bson:"university"`
+	Reason     string    `json:"reason" bson:"reason"`
+	ActorRole  string    `json:"actorRole" bson:"actorRole"`
+	ActorID    string    `json:"actorID" bson:"actorID"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// JudgeConflictReasonMaxLength bounds the free-text reason
+var JudgeConflictReasonMaxLength = 500
+
+// normalizeUniversity makes university names comparable regardless of case and spacing
+func normalizeUniversity(university string) string {
+	return strings.ToLower(strings.Join(strings.Fields(university), " "))
+}
+
+// Validate checks that the conflict names exactly one of a team or a university
+func (jc *JudgeConflict) Validate() errmsg.StatusError {
+	jc.TeamID = strings.TrimSpace(jc.TeamID)
+	jc.University = strings.Join(strings.Fields(jc.University), " ")
+	jc.Reason = strings.TrimSpace(jc.Reason)
+
+	if jc.JudgeID == "" {
+		return errmsg.JudgeNotFound
+	}
+	if (jc.TeamID == "") == (jc.University == "") {
+		return errmsg.JudgeConflictInvalid
+	}
+	if len(jc.Reason) > JudgeConflictReasonMaxLength {
+		return errmsg.JudgeConflictInvalid
+	}
+
+	if jc.TeamID != "" {
+		team := Team{ID: jc.TeamID}
+		if err := team.Get(); err != nil {
+			return errmsg.TeamNotFound
+		}
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+func (jc *JudgeConflict) Create() errmsg.StatusError {
+	if serr := jc.Validate(); serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	jc.CreatedAt = time.Now()
+
+	err := WithNewID(func(ID string) error {
+		jc.ID = ID
+		_, err := db.JudgeConflicts.InsertOne(db.Ctx, bson.M{
+			"id":                   jc.ID,
+			"judgeID":              jc.JudgeID,
+			"teamID":               jc.TeamID,
+			"university":           jc.University,
+			"universityNormalized": normalizeUniversity(jc.University),
+			"reason":               jc.Reason,
+			"actorRole":            jc.ActorRole,
+			"actorID":              jc.ActorID,
+			"createdAt":            jc.CreatedAt,
+		})
+		return err
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return errmsg.JudgeConflictDuplicate
+	}
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// DeleteJudgeConflict removes a declared conflict and returns it
+func DeleteJudgeConflict(id string) (jc JudgeConflict, serr errmsg.StatusError) {
+	err := db.JudgeConflicts.FindOneAndDelete(db.Ctx, bson.M{"id": id}).Decode(&jc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return jc, errmsg.JudgeConflictNotFound
+	}
+	if err != nil {
+		return jc, errmsg.InternalServerError(err)
+	}
+
+	return jc, errmsg.EmptyStatusError
+}
+
+// GetJudgeConflicts lists declared conflicts, optionally for a single judge
+func GetJudgeConflicts(judgeID string) (conflicts []JudgeConflict, serr errmsg.StatusError) {
+	filter := bson.M{}
+	if judgeID != "" {
+		filter["judgeID"] = judgeID
+	}
+
+	cursor, err := db.JudgeConflicts.Find(db.Ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	conflicts = []JudgeConflict{}
+	if err = cursor.All(db.Ctx, &conflicts); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return conflicts, errmsg.EmptyStatusError
+}
+
+// JudgeConflictIndex answers whether a judge conflicts with a team, with
+// university conflicts already expanded to the teams of that university
+type JudgeConflictIndex struct {
+	teams map[string]map[string]bool // judgeID -> teamID -> conflicted
+}
+
+// LoadJudgeConflictIndex reads every declared conflict and resolves university
+// conflicts through the universities on the members' accounts
+func LoadJudgeConflictIndex() (index JudgeConflictIndex, serr errmsg.StatusError) {
+	index = JudgeConflictIndex{teams: map[string]map[string]bool{}}
+
+	conflicts, serr := GetJudgeConflicts("")
+	if serr != errmsg.EmptyStatusError {
+		return index, serr
+	}
+
+	universityJudges := map[string][]string{} // normalized university -> judgeIDs
+	for _, conflict := range conflicts {
+		if conflict.TeamID != "" {
+			index.add(conflict.JudgeID, conflict.TeamID)
+			continue
+		}
+		university := normalizeUniversity(conflict.University)
+		universityJudges[university] = append(universityJudges[university], conflict.JudgeID)
+	}
+
+	if len(universityJudges) == 0 {
+		return index, errmsg.EmptyStatusError
+	}
+
+	cursor, err := db.Accounts.Find(db.Ctx,
+		bson.M{"teamID": bson.M{"$ne": ""}, "university": bson.M{"$ne": ""}},
+		options.Find().SetProjection(bson.M{"teamID": 1, "university": 1}),
+	)
+	if err != nil {
+		return index, errmsg.InternalServerError(err)
+	}
+
+	var members []Account
+	if err = cursor.All(db.Ctx, &members); err != nil {
+		return index, errmsg.InternalServerError(err)
+	}
+
+	for _, member := range members {
+		for _, judgeID := range universityJudges[normalizeUniversity(member.University)] {
+			index.add(judgeID, member.TeamID)
+		}
+	}
+
+	return index, errmsg.EmptyStatusError
+}
+
+func (ci JudgeConflictIndex) add(judgeID string, teamID string) {
+	if ci.teams[judgeID] == nil {
+		ci.teams[judgeID] = map[string]bool{}
+	}
+	ci.teams[judgeID][teamID] = true
+}
+
+// Conflicts reports whether the judge has a declared conflict with the team
+func (ci JudgeConflictIndex) Conflicts(judgeID string, teamID string) bool {
+	return ci.teams[judgeID][teamID]
+}
+
+// Violates reports whether the judgment involves a team its judge conflicts with
+func (ci JudgeConflictIndex) Violates(judgment Judgment) bool {
+	return ci.Conflicts(judgment.JudgeID, judgment.WinningTeamID) ||
+		ci.Conflicts(judgment.JudgeID, judgment.LosingTeamID)
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JudgeNoteMaxLength caps a note so the judge app stays responsive
+var JudgeNoteMaxLength = 5000
+
+// JudgeNote is a judge's private note about a team. Only the judge and admins can read it.
+type JudgeNote struct {
+	ID        string    `json:"id" bson:"id"`
+	JudgeID   string    `json:"judgeID" bson:"judgeID"`
+	TeamID    string    `json:"teamID" bson:"teamID"`
+	Text      string    `json:"text" bson:"text"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Save creates the judge's note for the team or replaces its text
+func (n *JudgeNote) Save() errmsg.StatusError {
+	if len([]rune(n.Text)) > JudgeNoteMaxLength {
+		return errmsg.JudgeNoteTooLong
+	}
+
+	now := time.Now()
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetUpsert(true)
+
+	err := WithNewID(func(ID string) error {
+		return db.JudgeNotes.FindOneAndUpdate(
+			db.Ctx,
+			bson.M{"judgeID": n.JudgeID, "teamID": n.TeamID},
+			bson.M{
+				"$set": bson.M{
+					"text":      n.Text,
+					"updatedAt": now,
+				},
+				"$setOnInsert": bson.M{
+					"id":        ID,
+					"createdAt": now,
+				},
+			},
+			opts,
+		).Decode(n)
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// GetJudgeNote loads the judge's note for a team, if they wrote one
+func GetJudgeNote(judgeID string, teamID string) (note JudgeNote, found bool, serr errmsg.StatusError) {
+	err := db.JudgeNotes.FindOne(db.Ctx, bson.M{"judgeID": judgeID, "teamID": teamID}).Decode(&note)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return note, false, errmsg.EmptyStatusError
+	}
+	if err != nil {
+		return note, false, errmsg.InternalServerError(err)
+	}
+
+	return note, true, errmsg.EmptyStatusError
+}
+
+// GetJudgeNotes lists notes, optionally narrowed to one judge or one team
+func GetJudgeNotes(judgeID string, teamID string) (notes []JudgeNote, serr errmsg.StatusError) {
+	filter := bson.M{}
+	if judgeID != "" {
+		filter["judgeID"] = judgeID
+	}
+	if teamID != "" {
+		filter["teamID"] = teamID
+	}
+
+	cursor, err := db.JudgeNotes.Find(db.Ctx, filter, options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}}))
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	notes = []JudgeNote{}
+	if err = cursor.All(db.Ctx, &notes); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return notes, errmsg.EmptyStatusError
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"slices"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JudgeSkip records a judge skipping their current team
+type JudgeSkip struct {
+	ID      string    `json:"id" bson:"id"`
+	JudgeID string    `json:"judgeID" bson:"judgeID"`
+	TeamID  string    `json:"teamID" bson:"teamID"`
+	Step    int       `json:"step" bson:"step"`
+	Reason  string    `json:"reason" bson:"reason"`
+	Retry   bool      `json:"retry" bson:"retry"`   // the skipped visit was itself a retry
+	Queued  bool      `json:"queued" bson:"queued"` // the team was queued to be retried
+	Date    time.Time `json:"date" bson:"date"`
+}
+
+// TeamSkipSummary aggregates the skips recorded for one team
+type TeamSkipSummary struct {
+	TeamID   string         `json:"teamID"`
+	Total    int            `json:"total"`
+	ByReason map[string]int `json:"byReason"`
+	JudgeIDs []string       `json:"judgeIDs"`
+	LastSkip time.Time      `json:"lastSkip"`
+}
+
+// IsValidSkipReason reports whether reason is one of the accepted skip reasons
+func IsValidSkipReason(reason string) bool {
+	return reason == JudgeSkipAbsent || reason == JudgeSkipConflict || reason == JudgeSkipTechnical
+}
+
+func (s *JudgeSkip) Create() errmsg.StatusError {
+	s.Date = time.Now()
+
+	err := WithNewID(func(ID string) error {
+		s.ID = ID
+		_, err := db.JudgeSkips.InsertOne(db.Ctx, s)
+		return err
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+func GetJudgeSkips() (skips []JudgeSkip, serr errmsg.StatusError) {
+	cursor, err := db.JudgeSkips.Find(db.Ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	skips = []JudgeSkip{}
+	if err = cursor.All(db.Ctx, &skips); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return skips, errmsg.EmptyStatusError
+}
+
+// SummarizeSkipsByTeam groups skips per team, most absences first
+func SummarizeSkipsByTeam(skips []JudgeSkip) []TeamSkipSummary {
+	byTeam := map[string]*TeamSkipSummary{}
+	for _, skip := range skips {
+		summary, ok := byTeam[skip.TeamID]
+		if !ok {
+			summary = &TeamSkipSummary{
+				TeamID:   skip.TeamID,
+				ByReason: map[string]int{},
+				JudgeIDs: []string{},
+			}
+			byTeam[skip.TeamID] = summary
+		}
+
+		summary.Total++
+		summary.ByReason[skip.Reason]++
+		if !slices.Contains(summary.JudgeIDs, skip.JudgeID) {
+			summary.JudgeIDs = append(summary.JudgeIDs, skip.JudgeID)
+		}
+		if skip.Date.After(summary.LastSkip) {
+			summary.LastSkip = skip.Date
+		}
+	}
+
+	summaries := make([]TeamSkipSummary, 0, len(byTeam))
+	for _, summary := range byTeam {
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.ByReason[JudgeSkipAbsent] != b.ByReason[JudgeSkipAbsent] {
+			return a.ByReason[JudgeSkipAbsent] > b.ByReason[JudgeSkipAbsent]
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.TeamID < b.TeamID
+	})
+
+	return summaries
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+)
+
+// JudgeWeightExcluded is the weight that leaves a judge's judgments out of scoring
+var JudgeWeightExcluded = 0.0
+
+// JudgeWeightDefault is the weight of a judge nobody has adjusted
+var JudgeWeightDefault = 1.0
+
+// GetJudgeWeights returns the adjusted judge weights, stored in SettingJudgeWeights.
+// Judges missing from the map count fully.
+func GetJudgeWeights() (weights map[string]float64, serr errmsg.StatusError) {
+	weights = map[string]float64{}
+
+	setting := &Setting{Name: SettingJudgeWeights}
+	serr = setting.Get()
+	if serr == errmsg.SettingNotFound {
+		return weights, errmsg.EmptyStatusError
+	}
+	if serr != errmsg.EmptyStatusError {
+		return weights, serr
+	}
+
+	value, ok := setting.Value.(string)
+	if !ok {
+		return weights, errmsg.InternalServerError(errors.New("judge weights setting is not a string"))
+	}
+	if err := json.Unmarshal([]byte(value), &weights); err != nil {
+		return weights, errmsg.InternalServerError(err)
+	}
+
+	return weights, errmsg.EmptyStatusError
+}
+
+// SetJudgeWeight stores how much a judge's judgments count, between 0 (excluded)
+// and 1 (the default, which removes the adjustment)
+func SetJudgeWeight(judgeID string, weight float64, changedBy string) errmsg.StatusError {
+	if weight < JudgeWeightExcluded || weight > JudgeWeightDefault {
+		return errmsg.JudgeWeightInvalid
+	}
+
+	judge := Judge{ID: judgeID}
+	if err := judge.Get(); err != nil {
+		return errmsg.JudgeNotFound
+	}
+
+	weights, serr := GetJudgeWeights()
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	if weight == JudgeWeightDefault {
+		delete(weights, judgeID)
+	} else {
+		weights[judgeID] = weight
+	}
+
+	weightsJSON, err := json.Marshal(weights)
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	setting := &Setting{Name: SettingJudgeWeights, Value: string(weightsJSON)}
+	return setting.Save(changedBy)
+}
+
+// WeighJudgments converts judgments for the scorer with their judge's weight,
+// dropping the judgments of excluded judges
+func WeighJudgments(judgments []Judgment) (kept []Judgment, scorerJudgments []utils.JudgmentWithJudge, serr errmsg.StatusError) {
+	weights, serr := GetJudgeWeights()
+	if serr != errmsg.EmptyStatusError {
+		return nil, nil, serr
+	}
+
+	kept = make([]Judgment, 0, len(judgments))
+	scorerJudgments = make([]utils.JudgmentWithJudge, 0, len(judgments))
+	for _, judgment := range judgments {
+		weight, adjusted := weights[judgment.JudgeID]
+		if !adjusted {
+			weight = JudgeWeightDefault
+		}
+		if weight <= JudgeWeightExcluded {
+			continue
+		}
+
+		kept = append(kept, judgment)
+		scorerJudgments = append(scorerJudgments, utils.JudgmentWithJudge{
+			WinningTeamID: judgment.WinningTeamID,
+			LosingTeamID:  judgment.LosingTeamID,
+			JudgeID:       judgment.JudgeID,
+			Weight:        weight,
+		})
+	}
+
+	return kept, scorerJudgments, errmsg.EmptyStatusError
+}
@@ -3,30 +3,130 @@ package models
 import (
 	"backend/internal/db"
 	"backend/internal/errmsg"
-	"backend/internal/utils"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var JudgmentStatusValid = "valid"
+var JudgmentStatusRetracted = "retracted"
+var JudgmentStatusInvalidated = "invalidated"
+
+// JudgmentStatusArchived marks judgments made under a judging plan that was
+// replaced by a fresh init; their steps mean nothing in the new plan
+var JudgmentStatusArchived = "archived"
+
+// JudgmentRetractWindow is how long a judge can take back their own judgment
+var JudgmentRetractWindow = 2 * time.Minute
+
 type Judgment struct {
-	ID            string    `bson:"id" json:"id"`
-	WinningTeamID string    `bson:"winningTeamID" json:"winningTeamID"`
-	LosingTeamID  string    `bson:"losingTeamID" json:"losingTeamID"`
-	Date          time.Time `bson:"date" json:"date"`
-	JudgeID       string    `bson:"judgeID" json:"judgeID"`
+	ID            string           `bson:"id" json:"id"`
+	WinningTeamID string           `bson:"winningTeamID" json:"winningTeamID"`
+	LosingTeamID  string           `bson:"losingTeamID" json:"losingTeamID"`
+	Date          time.Time        `bson:"date" json:"date"`
+	JudgeID       string           `bson:"judgeID" json:"judgeID"`
+	Step          int              `bson:"step" json:"step"`
+	Status        string           `bson:"status" json:"status"`
+	History       []JudgmentChange `bson:"history" json:"history"`
+}
+
+// JudgmentChange is one entry in a judgment's audit trail
+type JudgmentChange struct {
+	Status    string    `bson:"status" json:"status"`
+	ActorRole string    `bson:"actorRole" json:"actorRole"`
+	ActorID   string    `bson:"actorID" json:"actorID"`
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	Date      time.Time `bson:"date" json:"date"`
+}
+
+// validJudgmentFilter matches valid judgments, including ones stored before statuses existed
+var validJudgmentFilter = bson.M{
+	"status": bson.M{"$nin": []string{JudgmentStatusRetracted, JudgmentStatusInvalidated, JudgmentStatusArchived}},
+}
+
+// Validate checks that the judgment compares the judge's current and previous
+// teams, neither of them skipped, that the judge has spent the wait window on
+// the current team, and that no judgment was recorded yet for this step. On
+// success the step is recorded.
+func (j *Judgment) Validate(judge Judge) errmsg.StatusError {
+	if j.WinningTeamID == j.LosingTeamID {
+		return errmsg.JudgmentSameTeam
+	}
+
+	currentTeamID, serr := judge.GetCurrentTeamID()
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	if visit, ok := judge.CurrentVisit(); ok && visit.Skipped {
+		return errmsg.JudgmentTeamSkipped
+	}
+
+	previousTeamID, serr := judge.GetPreviousTeam()
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	assigned := map[string]bool{currentTeamID: true, previousTeamID: true}
+	if !assigned[j.WinningTeamID] || !assigned[j.LosingTeamID] {
+		return errmsg.JudgmentTeamNotAssigned
+	}
+
+	for _, teamID := range []string{j.WinningTeamID, j.LosingTeamID} {
+		team := Team{ID: teamID}
+		if err := team.Get(); err != nil {
+			return errmsg.TeamNotFound
+		}
+		if team.Deleted {
+			return errmsg.JudgmentTeamDeleted
+		}
+	}
+
+	if time.Now().Before(judge.NextTeamTime) {
+		return errmsg.JudgmentTooEarly
+	}
+
+	count, err := db.Judgments.CountDocuments(db.Ctx, bson.M{
+		"judgeID": judge.ID,
+		"step":    judge.CurrentTeam,
+		"status":  validJudgmentFilter["status"],
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if count > 0 {
+		return errmsg.JudgmentDuplicate
+	}
+
+	j.JudgeID = judge.ID
+	j.Step = judge.CurrentTeam
+
+	return errmsg.EmptyStatusError
 }
 
 func (j *Judgment) Create() (err error) {
-	j.ID = utils.GenID(6)
 	j.Date = time.Now()
+	j.Status = JudgmentStatusValid
+	j.History = []JudgmentChange{{
+		Status:    JudgmentStatusValid,
+		ActorRole: "judge",
+		ActorID:   j.JudgeID,
+		Date:      j.Date,
+	}}
 
-	_, err = db.Judgments.InsertOne(db.Ctx, j)
-	if err != nil {
+	return WithNewID(func(ID string) error {
+		j.ID = ID
+		_, err := db.Judgments.InsertOne(db.Ctx, j)
 		return err
-	}
+	})
+}
 
-	return nil
+// IsDuplicateJudgment reports whether a Create error came from a second judgment
+// for the same judge and step racing past Validate
+func IsDuplicateJudgment(err error) bool {
+	return mongo.IsDuplicateKeyError(err)
 }
 
 func (j *Judgment) Get() (err error) {
@@ -37,15 +137,138 @@ func (j *Judgment) Get() (err error) {
 	return err
 }
 
-func GetAllJudgments() (judgments []Judgment, serr errmsg.StatusError) {
-	cursor, err := db.Judgments.Find(db.Ctx, bson.M{})
+// Retract lets a judge take back their own judgment shortly after submitting it
+func (j *Judgment) Retract(judgeID string) errmsg.StatusError {
+	if err := j.Get(); err != nil {
+		return errmsg.JudgmentNotFound
+	}
+
+	if j.JudgeID != judgeID {
+		return errmsg.JudgmentNotFound
+	}
+
+	if time.Since(j.Date) > JudgmentRetractWindow {
+		return errmsg.JudgmentRetractWindowClosed
+	}
+
+	return j.changeStatus(JudgmentChange{
+		Status:    JudgmentStatusRetracted,
+		ActorRole: "judge",
+		ActorID:   judgeID,
+	})
+}
+
+// Invalidate lets a superuser discard a judgment, recording why
+func (j *Judgment) Invalidate(superuser string, reason string) errmsg.StatusError {
+	if err := j.Get(); err != nil {
+		return errmsg.JudgmentNotFound
+	}
+
+	return j.changeStatus(JudgmentChange{
+		Status:    JudgmentStatusInvalidated,
+		ActorRole: "superuser",
+		ActorID:   superuser,
+		Reason:    reason,
+	})
+}
+
+// ArchiveJudgments archives every valid judgment when judging starts over, so
+// judges can judge the new plan's steps again. It returns how many were archived.
+func ArchiveJudgments(superuser string) (int64, errmsg.StatusError) {
+	change := JudgmentChange{
+		Status:    JudgmentStatusArchived,
+		ActorRole: "superuser",
+		ActorID:   superuser,
+		Reason:    "judging was initialized again",
+		Date:      time.Now(),
+	}
+
+	result, err := db.Judgments.UpdateMany(db.Ctx, validJudgmentFilter, bson.M{
+		"$set":  bson.M{"status": change.Status},
+		"$push": bson.M{"history": change},
+	})
+	if err != nil {
+		return 0, errmsg.InternalServerError(err)
+	}
+
+	return result.ModifiedCount, errmsg.EmptyStatusError
+}
+
+// changeStatus moves a valid judgment to a new status and appends the change to
+// its history. The status check is part of the update so concurrent changes
+// cannot both apply.
+func (j *Judgment) changeStatus(change JudgmentChange) errmsg.StatusError {
+	change.Date = time.Now()
+
+	filter := bson.M{
+		"id":     j.ID,
+		"status": validJudgmentFilter["status"],
+	}
+	result, err := db.Judgments.UpdateOne(db.Ctx, filter, bson.M{
+		"$set":  bson.M{"status": change.Status},
+		"$push": bson.M{"history": change},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if result.MatchedCount == 0 {
+		return errmsg.JudgmentNotValid
+	}
+
+	j.Status = change.Status
+	j.History = append(j.History, change)
+
+	return errmsg.EmptyStatusError
+}
+
+// GetJudgments lists judgments of any status, optionally narrowed to one judge or status
+func GetJudgments(judgeID string, status string) (judgments []Judgment, serr errmsg.StatusError) {
+	filter := bson.M{}
+	if judgeID != "" {
+		filter["judgeID"] = judgeID
+	}
+	if status == JudgmentStatusValid {
+		filter["status"] = validJudgmentFilter["status"]
+	} else if status != "" {
+		filter["status"] = status
+	}
+
+	cursor, err := db.Judgments.Find(db.Ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
 	if err != nil {
 		return nil, errmsg.InternalServerError(err)
 	}
 
+	judgments = []Judgment{}
 	if err = cursor.All(db.Ctx, &judgments); err != nil {
 		return nil, errmsg.InternalServerError(err)
 	}
 
 	return judgments, errmsg.EmptyStatusError
 }
+
+// GetAllJudgments returns the judgments fed to the scorer: valid ones whose judge
+// has no declared conflict with either team
+func GetAllJudgments() (judgments []Judgment, serr errmsg.StatusError) {
+	cursor, err := db.Judgments.Find(db.Ctx, validJudgmentFilter)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	var all []Judgment
+	if err = cursor.All(db.Ctx, &all); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	conflicts, serr := LoadJudgeConflictIndex()
+	if serr != errmsg.EmptyStatusError {
+		return nil, serr
+	}
+
+	for _, judgment := range all {
+		if !conflicts.Violates(judgment) {
+			judgments = append(judgments, judgment)
+		}
+	}
+
+	return judgments, errmsg.EmptyStatusError
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const leaderboardID = "leaderboard"
+
+// leaderboardMaxRetries bounds the optimistic update loop when judgments race
+const leaderboardMaxRetries = 5
+
+// leaderboardCacheTTL bounds how long the cached standings outlive the stored
+// ones, such as after the leaderboard was reset and its version started over
+const leaderboardCacheTTL = 10 * time.Minute
+
+var LeaderboardSourceIncremental = "incremental"
+var LeaderboardSourceRefit = "refit"
+
+type LeaderboardTeam struct {
+	TeamID    string  `json:"teamID" bson:"teamID"`
+	Rank      int     `json:"rank" bson:"rank"`
+	Mu        float64 `json:"mu" bson:"mu"`
+	SigmaSq   float64 `json:"sigmaSq" bson:"sigmaSq"`
+	Judgments int     `json:"judgments" bson:"judgments"`
+}
+
+// Leaderboard holds the latest Crowd-BT standings. It is updated online on every
+// judgment and replaced wholesale whenever rankings are recomputed from scratch.
+type Leaderboard struct {
+	ID             string              `json:"-" bson:"_id"`
+	Version        int64               `json:"version" bson:"version"`
+	Source         string              `json:"source" bson:"source"`
+	Teams          []LeaderboardTeam   `json:"teams" bson:"teams"`
+	JudgmentCount  int                 `json:"judgmentCount" bson:"judgmentCount"`
+	UpdatedAt      time.Time           `json:"updatedAt" bson:"updatedAt"`
+	LastJudgmentAt time.Time           `json:"lastJudgmentAt" bson:"lastJudgmentAt"`
+	Options        utils.ScorerOptions `json:"-" bson:"options"`
+	State          utils.ScorerState   `json:"-" bson:"state"`
+}
+
+// GetLeaderboard returns the stored standings, or an empty leaderboard if none exist yet
+func GetLeaderboard() (lb Leaderboard, serr errmsg.StatusError) {
+	if loadLeaderboardFromCache(&lb) {
+		return lb, errmsg.EmptyStatusError
+	}
+
+	lb, err := findLeaderboard()
+	if err != nil {
+		return lb, errmsg.InternalServerError(err)
+	}
+
+	cacheLeaderboard(lb)
+
+	return lb, errmsg.EmptyStatusError
+}
+
+// ApplyJudgmentToLeaderboard folds a single judgment into the stored standings.
+// Concurrent writers are serialized through the version field; a writer that
+// loses the race reloads the latest state and tries again. Judgments that
+// violate a declared conflict or come from an excluded judge are left out, as in
+// a full refit.
+func ApplyJudgmentToLeaderboard(judgment Judgment) errmsg.StatusError {
+	conflicts, serr := LoadJudgeConflictIndex()
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+	if conflicts.Violates(judgment) {
+		return errmsg.EmptyStatusError
+	}
+
+	// Judgments of excluded judges are left out too, and the rest keep their judge's weight
+	_, weighed, serr := WeighJudgments([]Judgment{judgment})
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+	if len(weighed) == 0 {
+		return errmsg.EmptyStatusError
+	}
+
+	for range leaderboardMaxRetries {
+		lb, err := findLeaderboard()
+		if err != nil {
+			return errmsg.InternalServerError(err)
+		}
+
+		scorer := utils.NewCrowdBTScorerWithOptions(lb.Options)
+		scorer.LoadState(lb.State)
+		scorer.Observe(weighed[0])
+
+		counts := map[string]int{}
+		for _, team := range lb.Teams {
+			counts[team.TeamID] = team.Judgments
+		}
+		counts[judgment.WinningTeamID]++
+		counts[judgment.LosingTeamID]++
+
+		next := lb
+		next.Source = LeaderboardSourceIncremental
+		next.Teams = buildLeaderboardTeams(scorer, counts)
+		next.JudgmentCount = lb.JudgmentCount + 1
+		next.UpdatedAt = time.Now()
+		if judgment.Date.After(lb.LastJudgmentAt) {
+			next.LastJudgmentAt = judgment.Date
+		}
+		next.State = scorer.State()
+
+		saved, err := replaceLeaderboard(lb.Version, next)
+		if err != nil {
+			return errmsg.InternalServerError(err)
+		}
+		if saved {
+			return errmsg.EmptyStatusError
+		}
+	}
+
+	return errmsg.InternalServerError(errors.New("leaderboard update conflicted too many times"))
+}
+
+// SaveLeaderboard replaces the stored standings with a full refit of the scorer
+// over the given judgments. Later incremental updates reuse the refit's options.
+func SaveLeaderboard(scorer *utils.CrowdBTScorer, opts utils.ScorerOptions, judgments []Judgment) errmsg.StatusError {
+	counts := map[string]int{}
+	var lastJudgmentAt time.Time
+	for _, judgment := range judgments {
+		counts[judgment.WinningTeamID]++
+		counts[judgment.LosingTeamID]++
+		if judgment.Date.After(lastJudgmentAt) {
+			lastJudgmentAt = judgment.Date
+		}
+	}
+
+	for range leaderboardMaxRetries {
+		lb, err := findLeaderboard()
+		if err != nil {
+			return errmsg.InternalServerError(err)
+		}
+
+		next := Leaderboard{
+			ID:             leaderboardID,
+			Version:        lb.Version,
+			Source:         LeaderboardSourceRefit,
+			Teams:          buildLeaderboardTeams(scorer, counts),
+			JudgmentCount:  len(judgments),
+			UpdatedAt:      time.Now(),
+			LastJudgmentAt: lastJudgmentAt,
+			Options:        opts,
+			State:          scorer.State(),
+		}
+
+		saved, err := replaceLeaderboard(lb.Version, next)
+		if err != nil {
+			return errmsg.InternalServerError(err)
+		}
+		if saved {
+			return errmsg.EmptyStatusError
+		}
+	}
+
+	return errmsg.InternalServerError(errors.New("leaderboard update conflicted too many times"))
+}
+
+// RebuildLeaderboard refits the standings from all valid judgments with the
+// options of the last refit. It is used when a judgment stops counting, which an
+// online update cannot undo.
+func RebuildLeaderboard() errmsg.StatusError {
+	lb, err := findLeaderboard()
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	judgments, serr := GetAllJudgments()
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	judgments, scorerJudgments, serr := WeighJudgments(judgments)
+	if serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	scorer := utils.NewCrowdBTScorerWithOptions(lb.Options)
+	scorer.Score(scorerJudgments)
+
+	return SaveLeaderboard(scorer, lb.Options, judgments)
+}
+
+func buildLeaderboardTeams(scorer *utils.CrowdBTScorer, counts map[string]int) []LeaderboardTeam {
+	mu := scorer.GetTeamScores()
+	sigmaSq := scorer.GetTeamUncertainty()
+
+	teams := []LeaderboardTeam{}
+	for i, teamID := range scorer.RankTeams() {
+		teams = append(teams, LeaderboardTeam{
+			TeamID:    teamID,
+			Rank:      i + 1,
+			Mu:        mu[teamID],
+			SigmaSq:   sigmaSq[teamID],
+			Judgments: counts[teamID],
+		})
+	}
+
+	return teams
+}
+
+// findLeaderboard reads the stored standings from Mongo, bypassing the cache
+func findLeaderboard() (lb Leaderboard, err error) {
+	err = db.Leaderboard.FindOne(db.Ctx, bson.M{"_id": leaderboardID}).Decode(&lb)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Leaderboard{
+			ID:      leaderboardID,
+			Teams:   []LeaderboardTeam{},
+			Options: utils.DefaultScorerOptions(),
+		}, nil
+	}
+
+	return lb, err
+}
+
+// replaceLeaderboard writes next if the stored version still matches. It reports
+// false when another writer got there first.
+func replaceLeaderboard(version int64, next Leaderboard) (bool, error) {
+	next.ID = leaderboardID
+	next.Version = version + 1
+
+	_, err := db.Leaderboard.ReplaceOne(
+		db.Ctx,
+		bson.M{"_id": leaderboardID, "version": version},
+		next,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		// the upsert collides on _id when the version moved underneath us
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cacheLeaderboard(next)
+
+	return true, nil
+}
+
+// cacheLeaderboard caches the standings unless a later version is cached already,
+// since concurrent writers and readers can get here in any order
+func cacheLeaderboard(lb Leaderboard) {
+	bytes, err := json.Marshal(lb)
+	if err != nil {
+		return
+	}
+
+	_ = db.CacheSetBytesIfNotOlder(leaderboardCacheKey(), lb.Version, bytes, leaderboardCacheTTL)
+}
+
+func loadLeaderboardFromCache(lb *Leaderboard) bool {
+	bytes, err := db.CacheGetBytes(leaderboardCacheKey())
+	if err != nil || len(bytes) == 0 {
+		return false
+	}
+
+	if err := json.Unmarshal(bytes, lb); err != nil {
+		_ = db.CacheDel(leaderboardCacheKey())
+		return false
+	}
+
+	return true
+}
+
+func leaderboardCacheKey() string {
+	return "leaderboard"
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RubricCriterion struct {
+	ID          string  `json:"id" bson:"id"`
+	Name        string  `json:"name" bson:"name"`
+	Description string  `json:"description" bson:"description"`
+	Weight      float64 `json:"weight" bson:"weight"`
+	Min         int     `json:"min" bson:"min"`
+	Max         int     `json:"max" bson:"max"`
+}
+
+// Rubric is the set of criteria judges score each team on, stored in SettingJudgingRubric
+type Rubric struct {
+	Criteria []RubricCriterion `json:"criteria" bson:"criteria"`
+}
+
+// DefaultRubric is used until a superuser configures one
+func DefaultRubric() Rubric {
+	return Rubric{
+		Criteria: []RubricCriterion{
+			{ID: "innovation", Name: "Innovation", Weight: 1, Min: 1, Max: 5},
+			{ID: "technical", Name: "Technical depth", Weight: 1, Min: 1, Max: 5},
+			{ID: "design", Name: "Design", Weight: 1, Min: 1, Max: 5},
+			{ID: "presentation", Name: "Presentation", Weight: 1, Min: 1, Max: 5},
+		},
+	}
+}
+
+// Validate reports whether the rubric can be used for scoring
+func (r Rubric) Validate() error {
+	if len(r.Criteria) == 0 {
+		return errors.New("rubric needs at least one criterion")
+	}
+
+	seen := map[string]bool{}
+	for _, criterion := range r.Criteria {
+		if criterion.ID == "" {
+			return errors.New("criterion id is required")
+		}
+		if seen[criterion.ID] {
+			return errors.New("duplicate criterion id " + criterion.ID)
+		}
+		seen[criterion.ID] = true
+
+		if criterion.Weight <= 0 {
+			return errors.New("criterion weight must be positive")
+		}
+		if criterion.Min >= criterion.Max {
+			return errors.New("criterion scale must have min below max")
+		}
+	}
+
+	return nil
+}
+
+// Weights returns the criterion weights keyed by criterion ID
+func (r Rubric) Weights() map[string]float64 {
+	weights := map[string]float64{}
+	for _, criterion := range r.Criteria {
+		weights[criterion.ID] = criterion.Weight
+	}
+	return weights
+}
+
+// GetRubric returns the configured rubric, or the default one if none was saved
+func GetRubric() (rubric Rubric, serr errmsg.StatusError) {
+	setting := &Setting{Name: SettingJudgingRubric}
+	serr = setting.Get()
+	if serr == errmsg.SettingNotFound {
+		return DefaultRubric(), errmsg.EmptyStatusError
+	}
+	if serr != errmsg.EmptyStatusError {
+		return rubric, serr
+	}
+
+	value, ok := setting.Value.(string)
+	if !ok {
+		return rubric, errmsg.InternalServerError(errors.New("rubric setting is not a string"))
+	}
+	if err := json.Unmarshal([]byte(value), &rubric); err != nil {
+		return rubric, errmsg.InternalServerError(err)
+	}
+
+	return rubric, errmsg.EmptyStatusError
+}
+
+// SaveRubric validates and stores the rubric
+func SaveRubric(rubric Rubric, changedBy string) errmsg.StatusError {
+	if err := rubric.Validate(); err != nil {
+		return errmsg.JudgingInvalidRubric
+	}
+
+	rubricJSON, err := json.Marshal(rubric)
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	setting := &Setting{Name: SettingJudgingRubric, Value: string(rubricJSON)}
+	return setting.Save(changedBy)
+}
+
+// RubricScore is one judge's rubric scores for one team
+type RubricScore struct {
+	ID        string         `json:"id" bson:"id"`
+	JudgeID   string         `json:"judgeID" bson:"judgeID"`
+	TeamID    string         `json:"teamID" bson:"teamID"`
+	Step      int            `json:"step" bson:"step"`
+	Scores    map[string]int `json:"scores" bson:"scores"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate checks that every rubric criterion is scored within its scale and nothing else is
+func (rs *RubricScore) Validate(rubric Rubric) errmsg.StatusError {
+	if len(rs.Scores) != len(rubric.Criteria) {
+		return errmsg.RubricScoreInvalid
+	}
+
+	for _, criterion := range rubric.Criteria {
+		score, ok := rs.Scores[criterion.ID]
+		if !ok || score < criterion.Min || score > criterion.Max {
+			return errmsg.RubricScoreInvalid
+		}
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// Save stores the judge's scores for the team, replacing earlier scores for the same team
+func (rs *RubricScore) Save() errmsg.StatusError {
+	now := time.Now()
+	rs.UpdatedAt = now
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetUpsert(true)
+
+	err := WithNewID(func(ID string) error {
+		return db.RubricScores.FindOneAndUpdate(
+			db.Ctx,
+			bson.M{"judgeID": rs.JudgeID, "teamID": rs.TeamID},
+			bson.M{
+				"$set": bson.M{
+					"step":      rs.Step,
+					"scores":    rs.Scores,
+					"updatedAt": rs.UpdatedAt,
+				},
+				"$setOnInsert": bson.M{
+					"id":        ID,
+					"createdAt": now,
+				},
+			},
+			opts,
+		).Decode(rs)
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// GetRubricScore loads the judge's scores for a team, if any
+func GetRubricScore(judgeID string, teamID string) (rs RubricScore, found bool, serr errmsg.StatusError) {
+	err := db.RubricScores.FindOne(db.Ctx, bson.M{"judgeID": judgeID, "teamID": teamID}).Decode(&rs)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return rs, false, errmsg.EmptyStatusError
+	}
+	if err != nil {
+		return rs, false, errmsg.InternalServerError(err)
+	}
+
+	return rs, true, errmsg.EmptyStatusError
+}
+
+func GetAllRubricScores() (scores []RubricScore, serr errmsg.StatusError) {
+	cursor, err := db.RubricScores.Find(db.Ctx, bson.M{})
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	scores = []RubricScore{}
+	if err = cursor.All(db.Ctx, &scores); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return scores, errmsg.EmptyStatusError
+}
@@ -14,12 +14,10 @@ import (
 var SettingBadgePileSalt = "badgePileSalt"
 var SettingJudgeToGroupIndex = "judgeToGroupIndex"
 var SettingJudgeInitMatrix = "judgeInitMatrix"
-var SettingFinalist1 = "finalist_1"
-var SettingFinalist2 = "finalist_2"
-var SettingFinalist3 = "finalist_3"
-var SettingFinalist4 = "finalist_4"
-var SettingFinalist5 = "finalist_5"
+var SettingFinalists = "finalists"
 var SettingWaitMinutes = "waitMinutes"
+var SettingJudgingRubric = "judgingRubric"
+var SettingJudgeWeights = "judgeWeights"
 
 type Setting struct {
 	Name  string `json:"name" bson:"name"`
@@ -52,7 +50,7 @@ func (s *Setting) Get() errmsg.StatusError {
 	return errmsg.EmptyStatusError
 }
 
-func (s *Setting) Update() errmsg.StatusError {
+func (s *Setting) Update(changedBy string) errmsg.StatusError {
 	if s.Name == "" {
 		return errmsg.SettingIncomplete
 	}
@@ -61,13 +59,12 @@ func (s *Setting) Update() errmsg.StatusError {
 		"value": s.Value,
 	}
 
-	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var before Setting
 	err := db.Settings.FindOneAndUpdate(
 		db.Ctx,
 		bson.M{"name": s.Name},
 		bson.M{"$set": update},
-		opts,
-	).Decode(s)
+	).Decode(&before)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return errmsg.SettingNotFound
@@ -78,40 +75,59 @@ func (s *Setting) Update() errmsg.StatusError {
 
 	cacheSetting(*s)
 
-	return errmsg.EmptyStatusError
+	return s.record(&before, changedBy, "setting updated")
+}
+
+func (s *Setting) Delete(changedBy string) errmsg.StatusError {
+	return s.delete(changedBy, "setting deleted")
 }
 
-func (s *Setting) Delete() errmsg.StatusError {
+func (s *Setting) delete(changedBy string, source string) errmsg.StatusError {
 	if s.Name == "" {
 		return errmsg.SettingIncomplete
 	}
 
-	res, err := db.Settings.DeleteOne(
+	var before Setting
+	err := db.Settings.FindOneAndDelete(
 		db.Ctx,
 		bson.M{"name": s.Name},
-	)
+	).Decode(&before)
 	if err != nil {
-		return errmsg.InternalServerError(err)
-	}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errmsg.SettingNotFound
+		}
 
-	if res.DeletedCount == 0 {
-		return errmsg.SettingNotFound
+		return errmsg.InternalServerError(err)
 	}
 
 	invalidateSettingCache(s.Name)
 
+	err = recordConfigChanges([]ConfigChange{{
+		Kind:    ConfigKindSetting,
+		Key:     s.Name,
+		Existed: true,
+		Old:     before.Value,
+		Removed: true,
+	}}, changedBy, source)
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
 	return errmsg.EmptyStatusError
 }
 
-func (s *Setting) Save() errmsg.StatusError {
+func (s *Setting) Save(changedBy string) errmsg.StatusError {
+	return s.save(changedBy, "setting saved")
+}
+
+func (s *Setting) save(changedBy string, source string) errmsg.StatusError {
 	if s.Name == "" {
 		return errmsg.SettingIncomplete
 	}
 
-	opts := options.FindOneAndUpdate().
-		SetReturnDocument(options.After).
-		SetUpsert(true)
+	opts := options.FindOneAndUpdate().SetUpsert(true)
 
+	var before Setting
 	err := db.Settings.FindOneAndUpdate(
 		db.Ctx,
 		bson.M{"name": s.Name},
@@ -121,13 +137,40 @@ func (s *Setting) Save() errmsg.StatusError {
 			},
 		},
 		opts,
-	).Decode(s)
+	).Decode(&before)
+
+	// the setting was created
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = nil
+		before = Setting{}
+	}
 	if err != nil {
 		return errmsg.InternalServerError(err)
 	}
 
 	cacheSetting(*s)
 
+	var previous *Setting
+	if before.Name != "" {
+		previous = &before
+	}
+
+	return s.record(previous, changedBy, source)
+}
+
+// record adds the change from before, nil when the setting didn't exist, to
+// the history
+func (s *Setting) record(before *Setting, changedBy string, source string) errmsg.StatusError {
+	change := ConfigChange{Kind: ConfigKindSetting, Key: s.Name, New: s.Value}
+	if before != nil {
+		change.Existed = true
+		change.Old = before.Value
+	}
+
+	if err := recordConfigChanges([]ConfigChange{change}, changedBy, source); err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
 	return errmsg.EmptyStatusError
 }
 
@@ -3,7 +3,6 @@ package models
 import (
 	"backend/internal/db"
 	"backend/internal/errmsg"
-	"backend/internal/utils"
 	"encoding/json"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -28,14 +27,17 @@ type Team struct {
 }
 
 func (t *Team) Create(firstMember string) (err error) {
-	t.ID = utils.GenTeamID()
 	t.Name = "New Team"
 	t.Members = []string{
 		firstMember,
 	}
 	t.Deleted = false
 
-	_, err = db.Teams.InsertOne(db.Ctx, t)
+	err = WithNewTeamID(func(ID string) error {
+		t.ID = ID
+		_, err := db.Teams.InsertOne(db.Ctx, t)
+		return err
+	})
 	if err != nil {
 		return
 	}
@@ -2,9 +2,10 @@ package models
 
 import (
 	"backend/internal/db"
-	"backend/internal/utils"
 	"crypto/rand"
 	"encoding/hex"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,16 +13,24 @@ import (
 
 type Vote struct {
 	ID           string    `bson:"id" json:"id"`
-	Choice       string    `bson:"choice" json:"choice"`
+	SessionID    string    `bson:"sessionID" json:"sessionID"`
+	Choice       string    `bson:"choice" json:"choice"`   // the single or first choice
+	Choices      []string  `bson:"choices" json:"choices"` // the whole ballot, in preference order for ranked votes
 	CreatedAt    time.Time `bson:"createdAt" json:"createdAt"`
 	BucketedTime time.Time `bson:"bucketedTime" json:"bucketedTime"`
 	Nonce        string    `bson:"nonce" json:"nonce"`
+	// voided votes are kept but no longer counted; VoidID names the VoteVoid that justifies it
+	Voided bool   `bson:"voided" json:"voided"`
+	VoidID string `bson:"voidID,omitempty" json:"voidID,omitempty"`
 }
 
+// VoteBucket is how finely vote times are kept, so that votes can't be matched
+// to the accounts that cast them by their timing
+var VoteBucket = 5 * time.Minute
+
 func (v *Vote) Create() error {
-	v.ID = utils.GenID(6)
 	v.CreatedAt = time.Now()
-	v.BucketedTime = v.CreatedAt.Truncate(5 * time.Minute)
+	v.BucketedTime = v.CreatedAt.Truncate(VoteBucket)
 
 	// Generate random nonce
 	nonceBuf := make([]byte, 8)
@@ -30,17 +39,32 @@ func (v *Vote) Create() error {
 	}
 	v.Nonce = hex.EncodeToString(nonceBuf)
 
-	_, err := db.Votes.InsertOne(db.Ctx, v)
-	return err
+	err := WithNewID(func(ID string) error {
+		v.ID = ID
+		_, err := db.Votes.InsertOne(db.Ctx, v)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	incrementVoteCounts(v)
+
+	return nil
 }
 
-func GetVoteCount(teamID string) (int64, error) {
-	count, err := db.Votes.CountDocuments(db.Ctx, bson.M{"choice": teamID})
-	return count, err
+// voteFilter narrows the counted votes to a voting session; an empty session ID
+// matches every counted vote
+func voteFilter(sessionID string) bson.M {
+	filter := bson.M{"voided": bson.M{"$ne": true}}
+	if sessionID != "" {
+		filter["sessionID"] = sessionID
+	}
+	return filter
 }
 
-func GetAllVotes() ([]Vote, error) {
-	cursor, err := db.Votes.Find(db.Ctx, bson.M{})
+func GetVotes(sessionID string) ([]Vote, error) {
+	cursor, err := db.Votes.Find(db.Ctx, voteFilter(sessionID))
 	if err != nil {
 		return nil, err
 	}
@@ -54,16 +78,161 @@ func GetAllVotes() ([]Vote, error) {
 	return votes, nil
 }
 
-func GetVoteResults(finalists []string) (map[string]int64, error) {
-	results := make(map[string]int64)
+// GetVoteResults counts the first choices for each finalist in a single aggregation
+func GetVoteResults(sessionID string, finalists []string) (map[string]int64, error) {
+	counts, err := AggregateVoteCounts(sessionID)
+	if err != nil {
+		return nil, err
+	}
 
+	results := make(map[string]int64, len(finalists))
 	for _, teamID := range finalists {
-		count, err := GetVoteCount(teamID)
+		results[teamID] = counts.First[teamID]
+	}
+
+	return results, nil
+}
+
+// voteCountsTTL bounds how long cached counters can drift from the votes before
+// they are rebuilt
+var voteCountsTTL = 5 * time.Minute
+
+// VoteCounts is a running tally of a voting session: how many ballots were cast,
+// how often each team was the first choice, and how many ballots named each team
+type VoteCounts struct {
+	Ballots int64
+	First   map[string]int64
+	Any     map[string]int64
+}
+
+// ForMode returns the counts a session's tally reports: approvals in approval
+// sessions, otherwise first choices
+func (vc VoteCounts) ForMode(mode string) map[string]int64 {
+	if mode == VotingModeApproval {
+		return vc.Any
+	}
+	return vc.First
+}
+
+// AggregateVoteCounts counts the session's ballots, first choices and named
+// teams in a single aggregation; an empty session ID counts every vote
+func AggregateVoteCounts(sessionID string) (VoteCounts, error) {
+	pipeline := []bson.M{
+		{"$match": voteFilter(sessionID)},
+		{"$facet": bson.M{
+			"ballots": []bson.M{{"$count": "count"}},
+			"first": []bson.M{
+				{"$group": bson.M{"_id": "$choice", "count": bson.M{"$sum": 1}}},
+			},
+			"any": []bson.M{
+				// votes cast before ballots were recorded only hold their choice
+				{"$project": bson.M{"team": bson.M{"$ifNull": bson.A{"$choices", bson.A{"$choice"}}}}},
+				{"$unwind": "$team"},
+				{"$group": bson.M{"_id": "$team", "count": bson.M{"$sum": 1}}},
+			},
+		}},
+	}
+
+	cursor, err := db.Votes.Aggregate(db.Ctx, pipeline)
+	if err != nil {
+		return VoteCounts{}, err
+	}
+	defer cursor.Close(db.Ctx)
+
+	type teamCount struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	var facets []struct {
+		Ballots []struct {
+			Count int64 `bson:"count"`
+		} `bson:"ballots"`
+		First []teamCount `bson:"first"`
+		Any   []teamCount `bson:"any"`
+	}
+	if err = cursor.All(db.Ctx, &facets); err != nil {
+		return VoteCounts{}, err
+	}
+
+	counts := VoteCounts{First: map[string]int64{}, Any: map[string]int64{}}
+	if len(facets) == 0 {
+		return counts, nil
+	}
+	if len(facets[0].Ballots) > 0 {
+		counts.Ballots = facets[0].Ballots[0].Count
+	}
+	for _, tc := range facets[0].First {
+		counts.First[tc.ID] = tc.Count
+	}
+	for _, tc := range facets[0].Any {
+		counts.Any[tc.ID] = tc.Count
+	}
+
+	return counts, nil
+}
+
+// GetVoteCounts reads the session's counters from the cache, rebuilding them
+// from the votes when they aren't cached
+func GetVoteCounts(sessionID string) (VoteCounts, error) {
+	if cached, err := db.CacheHGetAll(voteCountsCacheKey(sessionID)); err == nil {
+		return parseVoteCounts(cached), nil
+	}
+
+	counts, err := AggregateVoteCounts(sessionID)
+	if err != nil {
+		return counts, err
+	}
+
+	values := map[string]any{"ballots": counts.Ballots}
+	for teamID, count := range counts.First {
+		values["first:"+teamID] = count
+	}
+	for teamID, count := range counts.Any {
+		values["any:"+teamID] = count
+	}
+	_ = db.CacheHSetAll(voteCountsCacheKey(sessionID), values, voteCountsTTL)
+
+	return counts, nil
+}
+
+// InvalidateVoteCounts drops the session's cached counters so the next read rebuilds them
+func InvalidateVoteCounts(sessionID string) {
+	_ = db.CacheDel(voteCountsCacheKey(sessionID))
+}
+
+func incrementVoteCounts(v *Vote) {
+	increments := map[string]int64{
+		"ballots":           1,
+		"first:" + v.Choice: 1,
+	}
+	for _, teamID := range v.Ballot() {
+		increments["any:"+teamID]++
+	}
+
+	_ = db.CacheHIncrByIfExists(voteCountsCacheKey(v.SessionID), increments)
+}
+
+func parseVoteCounts(values map[string]string) VoteCounts {
+	counts := VoteCounts{First: map[string]int64{}, Any: map[string]int64{}}
+	for field, value := range values {
+		n, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
-			return nil, err
+			continue
+		}
+
+		switch {
+		case field == "ballots":
+			counts.Ballots = n
+		case strings.HasPrefix(field, "first:"):
+			counts.First[strings.TrimPrefix(field, "first:")] = n
+		case strings.HasPrefix(field, "any:"):
+			counts.Any[strings.TrimPrefix(field, "any:")] = n
 		}
-		results[teamID] = count
 	}
 
-	return results, nil
+	return counts
+}
+
+func voteCountsCacheKey(sessionID string) string {
+	return "votes:counts:" + sessionID
 }
@@ -0,0 +1,228 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"backend/internal/utils"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VoteVoidJustificationMaxLength bounds the reason recorded for voiding votes
+var VoteVoidJustificationMaxLength = 1000
+
+// accountActivityActions maps the account events counted against vote bursts
+// to the activity they count as
+var accountActivityActions = map[string]string{
+	"account.initialized":   "registrations",
+	"account.register":      "registrations",
+	"account.login.success": "logins",
+	"account.login.failure": "failedLogins",
+}
+
+// VoteVoid records votes in one bucket of a voting session being voided, and why.
+// Votes are never tied to accounts, so a whole bucket is voided, for every team
+// or only for TeamID.
+type VoteVoid struct {
+	ID            string    `json:"id" bson:"id"`
+	SessionID     string    `json:"sessionID" bson:"sessionID"`
+	Bucket        time.Time `json:"bucket" bson:"bucket"`
+	TeamID        string    `json:"teamID" bson:"teamID"` // empty when every team's votes were voided
+	Justification string    `json:"justification" bson:"justification"`
+	Votes         int64     `json:"votes" bson:"votes"`
+	CreatedBy     string    `json:"createdBy" bson:"createdBy"`
+	CreatedAt     time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// GetVoteBucketCounts counts the session's counted votes per bucket and first choice
+func GetVoteBucketCounts(sessionID string) ([]utils.VoteBucketCount, errmsg.StatusError) {
+	pipeline := []bson.M{
+		{"$match": voteFilter(sessionID)},
+		{"$group": bson.M{
+			"_id":   bson.M{"bucket": "$bucketedTime", "team": "$choice"},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := db.Votes.Aggregate(db.Ctx, pipeline)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+	defer cursor.Close(db.Ctx)
+
+	var groups []struct {
+		ID struct {
+			Bucket time.Time `bson:"bucket"`
+			Team   string    `bson:"team"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err = cursor.All(db.Ctx, &groups); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	counts := make([]utils.VoteBucketCount, 0, len(groups))
+	for _, g := range groups {
+		counts = append(counts, utils.VoteBucketCount{Bucket: g.ID.Bucket, TeamID: g.ID.Team, Votes: g.Count})
+	}
+
+	return counts, errmsg.EmptyStatusError
+}
+
+// GetAccountActivity counts account registrations, logins and failed logins per
+// bucket between from and to. Only counts leave the database, never who the
+// events were about.
+func GetAccountActivity(from, to time.Time, bucket time.Duration) ([]utils.AccountActivityBucket, errmsg.StatusError) {
+	actions := make([]string, 0, len(accountActivityActions))
+	for action := range accountActivityActions {
+		actions = append(actions, action)
+	}
+
+	timestamp := bson.M{"$toLong": "$timestamp"}
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"action":    bson.M{"$in": actions},
+			"timestamp": bson.M{"$gte": from, "$lt": to},
+		}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"bucket": bson.M{"$subtract": bson.A{timestamp, bson.M{"$mod": bson.A{timestamp, bucket.Milliseconds()}}}},
+				"action": "$action",
+			},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := db.Events.Aggregate(db.Ctx, pipeline)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+	defer cursor.Close(db.Ctx)
+
+	var groups []struct {
+		ID struct {
+			Bucket int64  `bson:"bucket"`
+			Action string `bson:"action"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err = cursor.All(db.Ctx, &groups); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	byBucket := map[int64]*utils.AccountActivityBucket{}
+	var activity []*utils.AccountActivityBucket
+	for _, g := range groups {
+		a, ok := byBucket[g.ID.Bucket]
+		if !ok {
+			a = &utils.AccountActivityBucket{Bucket: time.UnixMilli(g.ID.Bucket)}
+			byBucket[g.ID.Bucket] = a
+			activity = append(activity, a)
+		}
+
+		switch accountActivityActions[g.ID.Action] {
+		case "registrations":
+			a.Registrations += g.Count
+		case "logins":
+			a.Logins += g.Count
+		case "failedLogins":
+			a.FailedLogins += g.Count
+		}
+	}
+
+	result := make([]utils.AccountActivityBucket, 0, len(activity))
+	for _, a := range activity {
+		result = append(result, *a)
+	}
+
+	return result, errmsg.EmptyStatusError
+}
+
+// Validate checks that the justification is given and that the bucket and team
+// belong to the session
+func (vv *VoteVoid) Validate(session VotingSession) errmsg.StatusError {
+	vv.Justification = strings.TrimSpace(vv.Justification)
+	if vv.Justification == "" || len(vv.Justification) > VoteVoidJustificationMaxLength {
+		return errmsg.VoteVoidInvalid
+	}
+
+	if !vv.Bucket.Truncate(VoteBucket).Equal(vv.Bucket) ||
+		vv.Bucket.Before(session.OpensAt.Truncate(VoteBucket)) ||
+		vv.Bucket.After(session.ClosesAt) {
+		return errmsg.VoteVoidInvalid
+	}
+
+	if vv.TeamID != "" && !session.IsFinalist(vv.TeamID) {
+		return errmsg.VotingInvalidFinalist
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// Create voids the session's counted votes in the bucket, for TeamID or for
+// every team, and records why. Nothing is recorded when no votes matched.
+func (vv *VoteVoid) Create() errmsg.StatusError {
+	vv.CreatedAt = time.Now()
+
+	// The void is stored first, so the ID the votes point to is known to be its own
+	err := WithNewID(func(ID string) error {
+		vv.ID = ID
+		_, err := db.VoteVoids.InsertOne(db.Ctx, vv)
+		return err
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	filter := voteFilter(vv.SessionID)
+	filter["bucketedTime"] = vv.Bucket
+	if vv.TeamID != "" {
+		filter["choice"] = vv.TeamID
+	}
+
+	result, err := db.Votes.UpdateMany(db.Ctx, filter, bson.M{
+		"$set": bson.M{"voided": true, "voidID": vv.ID},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if result.ModifiedCount == 0 {
+		if _, err = db.VoteVoids.DeleteOne(db.Ctx, bson.M{"id": vv.ID}); err != nil {
+			return errmsg.InternalServerError(err)
+		}
+		return errmsg.VoteVoidNoVotes
+	}
+	vv.Votes = result.ModifiedCount
+
+	InvalidateVoteCounts(vv.SessionID)
+
+	_, err = db.VoteVoids.UpdateOne(db.Ctx, bson.M{"id": vv.ID}, bson.M{
+		"$set": bson.M{"votes": vv.Votes},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// GetVoteVoids lists the votes voided in a session, oldest first
+func GetVoteVoids(sessionID string) ([]VoteVoid, errmsg.StatusError) {
+	cursor, err := db.VoteVoids.Find(db.Ctx,
+		bson.M{"sessionID": sessionID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	voids := []VoteVoid{}
+	if err = cursor.All(db.Ctx, &voids); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return voids, errmsg.EmptyStatusError
+}
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"backend/internal/errmsg"
+	"slices"
+)
+
+// RunoffRound is one round of an instant-runoff count
+type RunoffRound struct {
+	Round      int              `json:"round"`
+	Counts     map[string]int64 `json:"counts"`     // ballots held by each remaining team
+	Exhausted  int64            `json:"exhausted"`  // ballots with no remaining team left on them
+	Eliminated string           `json:"eliminated"` // empty in the final round
+}
+
+// VoteTally is the outcome of a voting session under its voting mode
+type VoteTally struct {
+	Mode    string `json:"mode"`
+	Ballots int64  `json:"ballots"`
+	// Votes per team in single-choice sessions, first preferences in ranked
+	// sessions, and approvals in approval sessions
+	Counts      map[string]int64 `json:"counts"`
+	BordaPoints map[string]int64 `json:"bordaPoints,omitempty"`
+	Rounds      []RunoffRound    `json:"rounds,omitempty"`
+	Winner      string           `json:"winner"`
+}
+
+// Ballot returns the teams on the vote, in preference order for ranked votes.
+// Votes cast before ballots were recorded hold only their choice.
+func (v Vote) Ballot() []string {
+	if len(v.Choices) > 0 {
+		return v.Choices
+	}
+	if v.Choice != "" {
+		return []string{v.Choice}
+	}
+	return nil
+}
+
+// TallyPlurality counts each ballot's first choice among the candidates
+func TallyPlurality(ballots [][]string, candidates []string) map[string]int64 {
+	counts := zeroCounts(candidates)
+	for _, ballot := range ballots {
+		for _, teamID := range ballot {
+			if _, ok := counts[teamID]; ok {
+				counts[teamID]++
+				break
+			}
+		}
+	}
+
+	return counts
+}
+
+// TallyApproval counts every candidate approved on each ballot, once per ballot
+func TallyApproval(ballots [][]string, candidates []string) map[string]int64 {
+	counts := zeroCounts(candidates)
+	for _, ballot := range ballots {
+		seen := map[string]bool{}
+		for _, teamID := range ballot {
+			if _, ok := counts[teamID]; ok && !seen[teamID] {
+				counts[teamID]++
+				seen[teamID] = true
+			}
+		}
+	}
+
+	return counts
+}
+
+// TallyBorda gives each ranked candidate n-1 points for first place, n-2 for
+// second and so on, where n is the number of candidates. Unranked candidates
+// get nothing from that ballot.
+func TallyBorda(ballots [][]string, candidates []string) map[string]int64 {
+	points := zeroCounts(candidates)
+	n := int64(len(candidates))
+	for _, ballot := range ballots {
+		place := int64(0)
+		seen := map[string]bool{}
+		for _, teamID := range ballot {
+			if _, ok := points[teamID]; !ok || seen[teamID] {
+				continue
+			}
+			seen[teamID] = true
+			points[teamID] += n - 1 - place
+			place++
+		}
+	}
+
+	return points
+}
+
+// TallyInstantRunoff counts each ballot for its highest-ranked remaining team
+// and eliminates the weakest team until one holds a majority of the ballots
+// still in play. Ties for elimination go to whoever had fewer ballots in the
+// latest earlier round that separates them, then to the team later in
+// candidate order. Without any countable ballot there is no winner.
+func TallyInstantRunoff(ballots [][]string, candidates []string) (rounds []RunoffRound, winner string) {
+	remaining := slices.Clone(candidates)
+	rounds = []RunoffRound{}
+
+	for len(remaining) > 0 {
+		round := RunoffRound{
+			Round:  len(rounds) + 1,
+			Counts: zeroCounts(remaining),
+		}
+		for _, ballot := range ballots {
+			counted := false
+			for _, teamID := range ballot {
+				if _, ok := round.Counts[teamID]; ok {
+					round.Counts[teamID]++
+					counted = true
+					break
+				}
+			}
+			if !counted {
+				round.Exhausted++
+			}
+		}
+
+		continuing := int64(len(ballots)) - round.Exhausted
+		if continuing == 0 {
+			return append(rounds, round), ""
+		}
+
+		leader := remaining[0]
+		for _, teamID := range remaining[1:] {
+			if round.Counts[teamID] > round.Counts[leader] {
+				leader = teamID
+			}
+		}
+		if len(remaining) == 1 || round.Counts[leader]*2 > continuing {
+			return append(rounds, round), leader
+		}
+
+		round.Eliminated = runoffLoser(remaining, round.Counts, rounds)
+		rounds = append(rounds, round)
+		remaining = slices.DeleteFunc(remaining, func(teamID string) bool { return teamID == round.Eliminated })
+	}
+
+	return rounds, ""
+}
+
+// runoffLoser picks the remaining team with the fewest ballots, breaking ties
+// with earlier rounds from the latest back, and then by candidate order
+func runoffLoser(remaining []string, counts map[string]int64, earlier []RunoffRound) string {
+	loser := remaining[0]
+	for _, teamID := range remaining[1:] {
+		if counts[teamID] < counts[loser] {
+			loser = teamID
+			continue
+		}
+		if counts[teamID] > counts[loser] {
+			continue
+		}
+
+		fewer := true // later in candidate order loses a full tie
+		for i := len(earlier) - 1; i >= 0; i-- {
+			if earlier[i].Counts[teamID] != earlier[i].Counts[loser] {
+				fewer = earlier[i].Counts[teamID] < earlier[i].Counts[loser]
+				break
+			}
+		}
+		if fewer {
+			loser = teamID
+		}
+	}
+
+	return loser
+}
+
+// topCandidate returns the candidate with the highest count, the earlier one on ties
+func topCandidate(counts map[string]int64, candidates []string) string {
+	top := ""
+	for _, teamID := range candidates {
+		if counts[teamID] > 0 && (top == "" || counts[teamID] > counts[top]) {
+			top = teamID
+		}
+	}
+	return top
+}
+
+func zeroCounts(candidates []string) map[string]int64 {
+	counts := make(map[string]int64, len(candidates))
+	for _, teamID := range candidates {
+		counts[teamID] = 0
+	}
+	return counts
+}
+
+// TallyVotingSession counts the session's votes under its voting mode
+func TallyVotingSession(session VotingSession) (tally VoteTally, serr errmsg.StatusError) {
+	votes, err := GetVotes(session.ID)
+	if err != nil {
+		return tally, errmsg.InternalServerError(err)
+	}
+
+	ballots := make([][]string, 0, len(votes))
+	for _, vote := range votes {
+		ballots = append(ballots, vote.Ballot())
+	}
+
+	tally = VoteTally{
+		Mode:    session.Mode,
+		Ballots: int64(len(ballots)),
+	}
+
+	switch session.Mode {
+	case VotingModeRanked:
+		tally.Counts = TallyPlurality(ballots, session.Finalists)
+		tally.BordaPoints = TallyBorda(ballots, session.Finalists)
+		tally.Rounds, tally.Winner = TallyInstantRunoff(ballots, session.Finalists)
+	case VotingModeApproval:
+		tally.Counts = TallyApproval(ballots, session.Finalists)
+		tally.Winner = topCandidate(tally.Counts, session.Finalists)
+	default:
+		tally.Counts = TallyPlurality(ballots, session.Finalists)
+		tally.Winner = topCandidate(tally.Counts, session.Finalists)
+	}
+
+	return tally, errmsg.EmptyStatusError
+}
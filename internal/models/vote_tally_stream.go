@@ -0,0 +1,83 @@
+package models
+
+import (
+	"backend/internal/env"
+	"backend/internal/errmsg"
+	"backend/internal/utils"
+	"time"
+
+	sj "github.com/brianvoe/sjwt"
+	"github.com/gofiber/fiber/v3"
+)
+
+// TallyStreamTokenTTL is how long a stream token can be used to open the tally stream
+var TallyStreamTokenTTL = 2 * time.Minute
+
+var tallyStreamTokenPurpose = "tally_stream"
+
+// tallyStreamClaims carry no username or permissions, so a stream token is
+// never accepted where a superuser token is required
+type tallyStreamClaims struct {
+	Purpose   string `json:"purpose"`
+	SessionID string `json:"sessionID"`
+	IssuedBy  string `json:"issuedBy"`
+}
+
+// IssueTallyStreamToken creates a short-lived token that opens the live tally
+// stream of this session only. Browsers' EventSource cannot send an
+// Authorization header, so the stage screen passes it as a query parameter.
+func (vs VotingSession) IssueTallyStreamToken(issuedBy string) (token string, expiresAt time.Time) {
+	expiresAt = time.Now().Add(TallyStreamTokenTTL)
+
+	claims, _ := sj.ToClaims(tallyStreamClaims{
+		Purpose:   tallyStreamTokenPurpose,
+		SessionID: vs.ID,
+		IssuedBy:  issuedBy,
+	})
+	claims.SetExpiresAt(expiresAt)
+
+	token = claims.Generate(env.JWT_SECRET)
+	return token, expiresAt
+}
+
+// ValidTallyStreamToken reports whether the token was issued for this session and hasn't expired
+func (vs VotingSession) ValidTallyStreamToken(token string) bool {
+	if !sj.Verify(token, env.JWT_SECRET) {
+		return false
+	}
+
+	claims, err := sj.Parse(token)
+	if err != nil || claims.Validate() != nil {
+		return false
+	}
+
+	var stream tallyStreamClaims
+	if err := claims.ToStruct(&stream); err != nil {
+		return false
+	}
+
+	return stream.Purpose == tallyStreamTokenPurpose && vs.ID != "" && stream.SessionID == vs.ID
+}
+
+// TallyStreamMiddlewareBuilder lets the tally stream be opened either with a
+// superuser token in the Authorization header or with a stream token for the
+// requested session in the token query parameter
+func TallyStreamMiddlewareBuilder(required []string) fiber.Handler {
+	superuserMiddleware := SuperUserMiddlewareBuilder(required)
+
+	return func(c fiber.Ctx) error {
+		token := c.Query("token")
+		if token == "" {
+			return superuserMiddleware(c)
+		}
+
+		session := VotingSession{ID: c.Query("sessionID")}
+		if !session.ValidTallyStreamToken(token) {
+			return utils.StatusError(c,
+				errmsg.VotingStreamTokenInvalid,
+			)
+		}
+
+		return c.Next()
+	}
+}
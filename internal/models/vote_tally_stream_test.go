@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTallyStreamToken tests that stream tokens only open their own session's stream
+func TestTallyStreamToken(t *testing.T) {
+	session := VotingSession{ID: "stage"}
+
+	token, expiresAt := session.IssueTallyStreamToken("admin")
+	require.True(t, expiresAt.After(time.Now()))
+	require.True(t, session.ValidTallyStreamToken(token))
+
+	require.False(t, VotingSession{ID: "other"}.ValidTallyStreamToken(token))
+	require.False(t, session.ValidTallyStreamToken(token+"x"), "tampered signature")
+	require.False(t, session.ValidTallyStreamToken(""))
+
+	superuser := SuperUser{Username: "admin", Permissions: []string{"admin"}}
+	require.False(t, session.ValidTallyStreamToken(superuser.GenToken()), "superuser tokens aren't stream tokens")
+
+	// a stream token carries no permissions, so it doesn't pass as a superuser token
+	var parsed SuperUser
+	require.NoError(t, parsed.ParseToken(token))
+	require.False(t, parsed.HasAllRoles([]string{"admin"}))
+
+	ttl := TallyStreamTokenTTL
+	TallyStreamTokenTTL = -time.Minute
+	defer func() { TallyStreamTokenTTL = ttl }()
+
+	expired, _ := session.IssueTallyStreamToken("admin")
+	require.False(t, session.ValidTallyStreamToken(expired))
+}
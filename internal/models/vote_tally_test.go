@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTallyInstantRunoff tests elimination, transfers and exhausted ballots
+func TestTallyInstantRunoff(t *testing.T) {
+	candidates := []string{"a", "b", "c", "d"}
+	ballots := [][]string{
+		{"a", "b"}, {"a", "b"}, {"a"}, {"a", "c"},
+		{"b", "c"}, {"b", "a"}, {"b"},
+		{"c", "b"}, {"c", "b"},
+		{"d", "c"},
+	}
+
+	rounds, winner := TallyInstantRunoff(ballots, candidates)
+	require.Equal(t, "b", winner, "b overtakes a once c's ballots transfer")
+	require.Len(t, rounds, 3)
+
+	require.Equal(t, map[string]int64{"a": 4, "b": 3, "c": 2, "d": 1}, rounds[0].Counts)
+	require.Equal(t, "d", rounds[0].Eliminated)
+
+	require.Equal(t, map[string]int64{"a": 4, "b": 3, "c": 3}, rounds[1].Counts)
+	require.Equal(t, "c", rounds[1].Eliminated, "b and c tie, but c had fewer ballots in round 1")
+
+	require.Equal(t, map[string]int64{"a": 4, "b": 5}, rounds[2].Counts)
+	require.Equal(t, int64(1), rounds[2].Exhausted, "d's voter ranked nobody left")
+	require.Empty(t, rounds[2].Eliminated)
+
+	// A first-round majority wins at once
+	rounds, winner = TallyInstantRunoff([][]string{{"a"}, {"a"}, {"b"}}, candidates)
+	require.Equal(t, "a", winner)
+	require.Len(t, rounds, 1)
+
+	// Without ballots nobody wins
+	_, winner = TallyInstantRunoff(nil, candidates)
+	require.Empty(t, winner)
+}
+
+// TestTallyBordaAndApproval tests point and approval counts, ignoring unknown and repeated teams
+func TestTallyBordaAndApproval(t *testing.T) {
+	candidates := []string{"a", "b", "c"}
+	ballots := [][]string{
+		{"a", "b", "c"},
+		{"b", "a"},
+		{"c", "x", "c"},
+	}
+
+	require.Equal(t, map[string]int64{"a": 3, "b": 3, "c": 2}, TallyBorda(ballots, candidates))
+	require.Equal(t, map[string]int64{"a": 2, "b": 2, "c": 2}, TallyApproval(ballots, candidates))
+	require.Equal(t, map[string]int64{"a": 1, "b": 1, "c": 1}, TallyPlurality(ballots, candidates))
+
+	require.Equal(t, "a", topCandidate(map[string]int64{"a": 2, "b": 2, "c": 1}, candidates), "ties go to the earlier finalist")
+	require.Empty(t, topCandidate(map[string]int64{}, candidates))
+}
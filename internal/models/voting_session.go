@@ -0,0 +1,309 @@
+package models
+
+import (
+	"backend/internal/db"
+	"backend/internal/errmsg"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var VotingSessionScheduled = "scheduled"
+var VotingSessionOpen = "open"
+var VotingSessionClosed = "closed"
+
+var VotingModeSingle = "single"
+var VotingModeRanked = "ranked"
+var VotingModeApproval = "approval"
+
+// VotingSessionNameMaxLength bounds the round's display name
+var VotingSessionNameMaxLength = 100
+
+// MaxScannedWithinMinutes bounds how far back a badge scan can count for eligibility
+var MaxScannedWithinMinutes = 24 * 60
+
+// VotingEligibility decides who may vote in a session. Every requirement that
+// is set must hold; the zero value lets any account vote.
+type VotingEligibility struct {
+	RequireCheckedIn     bool `json:"requireCheckedIn" bson:"requireCheckedIn"`
+	RequirePresent       bool `json:"requirePresent" bson:"requirePresent"`
+	ScannedWithinMinutes int  `json:"scannedWithinMinutes" bson:"scannedWithinMinutes"` // 0 doesn't require a badge scan
+	ExcludeOwnTeam       bool `json:"excludeOwnTeam" bson:"excludeOwnTeam"`             // finalists' members can't vote for their own team
+}
+
+// Check returns why the account may not vote, or an empty error if it may
+func (e VotingEligibility) Check(acc Account, now time.Time) errmsg.StatusError {
+	if e.RequireCheckedIn && !acc.CheckedIn {
+		return errmsg.VotingNotCheckedIn
+	}
+	if e.RequirePresent && !acc.Present {
+		return errmsg.VotingNotPresent
+	}
+	if e.ScannedWithinMinutes > 0 && now.Sub(acc.LastScannedAt) > time.Duration(e.ScannedWithinMinutes)*time.Minute {
+		return errmsg.VotingScanRequired
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// VotingSession is one round of audience voting, such as a semifinal or a
+// people's choice award. Votes are only accepted between OpensAt and ClosesAt,
+// for one of the finalists snapshotted when the session was created, and from
+// accounts meeting its eligibility requirements. Each account votes once per session,
+// with a single choice, a ranking or a set of approved teams depending on Mode.
+type VotingSession struct {
+	ID          string            `json:"id" bson:"id"`
+	Name        string            `json:"name" bson:"name"`
+	OpensAt     time.Time         `json:"opensAt" bson:"opensAt"`
+	ClosesAt    time.Time         `json:"closesAt" bson:"closesAt"`
+	Finalists   []string          `json:"finalists" bson:"finalists"`
+	Mode        string            `json:"mode" bson:"mode"`
+	Eligibility VotingEligibility `json:"eligibility" bson:"eligibility"`
+	// the live tally only shows shares, not exact counts, until the session closes
+	HideTallyUntilClose bool      `json:"hideTallyUntilClose" bson:"hideTallyUntilClose"`
+	CreatedBy           string    `json:"createdBy" bson:"createdBy"`
+	CreatedAt           time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Validate checks the window, the finalists, the voting mode and the eligibility requirements
+func (vs *VotingSession) Validate() errmsg.StatusError {
+	vs.Name = strings.TrimSpace(vs.Name)
+	if vs.Mode == "" {
+		vs.Mode = VotingModeSingle
+	}
+
+	if vs.Name == "" || len(vs.Name) > VotingSessionNameMaxLength {
+		return errmsg.VotingSessionInvalid
+	}
+	if vs.OpensAt.IsZero() || !vs.ClosesAt.After(vs.OpensAt) {
+		return errmsg.VotingSessionInvalid
+	}
+	if !slices.Contains([]string{VotingModeSingle, VotingModeRanked, VotingModeApproval}, vs.Mode) {
+		return errmsg.VotingSessionInvalid
+	}
+	if vs.Eligibility.ScannedWithinMinutes < 0 || vs.Eligibility.ScannedWithinMinutes > MaxScannedWithinMinutes {
+		return errmsg.VotingSessionInvalid
+	}
+	if len(vs.Finalists) == 0 {
+		return errmsg.VotingSessionInvalid
+	}
+
+	for i, teamID := range vs.Finalists {
+		if slices.Contains(vs.Finalists[:i], teamID) {
+			return errmsg.VotingSessionInvalid
+		}
+
+		team := Team{ID: teamID}
+		if err := team.Get(); err != nil || team.Deleted {
+			return errmsg.TeamNotFound
+		}
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+func (vs *VotingSession) Create() errmsg.StatusError {
+	if serr := vs.Validate(); serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	vs.CreatedAt = time.Now()
+
+	err := WithNewID(func(ID string) error {
+		vs.ID = ID
+		_, err := db.VotingSessions.InsertOne(db.Ctx, vs)
+		return err
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	sessions, err := db.VotingSessions.CountDocuments(db.Ctx, bson.M{})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if sessions == 1 {
+		if err := adoptLegacyVotes(vs.ID); err != nil {
+			return errmsg.InternalServerError(err)
+		}
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// adoptLegacyVotes ties the votes cast before there were voting sessions to the
+// first session: accounts that voted then count as having voted in it, and
+// their ballots count in its results
+func adoptLegacyVotes(sessionID string) error {
+	var voters []Account
+	cursor, err := db.Accounts.Find(db.Ctx, bson.M{"hasVoted": true})
+	if err != nil {
+		return err
+	}
+	if err = cursor.All(db.Ctx, &voters); err != nil {
+		return err
+	}
+
+	_, err = db.Accounts.UpdateMany(db.Ctx, bson.M{"hasVoted": true}, bson.M{
+		"$addToSet": bson.M{"votedSessions": sessionID},
+		"$unset":    bson.M{"hasVoted": ""},
+	})
+	if err != nil {
+		return err
+	}
+	for _, voter := range voters {
+		invalidateAccountCache(voter.ID, voter.Email)
+	}
+
+	_, err = db.Votes.UpdateMany(db.Ctx, bson.M{"sessionID": bson.M{"$in": bson.A{nil, ""}}}, bson.M{
+		"$set": bson.M{"sessionID": sessionID},
+	})
+	if err != nil {
+		return err
+	}
+	InvalidateVoteCounts(sessionID)
+
+	return nil
+}
+
+func (vs *VotingSession) Get() errmsg.StatusError {
+	err := db.VotingSessions.FindOne(db.Ctx, bson.M{"id": vs.ID}).Decode(vs)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errmsg.VotingSessionNotFound
+	}
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// Update stores the session's name, window, finalists, voting mode and eligibility requirements
+func (vs *VotingSession) Update() errmsg.StatusError {
+	if serr := vs.Validate(); serr != errmsg.EmptyStatusError {
+		return serr
+	}
+
+	result, err := db.VotingSessions.UpdateOne(db.Ctx, bson.M{"id": vs.ID}, bson.M{
+		"$set": bson.M{
+			"name":                vs.Name,
+			"opensAt":             vs.OpensAt,
+			"closesAt":            vs.ClosesAt,
+			"finalists":           vs.Finalists,
+			"mode":                vs.Mode,
+			"eligibility":         vs.Eligibility,
+			"hideTallyUntilClose": vs.HideTallyUntilClose,
+		},
+	})
+	if err != nil {
+		return errmsg.InternalServerError(err)
+	}
+	if result.MatchedCount == 0 {
+		return errmsg.VotingSessionNotFound
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// GetVotingSessions lists every voting session in opening order
+func GetVotingSessions() (sessions []VotingSession, serr errmsg.StatusError) {
+	cursor, err := db.VotingSessions.Find(db.Ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "opensAt", Value: 1}}))
+	if err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	sessions = []VotingSession{}
+	if err = cursor.All(db.Ctx, &sessions); err != nil {
+		return nil, errmsg.InternalServerError(err)
+	}
+
+	return sessions, errmsg.EmptyStatusError
+}
+
+// CurrentVotingSession picks the session participants should see: the most
+// recently opened one that is still open, otherwise the next one to open,
+// otherwise the last one to close
+func CurrentVotingSession(now time.Time) (session VotingSession, found bool, serr errmsg.StatusError) {
+	sessions, serr := GetVotingSessions()
+	if serr != errmsg.EmptyStatusError {
+		return session, false, serr
+	}
+
+	var open, next, last *VotingSession
+	for i := range sessions {
+		s := &sessions[i]
+		switch s.Status(now) {
+		case VotingSessionOpen:
+			if open == nil || s.OpensAt.After(open.OpensAt) {
+				open = s
+			}
+		case VotingSessionScheduled:
+			if next == nil || s.OpensAt.Before(next.OpensAt) {
+				next = s
+			}
+		case VotingSessionClosed:
+			if last == nil || s.ClosesAt.After(last.ClosesAt) {
+				last = s
+			}
+		}
+	}
+
+	for _, candidate := range []*VotingSession{open, next, last} {
+		if candidate != nil {
+			return *candidate, true, errmsg.EmptyStatusError
+		}
+	}
+
+	return session, false, errmsg.EmptyStatusError
+}
+
+// Status reports whether the session is scheduled, open or closed at the given time
+func (vs VotingSession) Status(now time.Time) string {
+	switch {
+	case now.Before(vs.OpensAt):
+		return VotingSessionScheduled
+	case now.Before(vs.ClosesAt):
+		return VotingSessionOpen
+	default:
+		return VotingSessionClosed
+	}
+}
+
+// IsFinalist reports whether the team is on the session's finalist snapshot
+func (vs VotingSession) IsFinalist(teamID string) bool {
+	return teamID != "" && slices.Contains(vs.Finalists, teamID)
+}
+
+// ValidateBallot checks that a ballot fits the session's voting mode: exactly one
+// team in single-choice sessions, and at least one team without repeats in
+// ranked and approval sessions. Every team must be one of the finalists, and
+// not the voter's own team when the session excludes it.
+func (vs VotingSession) ValidateBallot(acc Account, ballot []string) errmsg.StatusError {
+	if len(ballot) == 0 || (vs.Mode == VotingModeSingle && len(ballot) != 1) {
+		return errmsg.VotingInvalidBallot
+	}
+
+	for i, teamID := range ballot {
+		if slices.Contains(ballot[:i], teamID) {
+			return errmsg.VotingInvalidBallot
+		}
+		if !vs.IsFinalist(teamID) {
+			return errmsg.VotingInvalidFinalist
+		}
+		if vs.Eligibility.ExcludeOwnTeam && acc.TeamID != "" && teamID == acc.TeamID {
+			return errmsg.VotingOwnTeam
+		}
+	}
+
+	return errmsg.EmptyStatusError
+}
+
+// LoadFinalists fetches the session's finalist teams in finalist order
+func (vs VotingSession) LoadFinalists() ([]Team, errmsg.StatusError) {
+	return Finalists{Teams: vs.Finalists}.LoadTeams()
+}
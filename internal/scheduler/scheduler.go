@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+var Sc *Scheduler
+
+type Config struct {
+	PollEvery time.Duration // how often due schedules are looked for
+	Lease     time.Duration // how long a claimed schedule may run before it counts as interrupted
+}
+
+// Scheduler executes flag stages at their scheduled times. Every process runs
+// one, including prefork children and other instances; each due schedule is
+// leased to exactly one of them in Mongo.
+type Scheduler struct {
+	cfg Config
+	id  string
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	onceClose sync.Once
+}
+
+func NewScheduler(cfg Config) *Scheduler {
+	s := &Scheduler{
+		cfg:  cfg,
+		id:   schedulerID(),
+		stop: make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.worker()
+
+	return s
+}
+
+func (s *Scheduler) Close() {
+	s.onceClose.Do(func() {
+		close(s.stop)
+		s.wg.Wait()
+	})
+}
+
+func (s *Scheduler) worker() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.PollEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.runDue()
+		}
+	}
+}
+
+// runDue executes every schedule that is due, one claim at a time
+func (s *Scheduler) runDue() {
+	now := time.Now()
+	if err := models.FailExpiredFlagStageSchedules(now); err != nil {
+		log.Printf("scheduler: failed to expire flag stage schedules: %v", err)
+	}
+
+	for {
+		schedule, found, err := models.ClaimDueFlagStageSchedule(s.id, time.Now(), s.cfg.Lease)
+		if err != nil {
+			log.Printf("scheduler: failed to claim a flag stage schedule: %v", err)
+			return
+		}
+		if !found {
+			return
+		}
+
+		s.execute(schedule)
+	}
+}
+
+func (s *Scheduler) execute(schedule models.FlagStageSchedule) {
+	done := make(chan struct{})
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		s.renewLease(schedule, done)
+	}()
+
+	execution, executeErr := executeStage(schedule.StageID, s.id)
+	close(done)
+	<-renewed
+
+	if err := schedule.Finish(execution.ID, executeErr); err != nil {
+		log.Printf("scheduler: failed to finish flag stage schedule %s: %v", schedule.ID, err)
+	}
+
+	errMessage := ""
+	if executeErr != nil {
+		errMessage = executeErr.Error()
+	}
+	events.Em.FlagStageScheduleExecuted(s.id, schedule.ID, schedule.StageID, execution.ID, schedule.RunAt, time.Now(), errMessage)
+}
+
+// renewLease keeps extending the schedule's lease while its stage executes,
+// until done is closed or the lease is lost
+func (s *Scheduler) renewLease(schedule models.FlagStageSchedule, done <-chan struct{}) {
+	ticker := time.NewTicker(s.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			err := schedule.RenewLease(time.Now().Add(s.cfg.Lease))
+			if errors.Is(err, models.ErrFlagStageScheduleLeaseLost) {
+				log.Printf("scheduler: lost the lease on flag stage schedule %s while it ran", schedule.ID)
+				return
+			}
+			if err != nil {
+				log.Printf("scheduler: failed to renew the lease on flag stage schedule %s: %v", schedule.ID, err)
+			}
+		}
+	}
+}
+
+func executeStage(stageID string, executedBy string) (models.FlagStageExecution, error) {
+	stage := models.FlagStage{ID: stageID}
+	if serr := stage.Get(); serr != errmsg.EmptyStatusError {
+		return models.FlagStageExecution{}, errors.New(serr.Message)
+	}
+
+	execution, serr := stage.Execute(executedBy)
+	if serr != errmsg.EmptyStatusError {
+		return execution, errors.New(serr.Message)
+	}
+
+	return execution, nil
+}
+
+// schedulerID names this process as a lease owner
+func schedulerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(buf))
+}
@@ -87,7 +87,10 @@ func pilesComputeHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
-	salt, counts, serr := computeAndPersistBadgePileSalt(accounts, body.Trials)
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	salt, counts, serr := computeAndPersistBadgePileSalt(accounts, body.Trials, superuser.Username)
 	if serr != errmsg.EmptyStatusError {
 		return utils.StatusError(c, serr)
 	}
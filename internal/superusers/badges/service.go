@@ -11,7 +11,7 @@ import (
 	"time"
 )
 
-func computeAndPersistBadgePileSalt(accounts []models.Account, trials int) (uint32, []int, errmsg.StatusError) {
+func computeAndPersistBadgePileSalt(accounts []models.Account, trials int, computedBy string) (uint32, []int, errmsg.StatusError) {
 	if env.BADGE_PILES <= 0 {
 		return 0, nil, errmsg.InternalServerError(fmt.Errorf("badge piles misconfigured"))
 	}
@@ -40,7 +40,7 @@ func computeAndPersistBadgePileSalt(accounts []models.Account, trials int) (uint
 		Value: strconv.FormatUint(uint64(salt), 10),
 	}
 
-	if serr := setting.Save(); serr != errmsg.EmptyStatusError {
+	if serr := setting.Save(computedBy); serr != errmsg.EmptyStatusError {
 		return 0, nil, serr
 	}
 
@@ -32,16 +32,32 @@ func flagsGetHandler(c fiber.Ctx) error {
 	return c.JSON(flags)
 }
 
+// flagsRegistryGetHandler lists the flags the backend knows.
+// @Summary Retrieve the flag registry
+// @Description Lists every registered flag with its description, the flags it requires and the flags it conflicts with, as declared in flags_config.json.
+// @Tags Superusers Flags
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {array} models.FlagDefinition
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Router /superusers/flags/registry [get]
+func flagsRegistryGetHandler(c fiber.Ctx) error {
+	return c.JSON(models.FlagsRegistry.Flags)
+}
+
 // flagsSetHandler flips a single feature flag.
 // @Summary Set a feature flag
-// @Description Updates one flag value and returns the refreshed flag map for verification.
+// @Description Updates one flag value and returns the refreshed flag map for verification. The flag has to be in the flag registry, and the change is rejected when it would leave a flag on without the flags it requires or with a flag it conflicts with. With resolve, those flags are switched along with it instead: turning a flag on turns on what it requires and turns off what it conflicts with, and turning it off turns off what requires it.
 // @Tags Superusers Flags
 // @Security SuperUserAuth
 // @Accept json
 // @Produce json
 // @Param payload body FlagSetRequest true "Flag toggle"
+// @Param resolve query bool false "Switch the flags the change depends on or affects as well"
 // @Success 200 {object} FlagAssignments
+// @Failure 400 {object} errmsg._FlagUnknown
 // @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 409 {object} errmsg._FlagInconsistent
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/flags [post]
 func flagsSetHandler(c fiber.Ctx) error {
@@ -56,7 +72,21 @@ func flagsSetHandler(c fiber.Ctx) error {
 		)
 	}
 
-	err = flags.Set(body.Flag, body.Value)
+	changes, serr := models.FlagsRegistry.Reconcile(
+		flags.Flags, map[string]bool{body.Flag: body.Value}, c.Query("resolve") == "true",
+	)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	if len(changes) == 1 {
+		err = flags.Set(body.Flag, body.Value, superuser.Username)
+	} else {
+		err = flags.SetBulk(changes, superuser.Username)
+	}
 	if err != nil {
 		return utils.StatusError(
 			c, errmsg.InternalServerError(err),
@@ -68,14 +98,17 @@ func flagsSetHandler(c fiber.Ctx) error {
 
 // flagsSetBulkHandler overwrites multiple flags in one request.
 // @Summary Bulk update feature flags
-// @Description Applies a map of flag values and echoes the resulting assignments.
+// @Description Applies a map of flag values and echoes the resulting assignments. Every flag has to be in the flag registry, and the changes are rejected when they would leave a flag on without the flags it requires or with a flag it conflicts with. With resolve, the flags the changes depend on or affect are switched as well; flags in the payload are never overridden.
 // @Tags Superusers Flags
 // @Security SuperUserAuth
 // @Accept json
 // @Produce json
 // @Param payload body FlagAssignments true "Flag assignments"
+// @Param resolve query bool false "Switch the flags the changes depend on or affect as well"
 // @Success 200 {object} FlagAssignments
+// @Failure 400 {object} errmsg._FlagUnknown
 // @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 409 {object} errmsg._FlagInconsistent
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/flags [put]
 func flagsSetBulkHandler(c fiber.Ctx) error {
@@ -90,7 +123,15 @@ func flagsSetBulkHandler(c fiber.Ctx) error {
 		)
 	}
 
-	err = flags.SetBulk(body)
+	changes, serr := models.FlagsRegistry.Reconcile(flags.Flags, body, c.Query("resolve") == "true")
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	err = flags.SetBulk(changes, superuser.Username)
 	if err != nil {
 		return utils.StatusError(
 			c, errmsg.InternalServerError(err),
@@ -124,7 +165,10 @@ func flagsUnsetHandler(c fiber.Ctx) error {
 		)
 	}
 
-	err = flags.Unset(body.Flag)
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	err = flags.Unset(body.Flag, superuser.Username)
 	if err != nil {
 		return utils.StatusError(
 			c, errmsg.InternalServerError(err),
@@ -154,7 +198,10 @@ func flagsResetHandler(c fiber.Ctx) error {
 		)
 	}
 
-	err = flags.Reset()
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	err = flags.Reset(superuser.Username)
 	if err != nil {
 		return utils.StatusError(
 			c, errmsg.InternalServerError(err),
@@ -38,6 +38,26 @@ func Routes(r fiber.Router) {
 		flagsUnsetHandler,
 	)
 
+	r.Get("/registry",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagsRegistryGetHandler,
+	)
+
+	r.Put("/rules",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagRulesSetHandler,
+	)
+	r.Delete("/rules",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagRulesUnsetHandler,
+	)
+
 	// testing the flags middleware
 	r.Get("/test",
 		models.SuperUserMiddlewareBuilder([]string{
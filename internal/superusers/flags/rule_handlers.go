@@ -0,0 +1,107 @@
+package flags
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// flagRulesSetHandler targets a flag at part of the audience.
+// @Summary Set a feature flag rule
+// @Description Turns a flag on for the listed accounts, teams and universities, and for a fixed percentage of accounts, while it stays off for everyone else. Each account's place in the percentage is fixed by hashing its ID with the flag name, so raising the percentage only ever adds accounts. The rule replaces any rule the flag had; a flag that is on globally is on for everyone regardless.
+// @Tags Superusers Flags
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body FlagRuleSetRequest true "Flag and its audience"
+// @Success 200 {object} FlagRules
+// @Failure 400 {object} errmsg._FlagRuleInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flags/rules [put]
+func flagRulesSetHandler(c fiber.Ctx) error {
+	var body FlagRuleSetRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.FlagRuleInvalid)
+	}
+
+	body.Flag = strings.TrimSpace(body.Flag)
+	if body.Flag == "" {
+		return utils.StatusError(c, errmsg.FlagRuleInvalid)
+	}
+
+	rule := models.FlagRule{
+		AccountIDs:   body.AccountIDs,
+		TeamIDs:      body.TeamIDs,
+		Universities: body.Universities,
+		Percentage:   body.Percentage,
+	}
+	if serr := rule.Validate(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	flags := models.Flags{}
+	err := flags.Get()
+	if err != nil {
+		return utils.StatusError(
+			c, errmsg.InternalServerError(err),
+		)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	err = flags.SetRule(body.Flag, rule, superuser.Username)
+	if err != nil {
+		return utils.StatusError(
+			c, errmsg.InternalServerError(err),
+		)
+	}
+
+	return c.JSON(flags.Rules)
+}
+
+// flagRulesUnsetHandler stops targeting a flag.
+// @Summary Remove a feature flag rule
+// @Description Deletes the flag's rule so only its global value applies, and returns the remaining rules.
+// @Tags Superusers Flags
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body FlagUnsetRequest true "Flag identifier"
+// @Success 200 {object} FlagRules
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flags/rules [delete]
+func flagRulesUnsetHandler(c fiber.Ctx) error {
+	var body FlagUnsetRequest
+	json.Unmarshal(c.Body(), &body)
+
+	flags := models.Flags{}
+	err := flags.Get()
+	if err != nil {
+		return utils.StatusError(
+			c, errmsg.InternalServerError(err),
+		)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	err = flags.UnsetRule(body.Flag, superuser.Username)
+	if err != nil {
+		return utils.StatusError(
+			c, errmsg.InternalServerError(err),
+		)
+	}
+
+	if flags.Rules == nil {
+		flags.Rules = map[string]models.FlagRule{}
+	}
+
+	return c.JSON(flags.Rules)
+}
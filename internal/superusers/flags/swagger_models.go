@@ -1,5 +1,7 @@
 package flags
 
+import "backend/internal/models"
+
 // FlagSetRequest toggles a single feature flag.
 type FlagSetRequest struct {
 	Flag  string `json:"flag"`
@@ -13,3 +15,15 @@ type FlagAssignments map[string]bool
 type FlagUnsetRequest struct {
 	Flag string `json:"flag"`
 }
+
+// FlagRuleSetRequest targets a flag at part of the audience.
+type FlagRuleSetRequest struct {
+	Flag         string   `json:"flag" example:"submissions_write"`
+	AccountIDs   []string `json:"accountIDs"`
+	TeamIDs      []string `json:"teamIDs"`
+	Universities []string `json:"universities" example:"Politehnica Bucharest"`
+	Percentage   int      `json:"percentage" example:"10" description:"Share of accounts from 0 to 100"`
+}
+
+// FlagRules represents the flag rules keyed by flag name.
+type FlagRules map[string]models.FlagRule
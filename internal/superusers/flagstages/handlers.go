@@ -2,9 +2,11 @@ package flagstages
 
 import (
 	"backend/internal/errmsg"
+	"backend/internal/events"
 	"backend/internal/models"
 	"backend/internal/utils"
 	"encoding/json"
+	"slices"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -32,20 +34,27 @@ func flagStagesGetHandler(c fiber.Ctx) error {
 
 // flagStagesCreateHandler stores a new stage configuration.
 // @Summary Create a new flag stage
-// @Description Saves a stage blueprint listing which flags to enable or disable.
+// @Description Saves a stage blueprint listing which flags to enable or disable. Every flag has to be in the flag registry, and the stage can't turn a flag on while turning off a flag it requires, or turn on two conflicting flags.
 // @Tags Superusers Flag Stages
 // @Security SuperUserAuth
 // @Accept json
 // @Produce json
 // @Param payload body FlagStageCreateRequest true "Flag stage"
 // @Success 200 {object} models.FlagStage
+// @Failure 400 {object} errmsg._FlagUnknown
 // @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 409 {object} errmsg._FlagInconsistent
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/flagstages [post]
 func flagStagesCreateHandler(c fiber.Ctx) error {
 	flagStage := models.FlagStage{}
 	json.Unmarshal(c.Body(), &flagStage)
 
+	serr := flagStage.Validate()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	err := flagStage.Create()
 
 	if err != nil {
@@ -83,7 +92,7 @@ func flagStagesDeleteHandler(c fiber.Ctx) error {
 
 // flagStagesExecuteHandler executes the toggles defined in a stage.
 // @Summary Apply a flag stage
-// @Description Fetches the stage, applies its instructions, and returns the resulting flags payload.
+// @Description Fetches the stage, applies its instructions, and returns the resulting flags payload. The flags and stage it replaces are recorded so the execution can be rolled back. A stage that would leave a flag on without the flags it requires, or with a flag it conflicts with, is not applied.
 // @Tags Superusers Flag Stages
 // @Security SuperUserAuth
 // @Produce json
@@ -91,6 +100,7 @@ func flagStagesDeleteHandler(c fiber.Ctx) error {
 // @Success 200 {object} models.Flags
 // @Failure 401 {object} errmsg._SuperUserNoToken
 // @Failure 404 {object} errmsg._FlagStageNotFound
+// @Failure 409 {object} errmsg._FlagInconsistent
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/flagstages/execute [post]
 func flagStagesExecuteHandler(c fiber.Ctx) error {
@@ -100,21 +110,134 @@ func flagStagesExecuteHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, serr)
 	}
 
-	err := flagStage.Execute()
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	execution, serr := flagStage.Execute(superuser.Username)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.FlagStageExecuted(superuser.Username, flagStage.ID, execution.ID, execution.Changes)
+
+	flags := models.Flags{}
+	err := flags.Get()
 	if err != nil {
 		return utils.StatusError(
 			c, errmsg.InternalServerError(err),
 		)
 	}
 
+	return c.JSON(flags)
+
+}
+
+// flagStagesDryRunHandler previews what executing a stage would change.
+// @Summary Preview a flag stage
+// @Description Compares the stage's instructions with the current flags without changing anything. Lists every flag whose value would change, including flags the stage would add, the flags it sets to the value they already have, and the dependencies or conflicts that would keep it from being executed.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Produce json
+// @Param id query string true "Flag stage ID"
+// @Success 200 {object} FlagStageDryRunResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._FlagStageNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/dry-run [get]
+func flagStagesDryRunHandler(c fiber.Ctx) error {
+	flagStage := models.FlagStage{ID: c.Query("id")}
+	serr := flagStage.Get()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	flags := models.Flags{}
-	err = flags.Get()
+	err := flags.Get()
 	if err != nil {
 		return utils.StatusError(
 			c, errmsg.InternalServerError(err),
 		)
 	}
 
-	return c.JSON(flags)
+	changes := flagStage.Diff(flags.Flags)
+	changed := map[string]bool{}
+	for _, change := range changes {
+		changed[change.Flag] = true
+	}
+
+	unchanged := []string{}
+	for flag := range flagStage.Instructions() {
+		if !changed[flag] {
+			unchanged = append(unchanged, flag)
+		}
+	}
+	slices.Sort(unchanged)
+
+	return c.JSON(FlagStageDryRunResponse{
+		Stage:        flagStage,
+		CurrentStage: flags.Stage,
+		Changes:      changes,
+		Unchanged:    unchanged,
+		Problems:     models.FlagsRegistry.Check(flags.Flags, flagStage.Instructions()),
+	})
+}
+
+// flagStageExecutionsGetHandler lists past stage executions.
+// @Summary List flag stage executions
+// @Description Lists every stage execution, latest first, with the flags and stage it replaced, what it changed, and whether it was rolled back.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {array} models.FlagStageExecution
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/executions [get]
+func flagStageExecutionsGetHandler(c fiber.Ctx) error {
+	executions, serr := models.GetFlagStageExecutions()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(executions)
+}
+
+// flagStagesRollbackHandler undoes a stage execution.
+// @Summary Roll back a flag stage
+// @Description Sets the flags the execution changed back to their previous values, removes the flags it added, and restores the previous stage. Flags changed by other means are left alone. Only the latest execution that hasn't been rolled back can be rolled back, so a series of executions is undone in reverse order.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Produce json
+// @Param id query string true "Flag stage execution ID"
+// @Success 200 {object} models.Flags
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._FlagStageExecutionNotFound
+// @Failure 409 {object} errmsg._FlagStageRollbackConflict
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/rollback [post]
+func flagStagesRollbackHandler(c fiber.Ctx) error {
+	execution := models.FlagStageExecution{ID: c.Query("id")}
+	serr := execution.Get()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	serr = execution.RollBack(superuser.Username)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.FlagStageRolledBack(superuser.Username, execution.StageID, execution.ID, execution.Changes)
 
+	flags := models.Flags{}
+	err := flags.Get()
+	if err != nil {
+		return utils.StatusError(
+			c, errmsg.InternalServerError(err),
+		)
+	}
+
+	return c.JSON(flags)
 }
@@ -33,4 +33,48 @@ func Routes(r fiber.Router) {
 		}),
 		flagStagesExecuteHandler,
 	)
+	r.Get("/dry-run",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStagesDryRunHandler,
+	)
+	r.Get("/executions",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStageExecutionsGetHandler,
+	)
+	r.Post("/rollback",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStagesRollbackHandler,
+	)
+
+	// schedules
+	r.Get("/schedules",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStageSchedulesGetHandler,
+	)
+	r.Post("/schedules",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStageScheduleCreateHandler,
+	)
+	r.Put("/schedules",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStageScheduleUpdateHandler,
+	)
+	r.Delete("/schedules",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		flagStageScheduleDeleteHandler,
+	)
 }
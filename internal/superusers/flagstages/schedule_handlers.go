@@ -0,0 +1,139 @@
+package flagstages
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// flagStageSchedulesGetHandler lists the scheduled stage executions.
+// @Summary List flag stage schedules
+// @Description Lists every scheduled stage execution by time, whether pending, done, failed or cancelled. Executed schedules say when they ran and, if they failed, why.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {array} models.FlagStageSchedule
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/schedules [get]
+func flagStageSchedulesGetHandler(c fiber.Ctx) error {
+	schedules, serr := models.GetFlagStageSchedules()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(schedules)
+}
+
+// flagStageScheduleCreateHandler schedules a stage to be applied later.
+// @Summary Schedule a flag stage
+// @Description Applies the stage at runAt without anyone having to click execute. Schedules are stored in the database, so they survive restarts, and each runs exactly once however many server processes are running.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body FlagStageScheduleCreateRequest true "Stage and time"
+// @Success 200 {object} models.FlagStageSchedule
+// @Failure 400 {object} errmsg._FlagStageScheduleInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._FlagStageNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/schedules [post]
+func flagStageScheduleCreateHandler(c fiber.Ctx) error {
+	var body FlagStageScheduleCreateRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil || body.StageID == "" {
+		return utils.StatusError(c, errmsg.FlagStageScheduleInvalid)
+	}
+
+	flagStage := models.FlagStage{ID: body.StageID}
+	if serr := flagStage.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	schedule := models.FlagStageSchedule{
+		StageID:   flagStage.ID,
+		StageName: flagStage.Name,
+		RunAt:     body.RunAt,
+		CreatedBy: superuser.Username,
+	}
+	if serr := schedule.Create(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.FlagStageScheduled(superuser.Username, schedule.ID, schedule.StageID, schedule.RunAt)
+
+	return c.JSON(schedule)
+}
+
+// flagStageScheduleUpdateHandler moves a pending schedule.
+// @Summary Reschedule a flag stage
+// @Description Changes when a pending schedule runs. Schedules that have already run or were cancelled can't be moved.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body FlagStageScheduleUpdateRequest true "Schedule and its new time"
+// @Success 200 {object} models.FlagStageSchedule
+// @Failure 400 {object} errmsg._FlagStageScheduleInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._FlagStageScheduleNotFound
+// @Failure 409 {object} errmsg._FlagStageScheduleNotPending
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/schedules [put]
+func flagStageScheduleUpdateHandler(c fiber.Ctx) error {
+	var body FlagStageScheduleUpdateRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.FlagStageScheduleInvalid)
+	}
+
+	schedule := models.FlagStageSchedule{ID: body.ID}
+	if serr := schedule.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	oldRunAt := schedule.RunAt
+
+	if serr := schedule.Reschedule(body.RunAt); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	events.Em.FlagStageRescheduled(superuser.Username, schedule.ID, schedule.StageID, oldRunAt, schedule.RunAt)
+
+	return c.JSON(schedule)
+}
+
+// flagStageScheduleDeleteHandler cancels a pending schedule.
+// @Summary Cancel a flag stage schedule
+// @Description Stops a pending schedule from running. The schedule is kept, marked cancelled.
+// @Tags Superusers Flag Stages
+// @Security SuperUserAuth
+// @Produce json
+// @Param id query string true "Flag stage schedule ID"
+// @Success 200 {object} models.FlagStageSchedule
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._FlagStageScheduleNotFound
+// @Failure 409 {object} errmsg._FlagStageScheduleNotPending
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/flagstages/schedules [delete]
+func flagStageScheduleDeleteHandler(c fiber.Ctx) error {
+	schedule := models.FlagStageSchedule{ID: c.Query("id")}
+	if serr := schedule.Cancel(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	events.Em.FlagStageScheduleCancelled(superuser.Username, schedule.ID, schedule.StageID)
+
+	return c.JSON(schedule)
+}
@@ -1,8 +1,34 @@
 package flagstages
 
+import (
+	"backend/internal/models"
+	"time"
+)
+
 // FlagStageCreateRequest documents the payload to create a new stage.
 type FlagStageCreateRequest struct {
 	Name    string   `json:"name"`
 	TurnOn  []string `json:"turnon"`
 	TurnOff []string `json:"turnoff"`
 }
+
+// FlagStageScheduleCreateRequest documents the payload to schedule a stage.
+type FlagStageScheduleCreateRequest struct {
+	StageID string    `json:"stageID" example:"abc123"`
+	RunAt   time.Time `json:"runAt" example:"2026-10-20T03:00:00Z"`
+}
+
+// FlagStageScheduleUpdateRequest documents the payload to reschedule a stage.
+type FlagStageScheduleUpdateRequest struct {
+	ID    string    `json:"id" example:"abc123"`
+	RunAt time.Time `json:"runAt" example:"2026-10-20T03:30:00Z"`
+}
+
+// FlagStageDryRunResponse documents what executing a stage would change.
+type FlagStageDryRunResponse struct {
+	Stage        models.FlagStage    `json:"stage"`
+	CurrentStage models.FlagStage    `json:"currentStage"`
+	Changes      []models.FlagChange `json:"changes"`
+	Unchanged    []string            `json:"unchanged" description:"Flags the stage sets to the value they already have"`
+	Problems     []string            `json:"problems" description:"Dependencies and conflicts that keep the stage from being executed"`
+}
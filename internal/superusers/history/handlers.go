@@ -0,0 +1,123 @@
+package history
+
+import (
+	"backend/internal/env"
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// configHistoryGetHandler lists the changes made to flags and settings.
+// @Summary Flag and setting history
+// @Description Lists every recorded change to a flag, a flag's targeting rule, the current flag stage or a setting, latest first: the value before and after, who made it, when, and through what, such as a flag or rule being set, a flag stage being executed or rolled back, or a restore. Rules are keyed by their flag and the stage by "stage". Filter by kind and key to follow one of them, and by time to see what changed around a moment. Changes made before the history was kept are not listed.
+// @Tags Superusers History
+// @Security SuperUserAuth
+// @Produce json
+// @Param kind query string false "flag, rule, stage or setting"
+// @Param key query string false "Flag or setting name, or stage"
+// @Param from query string false "Earliest change (RFC 3339)"
+// @Param to query string false "Latest change (RFC 3339)"
+// @Success 200 {array} models.ConfigChange
+// @Failure 400 {object} errmsg._ConfigHistoryInvalidQuery
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/history [get]
+func configHistoryGetHandler(c fiber.Ctx) error {
+	filter := models.ConfigHistoryFilter{
+		Kind: c.Query("kind"),
+		Key:  c.Query("key"),
+	}
+	if !validKind(filter.Kind) {
+		return utils.StatusError(c, errmsg.ConfigHistoryInvalidQuery)
+	}
+
+	bounds := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+	for name, bound := range bounds {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return utils.StatusError(c, errmsg.ConfigHistoryInvalidQuery)
+		}
+		*bound = value
+	}
+
+	history, serr := models.GetConfigHistory(filter)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(history)
+}
+
+// configRestoreHandler puts flags and settings back the way they were.
+// @Summary Restore flags and settings to a point in time
+// @Description Undoes every recorded change made after at, setting each flag, rule, the stage and each setting changed since back to the value it had then, and removing the ones added since. Narrow it with kind and keys. Flags are checked against the flag registry like any other change. The restore is recorded in the history like any other change, so it can be undone by restoring to a time before it. With dryRun the changes are only listed.
+// @Tags Superusers History
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body ConfigRestoreRequest true "Point in time and what to restore"
+// @Success 200 {object} ConfigRestoreResponse
+// @Failure 400 {object} errmsg._ConfigRestoreInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 409 {object} errmsg._FlagInconsistent
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/history/restore [post]
+func configRestoreHandler(c fiber.Ctx) error {
+	var body ConfigRestoreRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.ConfigRestoreInvalid)
+	}
+	if body.At.IsZero() || !body.At.Before(time.Now()) || !validKind(body.Kind) {
+		return utils.StatusError(c, errmsg.ConfigRestoreInvalid)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	changes, serr := models.RestoreConfig(body.At, body.Kind, body.Keys, superuser.Username, body.DryRun)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if !body.DryRun && len(changes) > 0 {
+		for _, change := range changes {
+			// the salt is kept in memory as well as in the settings
+			if change.Kind == models.ConfigKindSetting && change.Key == models.SettingBadgePileSalt {
+				env.BADGE_PILES_SALT = ""
+				if !change.Removed {
+					env.BADGE_PILES_SALT = fmt.Sprintf("%v", change.New)
+				}
+			}
+		}
+
+		events.Em.ConfigRestored(superuser.Username, body.At, body.Kind, body.Keys, changes)
+	}
+
+	return c.JSON(ConfigRestoreResponse{
+		At:      body.At,
+		DryRun:  body.DryRun,
+		Changes: changes,
+	})
+}
+
+func validKind(kind string) bool {
+	switch kind {
+	case "", models.ConfigKindFlag, models.ConfigKindRule, models.ConfigKindStage, models.ConfigKindSetting:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,22 @@
+package history
+
+import (
+	"backend/internal/models"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func Routes(r fiber.Router) {
+	r.Get("/",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		configHistoryGetHandler,
+	)
+	r.Post("/restore",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		configRestoreHandler,
+	)
+}
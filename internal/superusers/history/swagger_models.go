@@ -0,0 +1,21 @@
+package history
+
+import (
+	"backend/internal/models"
+	"time"
+)
+
+// ConfigRestoreRequest documents the payload to restore flags and settings.
+type ConfigRestoreRequest struct {
+	At     time.Time `json:"at" example:"2026-10-19T14:01:00Z"`
+	Kind   string    `json:"kind" example:"flag" description:"flag, rule, stage or setting; empty restores all of them"`
+	Keys   []string  `json:"keys" example:"judging" description:"Flags, rules (by flag) or settings to restore, or stage; empty restores every one changed since"`
+	DryRun bool      `json:"dryRun" description:"Only list the changes the restore would make"`
+}
+
+// ConfigRestoreResponse documents the changes a restore made.
+type ConfigRestoreResponse struct {
+	At      time.Time             `json:"at"`
+	DryRun  bool                  `json:"dryRun"`
+	Changes []models.ConfigChange `json:"changes"`
+}
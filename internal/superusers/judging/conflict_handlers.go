@@ -0,0 +1,122 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getConflictsHandler lists declared judge conflicts of interest.
+// @Summary List judge conflicts
+// @Description Returns every declared conflict of interest, oldest first. Can be narrowed to one judge.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Param judgeID query string false "Only conflicts of this judge"
+// @Success 200 {array} models.JudgeConflict
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/conflicts [get]
+func getConflictsHandler(c fiber.Ctx) error {
+	conflicts, serr := models.GetJudgeConflicts(c.Query("judgeID"))
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(conflicts)
+}
+
+// declareConflictHandler records a conflict of interest on behalf of a judge.
+// @Summary Declare a judge conflict
+// @Description Declares that a judge should not judge a team, or any team with a member from a university. Conflicted teams are kept out of the judge's group on the next judging init, and the live leaderboard is refit without judgments that violate the conflict.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body JudgeConflictRequest true "Judge ID, team ID or university, and reason"
+// @Success 200 {object} models.JudgeConflict
+// @Failure 400 {object} errmsg._JudgeConflictInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._JudgeNotFound
+// @Failure 409 {object} errmsg._JudgeConflictDuplicate
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/conflicts [post]
+func declareConflictHandler(c fiber.Ctx) error {
+	var body JudgeConflictRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.JudgeConflictInvalid)
+	}
+
+	judge := models.Judge{ID: body.JudgeID}
+	if body.JudgeID == "" || judge.Get() != nil {
+		return utils.StatusError(c, errmsg.JudgeNotFound)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	conflict := models.JudgeConflict{
+		JudgeID:    judge.ID,
+		TeamID:     body.TeamID,
+		University: body.University,
+		Reason:     body.Reason,
+		ActorRole:  events.ActorSuperUser,
+		ActorID:    superuser.Username,
+	}
+	if serr := conflict.Create(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to rebuild leaderboard after conflict %s: %s", conflict.ID, serr.Message)
+	}
+
+	events.Em.JudgeConflictDeclared(events.ActorSuperUser, superuser.Username, conflict)
+
+	return c.JSON(conflict)
+}
+
+// deleteConflictHandler removes a declared judge conflict.
+// @Summary Remove a judge conflict
+// @Description Deletes a declared conflict of interest and refits the live leaderboard, so judgments it excluded count again.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body JudgeConflictDeleteRequest true "Conflict ID"
+// @Success 200 {object} models.JudgeConflict
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._JudgeConflictNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/conflicts [delete]
+func deleteConflictHandler(c fiber.Ctx) error {
+	var body JudgeConflictDeleteRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	if body.ID == "" {
+		return utils.StatusError(c, errmsg.JudgeConflictNotFound)
+	}
+
+	conflict, serr := models.DeleteJudgeConflict(body.ID)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to rebuild leaderboard after removing conflict %s: %s", conflict.ID, serr.Message)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	events.Em.JudgeConflictRemoved(superuser.Username, conflict)
+
+	return c.JSON(conflict)
+}
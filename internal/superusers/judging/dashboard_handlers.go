@@ -0,0 +1,251 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+var judgeStatusNotStarted = "not_started"
+var judgeStatusJudging = "judging"
+var judgeStatusResting = "resting"
+var judgeStatusStuck = "stuck"
+var judgeStatusFinished = "finished"
+
+// judgingDashboardHandler summarizes judging progress for organizers.
+// @Summary Judging progress dashboard
+// @Description Summarizes every judge's progress through the matrix (current step, judgments made, time since the last judgment, next team time, Crowd-BT reliability and a projected finish time) and every team's coverage (times judged, unique opponents and judges). A judge is stuck when a full wait window has passed since both their next team time and their last judgment without them moving on.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {object} JudgingDashboardResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 409 {object} errmsg._JudgingNotInitialized
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/dashboard [get]
+func judgingDashboardHandler(c fiber.Ctx) error {
+	plan, judgeToGroupIdx, serr := loadJudgingPlan()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teams, judges, serr := loadJudgingRoster()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	judgments, serr := models.GetJudgments("", models.JudgmentStatusValid)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	lb, serr := models.GetLeaderboard()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	waitMinutes, serr := models.GetJudgingWaitMinutes()
+	if serr == errmsg.SettingNotFound {
+		waitMinutes, serr = 5, errmsg.EmptyStatusError
+	}
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	waitWindow := time.Duration(waitMinutes) * time.Minute
+
+	// Judges the scorer hasn't seen yet sit at the prior
+	priors := lb.Options
+	if priors.AlphaPrior <= 0 || priors.BetaPrior <= 0 {
+		priors = utils.DefaultScorerOptions()
+	}
+
+	judgmentsByJudge := make(map[string][]models.Judgment)
+	for _, judgment := range judgments {
+		judgmentsByJudge[judgment.JudgeID] = append(judgmentsByJudge[judgment.JudgeID], judgment)
+	}
+
+	now := time.Now()
+	response := JudgingDashboardResponse{
+		GeneratedAt: now,
+		TotalSteps:  plan.Steps,
+		WaitMinutes: waitMinutes,
+		StatusCounts: map[string]int{
+			judgeStatusNotStarted: 0,
+			judgeStatusJudging:    0,
+			judgeStatusResting:    0,
+			judgeStatusStuck:      0,
+			judgeStatusFinished:   0,
+		},
+		Judges: []JudgeDashboardEntry{},
+		Teams:  []TeamCoverageEntry{},
+	}
+
+	for _, judge := range judges {
+		groupIdx, inPlan := judgeToGroupIdx[judge.ID]
+		if !inPlan || groupIdx >= plan.Groups {
+			groupIdx = -1
+		}
+
+		entry := JudgeDashboardEntry{
+			JudgeID:      judge.ID,
+			Name:         judge.Name,
+			Pair:         judge.Pair,
+			GroupIdx:     groupIdx,
+			CurrentStep:  judge.CurrentTeam,
+			TotalSteps:   plan.Steps,
+			RetryQueue:   len(judge.RetryQueue),
+			NextTeamTime: judge.NextTeamTime,
+			Alpha:        priors.AlphaPrior,
+			Beta:         priors.BetaPrior,
+		}
+
+		if alpha, ok := lb.State.JudgeAlpha[judge.ID]; ok {
+			entry.Alpha = alpha
+			entry.Beta = lb.State.JudgeBeta[judge.ID]
+		}
+		entry.Reliability = entry.Alpha / (entry.Alpha + entry.Beta)
+
+		// Steps in this judge's column that send them to a team
+		currentTeamID := ""
+		if groupIdx >= 0 {
+			for step, row := range plan.Matrix {
+				if groupIdx < len(row) && row[groupIdx] != "" {
+					entry.ScheduledVisits++
+					if step == judge.CurrentTeam {
+						currentTeamID = row[groupIdx]
+					}
+				}
+			}
+		}
+		if judge.RetryTeam != "" {
+			currentTeamID = judge.RetryTeam
+		}
+		entry.CurrentTeamID = currentTeamID
+
+		judgeJudgments := judgmentsByJudge[judge.ID]
+		entry.JudgmentsMade = len(judgeJudgments)
+		if len(judgeJudgments) > 0 {
+			last := judgeJudgments[len(judgeJudgments)-1].Date
+			entry.LastJudgmentAt = &last
+			entry.SecondsSinceLastJudgment = int64(now.Sub(last).Seconds())
+		}
+
+		entry.Status = judgeDashboardStatus(judge, currentTeamID, entry.LastJudgmentAt, now, waitWindow)
+		response.StatusCounts[entry.Status]++
+
+		if entry.Status != judgeStatusFinished {
+			finish := projectJudgeFinish(judge, judgeJudgments, plan.Steps, now, waitWindow)
+			entry.ProjectedFinish = &finish
+		}
+
+		response.Judges = append(response.Judges, entry)
+	}
+
+	sort.Slice(response.Judges, func(i, j int) bool {
+		return response.Judges[i].Name < response.Judges[j].Name
+	})
+
+	// Per-team coverage from valid judgments, with the visits the matrix still has planned
+	scheduled := make(map[string]int)
+	for _, row := range plan.Matrix {
+		for _, teamID := range row {
+			if teamID != "" {
+				scheduled[teamID]++
+			}
+		}
+	}
+
+	opponents := make(map[string]map[string]bool)
+	teamJudges := make(map[string]map[string]bool)
+	timesJudged := make(map[string]int)
+	wins := make(map[string]int)
+	addComparison := func(teamID string, opponentID string, judgeID string) {
+		if opponents[teamID] == nil {
+			opponents[teamID] = make(map[string]bool)
+			teamJudges[teamID] = make(map[string]bool)
+		}
+		opponents[teamID][opponentID] = true
+		teamJudges[teamID][judgeID] = true
+		timesJudged[teamID]++
+	}
+	for _, judgment := range judgments {
+		addComparison(judgment.WinningTeamID, judgment.LosingTeamID, judgment.JudgeID)
+		addComparison(judgment.LosingTeamID, judgment.WinningTeamID, judgment.JudgeID)
+		wins[judgment.WinningTeamID]++
+	}
+
+	for _, team := range teams {
+		response.Teams = append(response.Teams, TeamCoverageEntry{
+			TeamID:          team.ID,
+			Name:            team.Name,
+			TimesJudged:     timesJudged[team.ID],
+			Wins:            wins[team.ID],
+			UniqueOpponents: len(opponents[team.ID]),
+			UniqueJudges:    len(teamJudges[team.ID]),
+			ScheduledVisits: scheduled[team.ID],
+		})
+	}
+
+	// Least covered teams first, since those are the ones organizers act on
+	sort.Slice(response.Teams, func(i, j int) bool {
+		if response.Teams[i].TimesJudged != response.Teams[j].TimesJudged {
+			return response.Teams[i].TimesJudged < response.Teams[j].TimesJudged
+		}
+		return response.Teams[i].TeamID < response.Teams[j].TeamID
+	})
+
+	return c.JSON(response)
+}
+
+// judgeDashboardStatus classifies where a judge is in their rotation
+func judgeDashboardStatus(judge models.Judge, currentTeamID string, lastJudgmentAt *time.Time, now time.Time, waitWindow time.Duration) string {
+	if judge.CurrentTeam < 0 {
+		return judgeStatusNotStarted
+	}
+	if judge.CurrentTeam >= 9000 {
+		return judgeStatusFinished
+	}
+
+	idleSince := judge.NextTeamTime
+	if lastJudgmentAt != nil && lastJudgmentAt.After(idleSince) {
+		idleSince = *lastJudgmentAt
+	}
+	if now.Sub(idleSince) > waitWindow {
+		return judgeStatusStuck
+	}
+
+	if currentTeamID == "" {
+		return judgeStatusResting
+	}
+
+	return judgeStatusJudging
+}
+
+// projectJudgeFinish extrapolates when a judge will be done, at the pace they
+// have kept between judgments so far but never faster than one wait window per step
+func projectJudgeFinish(judge models.Judge, judgments []models.Judgment, steps int, now time.Time, waitWindow time.Duration) time.Time {
+	remaining := steps - (judge.CurrentTeam + 1) + len(judge.RetryQueue)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	pace := waitWindow
+	if len(judgments) >= 2 {
+		first := judgments[0]
+		last := judgments[len(judgments)-1]
+		if stepsTaken := last.Step - first.Step; stepsTaken > 0 {
+			pace = max(pace, last.Date.Sub(first.Date)/time.Duration(stepsTaken))
+		}
+	}
+
+	start := now
+	if judge.NextTeamTime.After(start) {
+		start = judge.NextTeamTime
+	}
+
+	return start.Add(time.Duration(remaining) * pace)
+}
@@ -0,0 +1,151 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// finalistsResponse resolves the finalist teams for a response
+func finalistsResponse(finalists models.Finalists) (FinalistsResponse, errmsg.StatusError) {
+	teams, serr := finalists.LoadTeams()
+	if serr != errmsg.EmptyStatusError {
+		return FinalistsResponse{}, serr
+	}
+
+	return FinalistsResponse{
+		Finalists: teams,
+		Count:     finalists.Count,
+		Locked:    finalists.Locked,
+		Source:    finalists.Source,
+		UpdatedAt: finalists.UpdatedAt,
+		UpdatedBy: finalists.UpdatedBy,
+	}, errmsg.EmptyStatusError
+}
+
+// getFinalistsHandler retrieves the current finalists.
+// @Summary Get current finalists
+// @Description Returns the finalist teams in order, along with the finalist count, whether the list is locked, and whether it was last set by computing rankings or by hand.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {object} FinalistsResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/finalists [get]
+func getFinalistsHandler(c fiber.Ctx) error {
+	finalists, serr := models.GetFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	response, serr := finalistsResponse(finalists)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(response)
+}
+
+// updateFinalistsHandler overrides or reorders the finalists.
+// @Summary Override finalists
+// @Description Replaces the finalist list and/or the finalist count after deliberation. Teams are given in finalist order, so sending the current teams in a new order reorders them. Lowering the count below the number of teams is rejected. Once locked, finalists can't be changed until they are unlocked, and computing rankings leaves them alone.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body FinalistsUpdateRequest true "Finalist count and/or ordered team IDs"
+// @Success 200 {object} FinalistsResponse
+// @Failure 400 {object} errmsg._FinalistsInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._TeamNotFound
+// @Failure 409 {object} errmsg._FinalistsLocked
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/finalists [put]
+func updateFinalistsHandler(c fiber.Ctx) error {
+	var body FinalistsUpdateRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.FinalistsInvalid)
+	}
+
+	finalists, serr := models.GetFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	if finalists.Locked {
+		return utils.StatusError(c, errmsg.FinalistsLocked)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	if body.Count != nil {
+		finalists.Count = *body.Count
+	}
+	if body.Teams != nil {
+		finalists.Teams = body.Teams
+	}
+	finalists.Source = models.FinalistsSourceManual
+	finalists.UpdatedBy = superuser.Username
+
+	if serr := models.SaveFinalists(finalists); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.FinalistsUpdated(superuser.Username, finalists.Teams, finalists.Count)
+
+	response, serr := finalistsResponse(finalists)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(response)
+}
+
+// lockFinalistsHandler locks or unlocks the finalists.
+// @Summary Lock or unlock finalists
+// @Description Locked finalists can't be overridden, and computing rankings keeps them instead of taking the new top teams.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body FinalistsLockRequest true "Whether the finalists are locked"
+// @Success 200 {object} FinalistsResponse
+// @Failure 400 {object} errmsg._FinalistsInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/finalists/lock [put]
+func lockFinalistsHandler(c fiber.Ctx) error {
+	var body FinalistsLockRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.FinalistsInvalid)
+	}
+
+	finalists, serr := models.GetFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	finalists.Locked = body.Locked
+	finalists.UpdatedBy = superuser.Username
+
+	if serr := models.SaveFinalists(finalists); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.FinalistsLockSet(superuser.Username, finalists.Locked)
+
+	response, serr := finalistsResponse(finalists)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(response)
+}
@@ -75,7 +75,6 @@ func judgeCreateHandler(c fiber.Ctx) error {
 	}
 
 	judge := models.Judge{
-		ID:          utils.GenID(6),
 		Name:        body.Name,
 		Pair:        body.Pair,
 		CurrentTeam: -1,
@@ -94,37 +93,51 @@ func judgeCreateHandler(c fiber.Ctx) error {
 	return c.JSON(judge)
 }
 
+// loadScorerJudgments fetches all judgments and converts them to the format used by the scorer,
+// weighted by judge and without the judgments of excluded judges.
+func loadScorerJudgments() ([]models.Judgment, []utils.JudgmentWithJudge, errmsg.StatusError) {
+	judgments, serr := models.GetAllJudgments()
+	if serr != errmsg.EmptyStatusError {
+		return nil, nil, serr
+	}
+
+	return models.WeighJudgments(judgments)
+}
+
 // computeRankingsHandler computes team rankings using the CrowdBT algorithm
 // from all judgments currently in the database.
 // @Summary Compute team rankings from judgments
-// @Description Uses the Crowd Bradley-Terry algorithm to compute team rankings and judge reliability scores from all judgments in the database.
+// @Description Uses the Crowd Bradley-Terry algorithm to compute team rankings and judge reliability scores from all judgments in the database. The body is optional; any scorer option left out keeps its default.
 // @Tags Superusers Judging
 // @Security SuperUserAuth
 // @Accept json
 // @Produce json
+// @Param payload body utils.ScorerOptions false "Scorer option overrides"
 // @Success 200 {object} ComputeRankingsResponse
+// @Failure 400 {object} errmsg._JudgingInvalidScorerOptions
 // @Failure 401 {object} errmsg._SuperUserNoToken
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/judging/compute-rankings [post]
 func computeRankingsHandler(c fiber.Ctx) error {
+	// Overrides are applied on top of the defaults, so omitted fields keep them
+	options := utils.DefaultScorerOptions()
+	if len(c.Body()) > 0 {
+		if err := json.Unmarshal(c.Body(), &options); err != nil {
+			return utils.StatusError(c, errmsg.JudgingInvalidScorerOptions(err))
+		}
+	}
+	if err := options.Validate(); err != nil {
+		return utils.StatusError(c, errmsg.JudgingInvalidScorerOptions(err))
+	}
+
 	// Fetch all judgments from the database
-	judgments, serr := models.GetAllJudgments()
+	judgments, scorerJudgments, serr := loadScorerJudgments()
 	if serr != errmsg.EmptyStatusError {
 		return utils.StatusError(c, serr)
 	}
 
-	// Convert Judgment models to JudgmentWithJudge format for the scorer
-	var scorerJudgments []utils.JudgmentWithJudge
-	for _, judgment := range judgments {
-		scorerJudgments = append(scorerJudgments, utils.JudgmentWithJudge{
-			WinningTeamID: judgment.WinningTeamID,
-			LosingTeamID:  judgment.LosingTeamID,
-			JudgeID:       judgment.JudgeID,
-		})
-	}
-
 	// Run the CrowdBT scorer
-	scorer := utils.NewCrowdBTScorer()
+	scorer := utils.NewCrowdBTScorerWithOptions(options)
 	scorer.Score(scorerJudgments)
 
 	// Build the response
@@ -142,36 +155,18 @@ func computeRankingsHandler(c fiber.Ctx) error {
 		}
 	}
 
-	// Save finalists to settings (up to 5)
-	if len(rankedTeams) >= 1 {
-		finalist1 := &models.Setting{Name: models.SettingFinalist1, Value: rankedTeams[0]}
-		if serr := finalist1.Save(); serr != errmsg.EmptyStatusError {
-			return utils.StatusError(c, serr)
-		}
-	}
-	if len(rankedTeams) >= 2 {
-		finalist2 := &models.Setting{Name: models.SettingFinalist2, Value: rankedTeams[1]}
-		if serr := finalist2.Save(); serr != errmsg.EmptyStatusError {
-			return utils.StatusError(c, serr)
-		}
-	}
-	if len(rankedTeams) >= 3 {
-		finalist3 := &models.Setting{Name: models.SettingFinalist3, Value: rankedTeams[2]}
-		if serr := finalist3.Save(); serr != errmsg.EmptyStatusError {
-			return utils.StatusError(c, serr)
-		}
-	}
-	if len(rankedTeams) >= 4 {
-		finalist4 := &models.Setting{Name: models.SettingFinalist4, Value: rankedTeams[3]}
-		if serr := finalist4.Save(); serr != errmsg.EmptyStatusError {
-			return utils.StatusError(c, serr)
-		}
+	// Replace the live leaderboard with the refit
+	if serr := models.SaveLeaderboard(scorer, options, judgments); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
-	if len(rankedTeams) >= 5 {
-		finalist5 := &models.Setting{Name: models.SettingFinalist5, Value: rankedTeams[4]}
-		if serr := finalist5.Save(); serr != errmsg.EmptyStatusError {
-			return utils.StatusError(c, serr)
-		}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	// The top teams become finalists unless superusers have locked the list
+	finalistList, serr := models.SetComputedFinalists(rankedTeams, superuser.Username)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
 	// Fetch full team details for each ranked team
@@ -183,25 +178,9 @@ func computeRankingsHandler(c fiber.Ctx) error {
 		}
 	}
 
-	// Build finalists list in order from settings
-	var finalists []models.Team
-	finalistSettings := []string{
-		models.SettingFinalist1,
-		models.SettingFinalist2,
-		models.SettingFinalist3,
-		models.SettingFinalist4,
-		models.SettingFinalist5,
-	}
-
-	for _, settingName := range finalistSettings {
-		setting := &models.Setting{Name: settingName}
-		if serr := setting.Get(); serr == errmsg.EmptyStatusError {
-			if teamID, ok := setting.Value.(string); ok && teamID != "" {
-				if team, exists := teamDetails[teamID]; exists {
-					finalists = append(finalists, team)
-				}
-			}
-		}
+	finalists, serr := finalistList.LoadTeams()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
 	response := map[string]interface{}{
@@ -212,49 +191,13 @@ func computeRankingsHandler(c fiber.Ctx) error {
 		"judgmentCount":    len(judgments),
 		"teamDetails":      teamDetails,
 		"finalists":        finalists,
+		"finalistsLocked":  finalistList.Locked,
+		"diagnostics":      scorer.Diagnostics(),
 	}
 
 	return c.JSON(response)
 }
 
-// getFinalistsHandler retrieves the current finalists.
-// @Summary Get current finalists
-// @Description Returns the list of current finalist teams that have been saved after ranking computation.
-// @Tags Superusers Judging
-// @Security SuperUserAuth
-// @Produce json
-// @Success 200 {object} GetFinalistsResponse
-// @Failure 401 {object} errmsg._SuperUserNoToken
-// @Failure 500 {object} errmsg._InternalServerError
-// @Router /superusers/judging/finalists [get]
-func getFinalistsHandler(c fiber.Ctx) error {
-	finalistSettings := []string{
-		models.SettingFinalist1,
-		models.SettingFinalist2,
-		models.SettingFinalist3,
-		models.SettingFinalist4,
-		models.SettingFinalist5,
-	}
-
-	finalists := make([]models.Team, 0)
-
-	for _, settingName := range finalistSettings {
-		setting := &models.Setting{Name: settingName}
-		if err := setting.Get(); err == errmsg.EmptyStatusError {
-			if teamID, ok := setting.Value.(string); ok && teamID != "" {
-				team := models.Team{ID: teamID}
-				if err := team.Get(); err == nil {
-					finalists = append(finalists, team)
-				}
-			}
-		}
-	}
-
-	return c.JSON(bson.M{
-		"finalists": finalists,
-	})
-}
-
 // getAllJudgesHandler retrieves all judges with their current progress information.
 // @Summary Get all judges with progress
 // @Description Returns a list of all judges including their current team step and next available time.
@@ -282,31 +225,26 @@ func getAllJudgesHandler(c fiber.Ctx) error {
 
 // getVotingResultsHandler retrieves voting results with team details and vote counts.
 // @Summary Get voting results
-// @Description Returns all votes grouped by finalist team with full team details and vote counts.
+// @Description Returns all votes grouped by finalist team with full team details and vote counts. Each voting session keeps its own results; pass a session ID to see one round, or leave it out to count every vote.
 // @Tags Superusers Judging
 // @Security SuperUserAuth
 // @Produce json
+// @Param sessionID query string false "Only votes cast in this voting session"
 // @Success 200 {array} VotingResultItem
 // @Failure 401 {object} errmsg._SuperUserNoToken
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/judging/voting-results [get]
 func getVotingResultsHandler(c fiber.Ctx) error {
-	// Get all votes from database
-	allVotes, err := models.GetAllVotes()
+	// Count the votes of the requested session, or all of them, by first choice
+	voteCounts, err := models.AggregateVoteCounts(c.Query("sessionID"))
 	if err != nil {
 		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
 
-	// Count votes by team
-	voteCounts := make(map[string]int64)
-	for _, vote := range allVotes {
-		voteCounts[vote.Choice]++
-	}
-
 	// Build results with team details
 	results := make([]map[string]interface{}, 0)
 
-	for teamID, count := range voteCounts {
+	for teamID, count := range voteCounts.First {
 		team := models.Team{ID: teamID}
 		if err := team.Get(); err != nil {
 			// Skip teams that don't exist
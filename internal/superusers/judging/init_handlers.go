@@ -7,32 +7,15 @@ import (
 	"backend/internal/models"
 	"backend/internal/utils"
 	"encoding/json"
+	"log"
 	"math/rand"
 	"sort"
+	"strconv"
 
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-// getCoprimeMultipliers returns a list of numbers coprime to n
-func getCoprimeMultipliers(n int) []int {
-	coprimes := []int{}
-	for i := 1; i < n; i++ {
-		if gcd(i, n) == 1 {
-			coprimes = append(coprimes, i)
-		}
-	}
-	return coprimes
-}
-
-// gcd computes the greatest common divisor using Euclidean algorithm
-func gcd(a, b int) int {
-	for b != 0 {
-		a, b = b, a%b
-	}
-	return a
-}
-
 // JudgeInitMatrix represents the step-by-group assignment matrix
 type JudgeInitMatrix struct {
 	Steps         int        `json:"steps"`
@@ -41,6 +24,8 @@ type JudgeInitMatrix struct {
 	Assignments   int        `json:"assignments"`
 	BlankCells    int        `json:"blankCells"`
 	Collisions    int        `json:"collisions"`
+	Seed          int64      `json:"seed"`      // re-running init with this seed and the same roster rebuilds this matrix
+	Conflicts     int        `json:"conflicts"` // group/team pairs kept apart by declared conflicts
 	GavelScore    float64    `json:"gavelScore"`
 	UniquePairs   int        `json:"uniquePairs"`
 	TotalPairs    int        `json:"totalPairs"`
@@ -50,40 +35,39 @@ type JudgeInitMatrix struct {
 
 // judgeInitHandler initializes judging settings with judge pairing system.
 // @Summary Initialize judging configuration with judge pairing
-// @Description Groups judges by pair attribute and creates Latin rectangle assignment
+// @Description Groups judges by pair attribute and creates Latin rectangle assignment. A group is never assigned a team that any of its judges has declared a conflict with. This starts judging over: every judge goes back to the first step with no visits or pending retries, and every valid judgment is archived, so it no longer counts towards rankings and its step can be judged again. Pass mode=replan to keep the progress made so far.
 // @Tags Superusers Judging
 // @Security SuperUserAuth
 // @Produce json
+// @Param mode query string false "Leave empty for a fresh start, or replan"
+// @Param seed query int false "Random seed, to reproduce an earlier matrix; one is picked and recorded when left out"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errmsg._JudgingInvalidInitMode
+// @Failure 400 {object} errmsg._JudgingInvalidSeed
 // @Failure 401 {object} errmsg._SuperUserNoToken
 // @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/judging/init [post]
 func judgeInitHandler(c fiber.Ctx) error {
-	superuser := models.SuperUser{}
-	utils.GetLocals(c, "superuser", &superuser)
-
-	// Fetch all non-deleted teams and judges
-	// Use $ne to include teams without a deleted field (they're not deleted)
-	cursor, err := db.Teams.Find(db.Ctx, bson.M{"deleted": bson.M{"$ne": true}})
-	if err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
+	switch c.Query("mode") {
+	case "":
+	case judgingInitModeReplan:
+		return judgeReplanHandler(c)
+	default:
+		return utils.StatusError(c, errmsg.JudgingInvalidInitMode)
 	}
-	defer cursor.Close(db.Ctx)
 
-	var teams []models.Team
-	if err = cursor.All(db.Ctx, &teams); err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
+	seed, serr := judgingSeed(c)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
+	rng := rand.New(rand.NewSource(seed))
 
-	cursorJudges, err := db.Judges.Find(db.Ctx, bson.M{})
-	if err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
-	}
-	defer cursorJudges.Close(db.Ctx)
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
 
-	var judges []models.Judge
-	if err = cursorJudges.All(db.Ctx, &judges); err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
+	teams, judges, serr := loadJudgingRoster()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
 	numTeams := len(teams)
@@ -96,55 +80,39 @@ func judgeInitHandler(c fiber.Ctx) error {
 	}
 
 	// === PHASE 1: GROUP JUDGES BY PAIR ATTRIBUTE ===
-	pairGroups := make(map[string][]string)
+	judgePairGroups := groupJudgesByPair(judges)
 	judgeIDToGroupIdx := make(map[string]int)
-
-	for _, judge := range judges {
-		pairAttr := judge.Pair
-		if pairAttr == "" {
-			pairAttr = judge.ID
-		}
-		pairGroups[pairAttr] = append(pairGroups[pairAttr], judge.ID)
-	}
-
-	var pairAttrKeys []string
-	for attr := range pairGroups {
-		pairAttrKeys = append(pairAttrKeys, attr)
-	}
-	sort.Strings(pairAttrKeys)
-
-	var judgePairGroups []JudgePairGroup
-	for groupID, attr := range pairAttrKeys {
-		group := JudgePairGroup{
-			GroupID:   groupID,
-			JudgeIDs:  pairGroups[attr],
-			PairAttr:  attr,
-			NumJudges: len(pairGroups[attr]),
-		}
-		judgePairGroups = append(judgePairGroups, group)
-
+	for _, group := range judgePairGroups {
 		for _, judgeID := range group.JudgeIDs {
-			judgeIDToGroupIdx[judgeID] = groupID
+			judgeIDToGroupIdx[judgeID] = group.GroupID
 		}
 	}
 
 	numPairGroups := len(judgePairGroups)
 
+	// A group cannot visit a team that any of its judges conflicts with
+	blockedTeams, conflicts, serr := conflictBlockedTeams(judgePairGroups, teams)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	// === PHASE 2: CREATE SHUFFLED TEAM ORDERS ===
 	teamIDs := make([]string, numTeams)
 	for i, team := range teams {
 		teamIDs[i] = team.ID
 	}
+	// The database returns teams in no particular order; sort so the seed alone decides the shuffle
+	sort.Strings(teamIDs)
 
 	teamOrderA := make([]string, numTeams)
 	copy(teamOrderA, teamIDs)
-	rand.Shuffle(len(teamOrderA), func(i, j int) {
+	rng.Shuffle(len(teamOrderA), func(i, j int) {
 		teamOrderA[i], teamOrderA[j] = teamOrderA[j], teamOrderA[i]
 	})
 
 	teamOrderB := make([]string, numTeams)
 	copy(teamOrderB, teamIDs)
-	rand.Shuffle(len(teamOrderB), func(i, j int) {
+	rng.Shuffle(len(teamOrderB), func(i, j int) {
 		teamOrderB[i], teamOrderB[j] = teamOrderB[j], teamOrderB[i]
 	})
 
@@ -160,272 +128,212 @@ func judgeInitHandler(c fiber.Ctx) error {
 		if different {
 			break
 		}
-		rand.Shuffle(len(teamOrderB), func(i, j int) {
+		rng.Shuffle(len(teamOrderB), func(i, j int) {
 			teamOrderB[i], teamOrderB[j] = teamOrderB[j], teamOrderB[i]
 		})
 	}
 
 	events.Em.JudgeInitTeamOrderSet(superuser.Username, teamOrderA)
 
-	// === PHASE 3: CALCULATE STEPS ===
-	numSteps := numPairGroups
-	if numTeams > numPairGroups {
-		numSteps = numTeams
+	// === PHASE 3-4: BUILD LATIN RECTANGLE ===
+	matrix, err := utils.BuildJudgeMatrix(rng, teamOrderA, numPairGroups, blockedTeams)
+	if err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
 	}
+	numSteps := len(matrix)
 
-	// === PHASE 4: BUILD LATIN RECTANGLE WITH EVEN BLANK DISTRIBUTION ===
-	// Strategy: For each group, randomly shuffle step assignments with blanks evenly distributed
-	// Then assign teams per-step ensuring no collisions (same team in same step for different groups)
-
-	matrix := make([][]string, numSteps)
-	for i := range numSteps {
-		matrix[i] = make([]string, numPairGroups)
+	// === PHASE 5: CALCULATE METRICS ===
+	matrixObj := JudgeInitMatrix{
+		Steps:     numSteps,
+		Groups:    numPairGroups,
+		Teams:     numTeams,
+		Seed:      seed,
+		Conflicts: conflicts,
+		Matrix:    matrix,
 	}
+	matrixObj.computeMetrics()
 
-	pairGroupTeamsSeen := make(map[int]map[string]bool)
-	for i := range numPairGroups {
-		pairGroupTeamsSeen[i] = make(map[string]bool)
+	// === PHASE 6: SAVE SETTINGS ===
+	// Progress through the old matrix means nothing in the new one, and neither
+	// do the steps its judgments were made at
+	if serr := models.ResetJudgingProgress(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
-
-	// For each group, determine which steps it will be active (rest of steps are blank)
-	// Distribute blanks evenly
-	activeStepsPerGroup := make([][]int, numPairGroups)
-	blanksPerGroup := numSteps - numTeams // How many blanks each group should have
-	if blanksPerGroup < 0 {
-		blanksPerGroup = 0
+	archivedJudgments, serr := models.ArchiveJudgments(superuser.Username)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
-
-	for groupIdx := 0; groupIdx < numPairGroups; groupIdx++ {
-		// Create list of all steps
-		allSteps := make([]int, numSteps)
-		for i := 0; i < numSteps; i++ {
-			allSteps[i] = i
+	if archivedJudgments > 0 {
+		if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+			log.Printf("failed to rebuild leaderboard after archiving %d judgments: %s", archivedJudgments, serr.Message)
 		}
-
-		// Shuffle and take the first (numSteps - blanksPerGroup) as active
-		rand.Shuffle(len(allSteps), func(i, j int) {
-			allSteps[i], allSteps[j] = allSteps[j], allSteps[i]
-		})
-
-		activeCount := numSteps - blanksPerGroup
-		activeStepsPerGroup[groupIdx] = allSteps[:activeCount]
-		sort.Ints(activeStepsPerGroup[groupIdx]) // Keep in order for consistency
 	}
-
-	// Now assign teams to active steps, ensuring no collisions
-	teamsAssignedPerStep := make(map[int]map[string]bool)
-	for i := 0; i < numSteps; i++ {
-		teamsAssignedPerStep[i] = make(map[string]bool)
+	if serr := saveJudgingPlan(judgeIDToGroupIdx, matrixObj, superuser.Username); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
-	coprimes := getCoprimeMultipliers(numTeams)
-	if len(coprimes) == 0 {
-		return utils.StatusError(c, errmsg.InternalServerError(
-			&errorMessage{message: "no coprime multipliers found"},
-		))
+	// Save waitMinutes setting (1 minute for testing)
+	waitMinutesSetting := models.Setting{
+		Name:  models.SettingWaitMinutes,
+		Value: "5",
 	}
-
-	// Assign offset and multiplier to each group for team selection
-	pairOffsets := make([]int, numPairGroups)
-	pairMultipliers := make([]int, numPairGroups)
-
-	for i := range numPairGroups {
-		pairOffsets[i] = rand.Intn(numTeams)
-		pairMultipliers[i] = coprimes[i%len(coprimes)]
+	if serr := waitMinutesSetting.Save(superuser.Username); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
 	}
 
-	rand.Shuffle(len(pairMultipliers), func(i, j int) {
-		pairMultipliers[i], pairMultipliers[j] = pairMultipliers[j], pairMultipliers[i]
+	return c.JSON(bson.M{
+		"message":            "judging initialized with judge pairing",
+		"numTeams":           numTeams,
+		"numJudges":          numJudges,
+		"numPairGroups":      numPairGroups,
+		"numSteps":           numSteps,
+		"collisions":         matrixObj.Collisions,
+		"seed":               seed,
+		"conflicts":          conflicts,
+		"gavelScore":         matrixObj.GavelScore,
+		"uniquePairs":        matrixObj.UniquePairs,
+		"totalPossiblePairs": matrixObj.TotalPairs,
+		"averageRedundancy":  matrixObj.AvgRedundancy,
+		"waitMinutes":        waitMinutesSetting.Value,
+		"archivedJudgments":  archivedJudgments,
 	})
+}
 
-	// Assign teams step-by-step to prevent collisions
-	// For each step, go through groups that are active in that step
-	for step := 0; step < numSteps; step++ {
-		for groupIdx := 0; groupIdx < numPairGroups; groupIdx++ {
-			// Check if this group is active in this step
-			isActiveInStep := false
-			stepInGroup := -1
-			for idx, activeStep := range activeStepsPerGroup[groupIdx] {
-				if activeStep == step {
-					isActiveInStep = true
-					stepInGroup = idx
-					break
-				}
-			}
+// judgingSeed reads the seed query parameter, or picks a random seed if there is none
+func judgingSeed(c fiber.Ctx) (int64, errmsg.StatusError) {
+	value := c.Query("seed")
+	if value == "" {
+		return utils.GenSeed(), errmsg.EmptyStatusError
+	}
 
-			if !isActiveInStep {
-				// This group is resting in this step
-				matrix[step][groupIdx] = ""
-				continue
-			}
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errmsg.JudgingInvalidSeed
+	}
 
-			// This group is active in this step, find a team
-			offset := pairOffsets[groupIdx]
-			multiplier := pairMultipliers[groupIdx]
+	return seed, errmsg.EmptyStatusError
+}
 
-			// Calculate team index using offset + step*multiplier
-			teamIndex := (offset + stepInGroup*multiplier) % numTeams
+// loadJudgingRoster fetches the teams still in the running and every judge
+func loadJudgingRoster() (teams []models.Team, judges []models.Judge, serr errmsg.StatusError) {
+	// Use $ne to include teams without a deleted field (they're not deleted)
+	cursor, err := db.Teams.Find(db.Ctx, bson.M{"deleted": bson.M{"$ne": true}})
+	if err != nil {
+		return nil, nil, errmsg.InternalServerError(err)
+	}
+	defer cursor.Close(db.Ctx)
 
-			// Try to find a team that:
-			// 1. Hasn't been assigned to this group yet
-			// 2. Isn't already assigned to ANY other group in this step
-			found := false
-			attempts := 0
-			maxAttempts := numTeams
+	if err = cursor.All(db.Ctx, &teams); err != nil {
+		return nil, nil, errmsg.InternalServerError(err)
+	}
 
-			for attempts < maxAttempts {
-				candidateTeamID := teamOrderA[teamIndex]
+	cursorJudges, err := db.Judges.Find(db.Ctx, bson.M{})
+	if err != nil {
+		return nil, nil, errmsg.InternalServerError(err)
+	}
+	defer cursorJudges.Close(db.Ctx)
 
-				if !pairGroupTeamsSeen[groupIdx][candidateTeamID] && !teamsAssignedPerStep[step][candidateTeamID] {
-					matrix[step][groupIdx] = candidateTeamID
-					pairGroupTeamsSeen[groupIdx][candidateTeamID] = true
-					teamsAssignedPerStep[step][candidateTeamID] = true
-					found = true
-					break
-				}
+	if err = cursorJudges.All(db.Ctx, &judges); err != nil {
+		return nil, nil, errmsg.InternalServerError(err)
+	}
 
-				teamIndex = (teamIndex + 1) % numTeams
-				attempts++
-			}
+	return teams, judges, errmsg.EmptyStatusError
+}
 
-			if !found {
-				// No valid team found for this group in this step, leave blank
-				matrix[step][groupIdx] = ""
-			}
+// groupJudgesByPair puts judges sharing a pair attribute in one group, ordered by
+// attribute. A judge without a pair attribute judges alone.
+func groupJudgesByPair(judges []models.Judge) []JudgePairGroup {
+	pairGroups := make(map[string][]string)
+	for _, judge := range judges {
+		pairAttr := judge.Pair
+		if pairAttr == "" {
+			pairAttr = judge.ID
 		}
+		pairGroups[pairAttr] = append(pairGroups[pairAttr], judge.ID)
 	}
 
-	// === PHASE 5: CALCULATE METRICS ===
-	assignments := 0
-	blanks := 0
-	collisions := 0
-
-	for step := 0; step < numSteps; step++ {
-		teamCounts := make(map[string]int)
-		for groupIdx := 0; groupIdx < numPairGroups; groupIdx++ {
-			teamID := matrix[step][groupIdx]
-			if teamID == "" {
-				blanks++
-			} else {
-				assignments++
-				teamCounts[teamID]++
-			}
-		}
-		for _, count := range teamCounts {
-			if count > 1 {
-				collisions += (count - 1)
-			}
-		}
+	var pairAttrKeys []string
+	for attr := range pairGroups {
+		pairAttrKeys = append(pairAttrKeys, attr)
 	}
+	sort.Strings(pairAttrKeys)
 
-	// Calculate Gavel score (pairwise coverage)
-	type TeamPair struct {
-		TeamA string
-		TeamB string
+	var judgePairGroups []JudgePairGroup
+	for groupID, attr := range pairAttrKeys {
+		judgePairGroups = append(judgePairGroups, JudgePairGroup{
+			GroupID:   groupID,
+			JudgeIDs:  pairGroups[attr],
+			PairAttr:  attr,
+			NumJudges: len(pairGroups[attr]),
+		})
 	}
 
-	uniquePairs := make(map[TeamPair]int)
-	for step := 0; step < numSteps; step++ {
-		assignedTeams := []string{}
-		for groupIdx := 0; groupIdx < numPairGroups; groupIdx++ {
-			if matrix[step][groupIdx] != "" {
-				assignedTeams = append(assignedTeams, matrix[step][groupIdx])
-			}
-		}
+	return judgePairGroups
+}
 
-		// Record pairwise comparisons
-		for i := 0; i < len(assignedTeams); i++ {
-			for j := i + 1; j < len(assignedTeams); j++ {
-				pair := TeamPair{}
-				if assignedTeams[i] < assignedTeams[j] {
-					pair.TeamA = assignedTeams[i]
-					pair.TeamB = assignedTeams[j]
-				} else {
-					pair.TeamA = assignedTeams[j]
-					pair.TeamB = assignedTeams[i]
+// conflictBlockedTeams returns, per group ID, the teams any of the group's judges
+// has declared a conflict with, along with the number of blocked group/team pairs
+func conflictBlockedTeams(groups []JudgePairGroup, teams []models.Team) (map[int]map[string]bool, int, errmsg.StatusError) {
+	conflictIndex, serr := models.LoadJudgeConflictIndex()
+	if serr != errmsg.EmptyStatusError {
+		return nil, 0, serr
+	}
+
+	blockedTeams := make(map[int]map[string]bool)
+	conflicts := 0
+	for _, group := range groups {
+		blockedTeams[group.GroupID] = make(map[string]bool)
+		for _, team := range teams {
+			for _, judgeID := range group.JudgeIDs {
+				if conflictIndex.Conflicts(judgeID, team.ID) {
+					blockedTeams[group.GroupID][team.ID] = true
+					conflicts++
+					break
 				}
-				uniquePairs[pair]++
 			}
 		}
 	}
 
-	totalPossiblePairs := (numTeams * (numTeams - 1)) / 2
-	uniquePairsCount := len(uniquePairs)
-	gavelScore := float64(uniquePairsCount) / float64(totalPossiblePairs) * 100
-
-	totalComparisons := 0
-	for _, count := range uniquePairs {
-		totalComparisons += count
-	}
-
-	var avgRedundancy float64
-	if uniquePairsCount > 0 {
-		avgRedundancy = float64(totalComparisons) / float64(uniquePairsCount)
-	}
+	return blockedTeams, conflicts, errmsg.EmptyStatusError
+}
 
-	// === PHASE 6: SAVE SETTINGS ===
+// computeMetrics fills in the assignment, collision and pairwise coverage
+// figures for the matrix
+func (m *JudgeInitMatrix) computeMetrics() {
+	metrics := utils.MeasureJudgeMatrix(m.Matrix, m.Groups, m.Teams)
+
+	m.Assignments = metrics.Assignments
+	m.BlankCells = metrics.BlankCells
+	m.Collisions = metrics.Collisions
+	m.GavelScore = metrics.GavelScore
+	m.UniquePairs = metrics.UniquePairs
+	m.TotalPairs = metrics.TotalPairs
+	m.AvgRedundancy = metrics.AvgRedundancy
+}
 
+// saveJudgingPlan stores the judge to group mapping and the assignment matrix
+func saveJudgingPlan(judgeIDToGroupIdx map[string]int, matrixObj JudgeInitMatrix, savedBy string) errmsg.StatusError {
 	judgeToGroupIndexJSON, err := json.Marshal(judgeIDToGroupIdx)
 	if err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
+		return errmsg.InternalServerError(err)
 	}
 	judgeToGroupIndexSetting := models.Setting{
 		Name:  models.SettingJudgeToGroupIndex,
 		Value: string(judgeToGroupIndexJSON),
 	}
-	if serr := judgeToGroupIndexSetting.Save(); serr != errmsg.EmptyStatusError {
-		return utils.StatusError(c, serr)
-	}
-
-	// Save the matrix
-	matrixObj := JudgeInitMatrix{
-		Steps:         numSteps,
-		Groups:        numPairGroups,
-		Teams:         numTeams,
-		Assignments:   assignments,
-		BlankCells:    blanks,
-		Collisions:    collisions,
-		GavelScore:    gavelScore,
-		UniquePairs:   uniquePairsCount,
-		TotalPairs:    totalPossiblePairs,
-		AvgRedundancy: avgRedundancy,
-		Matrix:        matrix,
+	if serr := judgeToGroupIndexSetting.Save(savedBy); serr != errmsg.EmptyStatusError {
+		return serr
 	}
 
 	matrixJSON, err := json.Marshal(matrixObj)
 	if err != nil {
-		return utils.StatusError(c, errmsg.InternalServerError(err))
+		return errmsg.InternalServerError(err)
 	}
 	matrixSetting := models.Setting{
 		Name:  models.SettingJudgeInitMatrix,
 		Value: string(matrixJSON),
 	}
-	if serr := matrixSetting.Save(); serr != errmsg.EmptyStatusError {
-		return utils.StatusError(c, serr)
-	}
-
-	// Save waitMinutes setting (1 minute for testing)
-	waitMinutesSetting := models.Setting{
-		Name:  models.SettingWaitMinutes,
-		Value: "5",
-	}
-	if serr := waitMinutesSetting.Save(); serr != errmsg.EmptyStatusError {
-		return utils.StatusError(c, serr)
-	}
-
-	return c.JSON(bson.M{
-		"message":            "judging initialized with judge pairing",
-		"numTeams":           numTeams,
-		"numJudges":          numJudges,
-		"numPairGroups":      numPairGroups,
-		"numSteps":           numSteps,
-		"collisions":         collisions,
-		"gavelScore":         gavelScore,
-		"uniquePairs":        uniquePairsCount,
-		"totalPossiblePairs": totalPossiblePairs,
-		"averageRedundancy":  avgRedundancy,
-		"waitMinutes":        waitMinutesSetting.Value,
-	})
+	return matrixSetting.Save(savedBy)
 }
 
 // errorMessage is a simple error wrapper for the InternalServerError function
@@ -0,0 +1,84 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getJudgmentsHandler lists judgments together with their audit trail.
+// @Summary List judgments
+// @Description Returns judgments of every status with their change history, oldest first. Can be narrowed to one judge or one status (valid, retracted, invalidated, archived).
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Param judgeID query string false "Only judgments by this judge"
+// @Param status query string false "Only judgments with this status"
+// @Success 200 {array} models.Judgment
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/judgments [get]
+func getJudgmentsHandler(c fiber.Ctx) error {
+	judgments, serr := models.GetJudgments(c.Query("judgeID"), c.Query("status"))
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(judgments)
+}
+
+// invalidateJudgmentHandler discards a judgment so it no longer affects rankings.
+// @Summary Invalidate a judgment
+// @Description Marks a judgment as invalidated with the given reason and refits the live leaderboard without it. The judgment is kept for auditing.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body JudgmentInvalidateRequest true "Judgment ID and reason"
+// @Success 200 {object} models.Judgment
+// @Failure 400 {object} errmsg._JudgmentReasonRequired
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._JudgmentNotFound
+// @Failure 409 {object} errmsg._JudgmentNotValid
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/judgment/invalidate [post]
+func invalidateJudgmentHandler(c fiber.Ctx) error {
+	var body struct {
+		ID     string `json:"id"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	if body.ID == "" {
+		return utils.StatusError(c, errmsg.JudgmentNotFound)
+	}
+
+	reason := strings.TrimSpace(body.Reason)
+	if reason == "" {
+		return utils.StatusError(c, errmsg.JudgmentReasonRequired)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	judgment := models.Judgment{ID: body.ID}
+	if serr := judgment.Invalidate(superuser.Username, reason); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to rebuild leaderboard after invalidating judgment %s: %s", judgment.ID, serr.Message)
+	}
+
+	events.Em.JudgmentInvalidated(superuser.Username, judgment.ID, reason)
+
+	return c.JSON(judgment)
+}
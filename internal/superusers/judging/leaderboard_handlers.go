@@ -0,0 +1,28 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/models"
+	"backend/internal/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getLeaderboardHandler returns the live CrowdBT standings.
+// @Summary Get live leaderboard
+// @Description Returns the latest team standings without refitting. Standings are updated online with every judgment and replaced by each compute-rankings run; source tells which of the two produced them.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {object} models.Leaderboard
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/leaderboard [get]
+func getLeaderboardHandler(c fiber.Ctx) error {
+	leaderboard, serr := models.GetLeaderboard()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(leaderboard)
+}
@@ -0,0 +1,30 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/models"
+	"backend/internal/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getJudgeNotesHandler lists judges' private notes for deliberation.
+// @Summary List judge notes
+// @Description Returns the private notes judges wrote about teams, most recently updated first. Can be narrowed to one judge or one team.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Param judgeID query string false "Only notes by this judge"
+// @Param teamID query string false "Only notes about this team"
+// @Success 200 {array} models.JudgeNote
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/notes [get]
+func getJudgeNotesHandler(c fiber.Ctx) error {
+	notes, serr := models.GetJudgeNotes(c.Query("judgeID"), c.Query("teamID"))
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(notes)
+}
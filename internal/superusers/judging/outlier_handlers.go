@@ -0,0 +1,184 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// judgeOutliersHandler flags judges whose judgments look unreliable.
+// @Summary Detect outlier judges
+// @Description Analyzes every judgment that counts towards the rankings, whatever its judge's weight. A judge is flagged for low_agreement when their judgments often disagree with a fit on the other judges' judgments, low_reliability when the scorer's alpha/(alpha+beta) is low, fast when most gaps between their judgments are short, and side_bias when they almost always pick the team they were shown first (their previous team), or almost never. Judges with fewer than minJudgments judgments are reported but never flagged. Each judge's current weight is included; see PUT /superusers/judging/judge-weight.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Param minJudgments query int false "Judgments needed before a judge can be flagged (default 5)"
+// @Param minAgreement query number false "Flag agreement below this (default 0.5)"
+// @Param agreementZScore query number false "Flag agreement this many standard deviations below the mean (default 2)"
+// @Param minReliability query number false "Flag alpha/(alpha+beta) below this (default 0.75)"
+// @Param fastSeconds query number false "Gaps shorter than this many seconds are fast (default 60)"
+// @Param fastShare query number false "Flag when this share of gaps is fast (default 0.5)"
+// @Param sideBias query number false "Flag when the first team wins this often, or 1 minus this at most (default 0.9)"
+// @Success 200 {object} JudgeOutliersResponse
+// @Failure 400 {object} errmsg._JudgingInvalidOutlierOptions
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/judge-outliers [get]
+func judgeOutliersHandler(c fiber.Ctx) error {
+	options := utils.DefaultJudgeOutlierOptions()
+
+	if raw := c.Query("minJudgments"); raw != "" {
+		minJudgments, err := strconv.Atoi(raw)
+		if err != nil {
+			return utils.StatusError(c, errmsg.JudgingInvalidOutlierOptions)
+		}
+		options.MinJudgments = minJudgments
+	}
+
+	thresholds := map[string]*float64{
+		"minAgreement":    &options.MinAgreement,
+		"agreementZScore": &options.AgreementZScore,
+		"minReliability":  &options.MinReliability,
+		"fastSeconds":     &options.FastSeconds,
+		"fastShare":       &options.FastShare,
+		"sideBias":        &options.SideBias,
+	}
+	for name, threshold := range thresholds {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return utils.StatusError(c, errmsg.JudgingInvalidOutlierOptions)
+		}
+		*threshold = value
+	}
+
+	// Judge against the same scorer settings as the live leaderboard
+	lb, serr := models.GetLeaderboard()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	if lb.Options.Validate() == nil {
+		options.Scorer = lb.Options
+	}
+
+	if err := options.Validate(); err != nil {
+		return utils.StatusError(c, errmsg.JudgingInvalidOutlierOptions)
+	}
+
+	judgments, serr := models.GetAllJudgments()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	_, judges, serr := loadJudgingRoster()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	judgesByID := make(map[string]models.Judge)
+	for _, judge := range judges {
+		judgesByID[judge.ID] = judge
+	}
+
+	weights, serr := models.GetJudgeWeights()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	observations := make([]utils.JudgeObservation, 0, len(judgments))
+	for _, judgment := range judgments {
+		observations = append(observations, utils.JudgeObservation{
+			JudgmentWithJudge: utils.JudgmentWithJudge{
+				WinningTeamID: judgment.WinningTeamID,
+				LosingTeamID:  judgment.LosingTeamID,
+				JudgeID:       judgment.JudgeID,
+			},
+			Date:      judgment.Date,
+			FirstTeam: judgmentFirstTeam(judgesByID[judgment.JudgeID], judgment),
+		})
+	}
+
+	response := JudgeOutliersResponse{
+		Options: options,
+		Judges:  []JudgeOutlierEntry{},
+	}
+	for _, report := range utils.AnalyzeJudgeOutliers(observations, options) {
+		weight, adjusted := weights[report.JudgeID]
+		if !adjusted {
+			weight = models.JudgeWeightDefault
+		}
+
+		response.Judges = append(response.Judges, JudgeOutlierEntry{
+			JudgeOutlierReport: report,
+			Name:               judgesByID[report.JudgeID].Name,
+			Weight:             weight,
+		})
+		if len(report.Flags) > 0 {
+			response.Flagged++
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// judgmentFirstTeam returns the team the judge was shown first for a judgment:
+// the one that isn't the team they visited at the judgment's step
+func judgmentFirstTeam(judge models.Judge, judgment models.Judgment) string {
+	for _, visit := range judge.Visits {
+		if visit.Step != judgment.Step || visit.Skipped {
+			continue
+		}
+		switch visit.TeamID {
+		case judgment.WinningTeamID:
+			return judgment.LosingTeamID
+		case judgment.LosingTeamID:
+			return judgment.WinningTeamID
+		}
+	}
+
+	return ""
+}
+
+// setJudgeWeightHandler down-weights or excludes a judge's judgments.
+// @Summary Set a judge's weight
+// @Description Sets how much a judge's judgments count in compute-rankings, rank stability and the live leaderboard, from 0 (excluded) to 1 (the default). The leaderboard is refit right away.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body JudgeWeightRequest true "Judge ID and weight"
+// @Success 200 {object} JudgeWeightRequest
+// @Failure 400 {object} errmsg._JudgeWeightInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._JudgeNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/judge-weight [put]
+func setJudgeWeightHandler(c fiber.Ctx) error {
+	var body JudgeWeightRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil || body.Weight == nil {
+		return utils.StatusError(c, errmsg.JudgeWeightInvalid)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	if serr := models.SetJudgeWeight(body.JudgeID, *body.Weight, superuser.Username); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if serr := models.RebuildLeaderboard(); serr != errmsg.EmptyStatusError {
+		log.Printf("failed to rebuild leaderboard after weighting judge %s: %s", body.JudgeID, serr.Message)
+	}
+
+	events.Em.JudgeWeightSet(superuser.Username, body.JudgeID, *body.Weight)
+
+	return c.JSON(body)
+}
@@ -0,0 +1,392 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"sort"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const judgingInitModeReplan = "replan"
+
+// replanColumn is one pair group's column in the re-planned matrix
+type replanColumn struct {
+	group  JudgePairGroup
+	oldCol int  // column in the previous matrix, or -1 for a new group
+	freeze int  // steps before this one are kept as they were
+	closed bool // every judge in the group has finished, so it visits no more teams
+}
+
+// judgeReplanHandler rebuilds the remaining steps of the judging matrix.
+// @Summary Re-plan judging without losing progress
+// @Description Called as /superusers/judging/init?mode=replan. Every step a group's judges have already reached is kept, so judge progress and judgments stay valid. Late judges get their own group (or join their pair's group), groups whose judges were all deleted are dropped, deleted teams are removed from the matrix, and the remaining steps are rebuilt so each group visits the teams it hasn't seen yet without two groups visiting the same team at the same step. A group whose judges have all finished is closed: it keeps its steps but gets no new visits. Pending retries of skipped teams are dropped when the team was deleted, conflicts with the judge's group, or is visited again in the rebuilt steps.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Param mode query string true "replan"
+// @Param seed query int false "Random seed for the rebuilt steps; one is picked and recorded when left out"
+// @Success 200 {object} JudgeReplanResponse
+// @Failure 400 {object} errmsg._JudgingInvalidInitMode
+// @Failure 400 {object} errmsg._JudgingInvalidSeed
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 409 {object} errmsg._JudgingNotInitialized
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/init [post]
+func judgeReplanHandler(c fiber.Ctx) error {
+	seed, serr := judgingSeed(c)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	previous, oldGroupIdx, serr := loadJudgingPlan()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teams, judges, serr := loadJudgingRoster()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	if len(teams) == 0 || len(judges) == 0 {
+		return utils.StatusError(c, errmsg.InternalServerError(
+			&errorMessage{message: "no teams or judges found"},
+		))
+	}
+
+	judgesByID := make(map[string]models.Judge)
+	for _, judge := range judges {
+		judgesByID[judge.ID] = judge
+	}
+
+	activeTeams := make(map[string]bool)
+	for _, team := range teams {
+		activeTeams[team.ID] = true
+	}
+
+	// Existing groups keep their column order; new groups are appended
+	columns := matchReplanColumns(groupJudgesByPair(judges), oldGroupIdx, previous.Groups)
+	groups := make([]JudgePairGroup, len(columns))
+	judgeIDToGroupIdx := make(map[string]int)
+	for i := range columns {
+		columns[i].group.GroupID = i
+		groups[i] = columns[i].group
+		for _, judgeID := range columns[i].group.JudgeIDs {
+			judgeIDToGroupIdx[judgeID] = i
+		}
+	}
+
+	blockedTeams, conflicts, serr := conflictBlockedTeams(groups, teams)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	// A group's progress is that of its furthest judge; a judge who finished has
+	// used up the whole column. Finished judges never ask for another team, so a
+	// group made only of them is closed rather than given steps it won't reach.
+	seen := make([]map[string]bool, len(columns))
+	maxFreeze := 0
+	for i := range columns {
+		seen[i] = make(map[string]bool)
+		columns[i].closed = true
+		for _, judgeID := range columns[i].group.JudgeIDs {
+			judge := judgesByID[judgeID]
+			if judge.CurrentTeam+1 > columns[i].freeze {
+				columns[i].freeze = judge.CurrentTeam + 1
+			}
+			if judge.CurrentTeam < previous.Steps {
+				columns[i].closed = false
+			}
+			for _, visit := range judge.Visits {
+				seen[i][visit.TeamID] = true
+			}
+		}
+		columns[i].freeze = min(columns[i].freeze, previous.Steps)
+		maxFreeze = max(maxFreeze, columns[i].freeze)
+	}
+
+	// Teams each group still has to visit, in a per-group random order
+	remaining := make([][]string, len(columns))
+	for i, column := range columns {
+		if column.oldCol >= 0 {
+			for step := 0; step < column.freeze; step++ {
+				seen[i][previous.Matrix[step][column.oldCol]] = true
+			}
+		}
+
+		if column.closed {
+			continue
+		}
+		for _, team := range teams {
+			if !seen[i][team.ID] && !blockedTeams[i][team.ID] {
+				remaining[i] = append(remaining[i], team.ID)
+			}
+		}
+		rng.Shuffle(len(remaining[i]), func(a, b int) {
+			remaining[i][a], remaining[i][b] = remaining[i][b], remaining[i][a]
+		})
+	}
+
+	numSteps := max(maxFreeze, len(columns), len(teams))
+	for i, column := range columns {
+		numSteps = max(numSteps, column.freeze+len(remaining[i]))
+	}
+
+	matrix := make([][]string, numSteps)
+	teamsAssignedPerStep := make([]map[string]bool, numSteps)
+	for step := range numSteps {
+		matrix[step] = make([]string, len(columns))
+		teamsAssignedPerStep[step] = make(map[string]bool)
+	}
+
+	// Keep the steps already reached, minus teams that have since been deleted
+	keptCells := 0
+	droppedCells := 0
+	for i, column := range columns {
+		if column.oldCol < 0 {
+			continue
+		}
+		for step := 0; step < column.freeze; step++ {
+			teamID := previous.Matrix[step][column.oldCol]
+			if teamID == "" {
+				continue
+			}
+			if !activeTeams[teamID] {
+				droppedCells++
+				continue
+			}
+			matrix[step][i] = teamID
+			teamsAssignedPerStep[step][teamID] = true
+			keptCells++
+		}
+	}
+
+	// Spread each group's remaining visits over its open steps, leaving the rest as rests
+	activeSteps := make([]map[int]bool, len(columns))
+	for i, column := range columns {
+		openSteps := []int{}
+		for step := column.freeze; step < numSteps; step++ {
+			openSteps = append(openSteps, step)
+		}
+		rng.Shuffle(len(openSteps), func(a, b int) {
+			openSteps[a], openSteps[b] = openSteps[b], openSteps[a]
+		})
+
+		activeSteps[i] = make(map[int]bool)
+		for _, step := range openSteps[:len(remaining[i])] {
+			activeSteps[i][step] = true
+		}
+	}
+
+	// Fill step by step so no team is visited by two groups at once. A visit that
+	// can't be placed on its planned step is retried on any open rest step.
+	replannedCells := 0
+	place := func(i int, step int) {
+		for k, teamID := range remaining[i] {
+			if teamsAssignedPerStep[step][teamID] {
+				continue
+			}
+			matrix[step][i] = teamID
+			teamsAssignedPerStep[step][teamID] = true
+			remaining[i] = slices.Delete(remaining[i], k, k+1)
+			replannedCells++
+			return
+		}
+	}
+
+	for step := range numSteps {
+		for i := range columns {
+			if activeSteps[i][step] {
+				place(i, step)
+			}
+		}
+	}
+
+	for i, column := range columns {
+		for step := column.freeze; step < numSteps && len(remaining[i]) > 0; step++ {
+			if matrix[step][i] == "" {
+				place(i, step)
+			}
+		}
+	}
+
+	// Whatever still doesn't fit gets extra steps at the end, so coverage is never lost
+	addedSteps := 0
+	for slices.ContainsFunc(remaining, func(teamIDs []string) bool { return len(teamIDs) > 0 }) {
+		matrix = append(matrix, make([]string, len(columns)))
+		teamsAssignedPerStep = append(teamsAssignedPerStep, make(map[string]bool))
+		for i := range columns {
+			place(i, numSteps)
+		}
+		numSteps++
+		addedSteps++
+	}
+
+	matrixObj := JudgeInitMatrix{
+		Steps:     numSteps,
+		Groups:    len(columns),
+		Teams:     len(teams),
+		Seed:      seed,
+		Conflicts: conflicts,
+		Matrix:    matrix,
+	}
+	matrixObj.computeMetrics()
+
+	if serr := saveJudgingPlan(judgeIDToGroupIdx, matrixObj, superuser.Username); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	// Skipped teams waiting for a retry are dropped once they are deleted, kept
+	// away from the judge's new group, or visited again by the rebuilt steps
+	droppedRetries := 0
+	for _, judge := range judges {
+		if judge.RetryTeam == "" && len(judge.RetryQueue) == 0 {
+			continue
+		}
+
+		i := judgeIDToGroupIdx[judge.ID]
+		planned := make(map[string]bool)
+		for step := columns[i].freeze; step < numSteps; step++ {
+			planned[matrix[step][i]] = true
+		}
+
+		drop := []string{}
+		for _, teamID := range append(slices.Clone(judge.RetryQueue), judge.RetryTeam) {
+			if teamID != "" && (!activeTeams[teamID] || blockedTeams[i][teamID] || planned[teamID]) {
+				drop = append(drop, teamID)
+			}
+		}
+		if len(drop) == 0 {
+			continue
+		}
+
+		if serr := judge.DropRetries(drop); serr != errmsg.EmptyStatusError {
+			return utils.StatusError(c, serr)
+		}
+		droppedRetries += len(drop)
+	}
+
+	addedGroups := 0
+	keptGroups := 0
+	closedGroups := 0
+	for _, column := range columns {
+		if column.oldCol < 0 {
+			addedGroups++
+		} else {
+			keptGroups++
+		}
+		if column.closed {
+			closedGroups++
+		}
+	}
+
+	response := JudgeReplanResponse{
+		Message:            "judging re-planned",
+		NumTeams:           len(teams),
+		NumJudges:          len(judges),
+		NumPairGroups:      len(columns),
+		NumSteps:           numSteps,
+		Seed:               seed,
+		AddedGroups:        addedGroups,
+		RemovedGroups:      previous.Groups - keptGroups,
+		ClosedGroups:       closedGroups,
+		KeptCells:          keptCells,
+		ReplannedCells:     replannedCells,
+		DroppedCells:       droppedCells,
+		AddedSteps:         addedSteps,
+		DroppedRetries:     droppedRetries,
+		Collisions:         matrixObj.Collisions,
+		Conflicts:          conflicts,
+		GavelScore:         matrixObj.GavelScore,
+		UniquePairs:        matrixObj.UniquePairs,
+		TotalPossiblePairs: matrixObj.TotalPairs,
+		AverageRedundancy:  matrixObj.AvgRedundancy,
+	}
+
+	events.Em.JudgingReplanned(superuser.Username, map[string]any{
+		"numSteps":       response.NumSteps,
+		"addedGroups":    response.AddedGroups,
+		"removedGroups":  response.RemovedGroups,
+		"closedGroups":   response.ClosedGroups,
+		"keptCells":      response.KeptCells,
+		"replannedCells": response.ReplannedCells,
+		"droppedCells":   response.DroppedCells,
+		"addedSteps":     response.AddedSteps,
+		"droppedRetries": response.DroppedRetries,
+	})
+
+	return c.JSON(response)
+}
+
+// loadJudgingPlan reads the current matrix and judge to group mapping
+func loadJudgingPlan() (matrixObj JudgeInitMatrix, judgeToGroupIdx map[string]int, serr errmsg.StatusError) {
+	matrixSetting := &models.Setting{Name: models.SettingJudgeInitMatrix}
+	serr = matrixSetting.Get()
+	if serr == errmsg.SettingNotFound {
+		return matrixObj, nil, errmsg.JudgingNotInitialized
+	}
+	if serr != errmsg.EmptyStatusError {
+		return matrixObj, nil, serr
+	}
+
+	if err := json.Unmarshal([]byte(matrixSetting.Value.(string)), &matrixObj); err != nil {
+		return matrixObj, nil, errmsg.InternalServerError(err)
+	}
+
+	judgeToGroupIndexSetting := &models.Setting{Name: models.SettingJudgeToGroupIndex}
+	serr = judgeToGroupIndexSetting.Get()
+	if serr == errmsg.SettingNotFound {
+		return matrixObj, nil, errmsg.JudgingNotInitialized
+	}
+	if serr != errmsg.EmptyStatusError {
+		return matrixObj, nil, serr
+	}
+
+	if err := json.Unmarshal([]byte(judgeToGroupIndexSetting.Value.(string)), &judgeToGroupIdx); err != nil {
+		return matrixObj, nil, errmsg.InternalServerError(err)
+	}
+
+	return matrixObj, judgeToGroupIdx, errmsg.EmptyStatusError
+}
+
+// matchReplanColumns lines the current pair groups up with the previous matrix
+// columns. A group takes over the column of its first judge that had one, unless
+// another group already claimed it; groups left without a column are new.
+func matchReplanColumns(groups []JudgePairGroup, oldGroupIdx map[string]int, oldGroups int) []replanColumn {
+	claimed := make(map[int]bool)
+	kept := []replanColumn{}
+	added := []replanColumn{}
+
+	for _, group := range groups {
+		column := replanColumn{group: group, oldCol: -1}
+		for _, judgeID := range group.JudgeIDs {
+			if oldCol, ok := oldGroupIdx[judgeID]; ok && oldCol < oldGroups && !claimed[oldCol] {
+				column.oldCol = oldCol
+				claimed[oldCol] = true
+				break
+			}
+		}
+
+		if column.oldCol >= 0 {
+			kept = append(kept, column)
+		} else {
+			added = append(added, column)
+		}
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].oldCol < kept[j].oldCol
+	})
+
+	return append(kept, added...)
+}
@@ -22,6 +22,41 @@ func Routes(r fiber.Router) {
 		computeRankingsHandler,
 	)
 
+	r.Get("/rank-stability",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		rankStabilityHandler,
+	)
+
+	r.Get("/leaderboard",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		getLeaderboardHandler,
+	)
+
+	r.Get("/dashboard",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		judgingDashboardHandler,
+	)
+
+	r.Get("/judge-outliers",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		judgeOutliersHandler,
+	)
+
+	r.Put("/judge-weight",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		setJudgeWeightHandler,
+	)
+
 	r.Get("/judges",
 		models.SuperUserMiddlewareBuilder([]string{
 			"admin",
@@ -36,6 +71,20 @@ func Routes(r fiber.Router) {
 		getFinalistsHandler,
 	)
 
+	r.Put("/finalists",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		updateFinalistsHandler,
+	)
+
+	r.Put("/finalists/lock",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		lockFinalistsHandler,
+	)
+
 	r.Get("/voting-results",
 		models.SuperUserMiddlewareBuilder([]string{
 			"admin",
@@ -43,6 +92,76 @@ func Routes(r fiber.Router) {
 		getVotingResultsHandler,
 	)
 
+	r.Get("/rubric",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		getRubricHandler,
+	)
+
+	r.Put("/rubric",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		updateRubricHandler,
+	)
+
+	r.Get("/rubric-rankings",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		rubricRankingsHandler,
+	)
+
+	r.Get("/skips",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		getSkipsHandler,
+	)
+
+	r.Get("/conflicts",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		getConflictsHandler,
+	)
+
+	r.Post("/conflicts",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		declareConflictHandler,
+	)
+
+	r.Delete("/conflicts",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		deleteConflictHandler,
+	)
+
+	r.Get("/notes",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		getJudgeNotesHandler,
+	)
+
+	r.Get("/judgments",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		getJudgmentsHandler,
+	)
+
+	r.Post("/judgment/invalidate",
+		models.SuperUserMiddlewareBuilder([]string{
+			"admin",
+		}),
+		invalidateJudgmentHandler,
+	)
+
 	judge := r.Group("/judge")
 
 	judge.Post("",
@@ -0,0 +1,112 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getRubricHandler returns the judging rubric.
+// @Summary Get judging rubric
+// @Description Returns the rubric criteria judges score each team on. A default rubric is returned until one is configured.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {object} models.Rubric
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/rubric [get]
+func getRubricHandler(c fiber.Ctx) error {
+	rubric, serr := models.GetRubric()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(rubric)
+}
+
+// updateRubricHandler replaces the judging rubric.
+// @Summary Update judging rubric
+// @Description Replaces the rubric criteria, weights and scales. Criterion IDs must be unique, weights positive and each scale must have min below max. Scores already submitted are kept; criteria that no longer exist are ignored in rankings.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body models.Rubric true "Rubric"
+// @Success 200 {object} models.Rubric
+// @Failure 400 {object} errmsg._JudgingInvalidRubric
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/rubric [put]
+func updateRubricHandler(c fiber.Ctx) error {
+	var rubric models.Rubric
+	if err := json.Unmarshal(c.Body(), &rubric); err != nil {
+		return utils.StatusError(c, errmsg.JudgingInvalidRubric)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	if serr := models.SaveRubric(rubric, superuser.Username); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	criteria := []string{}
+	for _, criterion := range rubric.Criteria {
+		criteria = append(criteria, criterion.ID)
+	}
+	events.Em.RubricUpdated(superuser.Username, criteria)
+
+	return c.JSON(rubric)
+}
+
+// rubricRankingsHandler ranks teams on each rubric criterion.
+// @Summary Get rubric rankings
+// @Description Ranks teams per rubric criterion and by weighted overall rubric score. Scores are z-normalized per judge and criterion to cancel out lenient or harsh judges. The live pairwise leaderboard is included for comparison.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {object} RubricRankingsResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/rubric-rankings [get]
+func rubricRankingsHandler(c fiber.Ctx) error {
+	rubric, serr := models.GetRubric()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	scores, serr := models.GetAllRubricScores()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	entries := make([]utils.RubricEntry, 0, len(scores))
+	for _, score := range scores {
+		entry := utils.RubricEntry{
+			JudgeID: score.JudgeID,
+			TeamID:  score.TeamID,
+			Scores:  map[string]float64{},
+		}
+		for criterionID, value := range score.Scores {
+			entry.Scores[criterionID] = float64(value)
+		}
+		entries = append(entries, entry)
+	}
+
+	leaderboard, serr := models.GetLeaderboard()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(RubricRankingsResponse{
+		Rubric:         rubric,
+		RubricRankings: utils.RankRubric(entries, rubric.Weights()),
+		ScoreCount:     len(scores),
+		Pairwise:       leaderboard.Teams,
+	})
+}
@@ -0,0 +1,31 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/models"
+	"backend/internal/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getSkipsHandler reports the teams judges had to skip.
+// @Summary Get judge skip report
+// @Description Returns every recorded skip along with a per-team summary, teams most often absent first.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {object} SkipReportResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/skips [get]
+func getSkipsHandler(c fiber.Ctx) error {
+	skips, serr := models.GetJudgeSkips()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(SkipReportResponse{
+		Teams: models.SummarizeSkipsByTeam(skips),
+		Skips: skips,
+	})
+}
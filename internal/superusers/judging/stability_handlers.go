@@ -0,0 +1,65 @@
+package judging
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// rankStabilityHandler estimates how stable the CrowdBT ranking is by bootstrapping judgments.
+// @Summary Analyze ranking stability
+// @Description Refits the Crowd Bradley-Terry scorer on judgments resampled with replacement and reports per-team rank distributions, 95% rank intervals, the probability of landing in the top N, and pairwise "A ranked above B" probabilities.
+// @Tags Superusers Judging
+// @Security SuperUserAuth
+// @Produce json
+// @Param samples query int false "Number of bootstrap resamples (1-1000, default 100)"
+// @Param topN query int false "Size of the top group to report membership probability for (default 5)"
+// @Param seed query int false "Seed for the resampling (default 1)"
+// @Success 200 {object} RankStabilityResponse
+// @Failure 400 {object} errmsg._JudgingInvalidBootstrapOptions
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/judging/rank-stability [get]
+func rankStabilityHandler(c fiber.Ctx) error {
+	options := utils.DefaultBootstrapOptions()
+
+	if raw := c.Query("samples"); raw != "" {
+		samples, err := strconv.Atoi(raw)
+		if err != nil {
+			return utils.StatusError(c, errmsg.JudgingInvalidBootstrapOptions)
+		}
+		options.Samples = samples
+	}
+	if raw := c.Query("topN"); raw != "" {
+		topN, err := strconv.Atoi(raw)
+		if err != nil {
+			return utils.StatusError(c, errmsg.JudgingInvalidBootstrapOptions)
+		}
+		options.TopN = topN
+	}
+	if raw := c.Query("seed"); raw != "" {
+		seed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return utils.StatusError(c, errmsg.JudgingInvalidBootstrapOptions)
+		}
+		options.Seed = seed
+	}
+
+	if err := options.Validate(); err != nil {
+		return utils.StatusError(c, errmsg.JudgingInvalidBootstrapOptions)
+	}
+
+	judgments, scorerJudgments, serr := loadScorerJudgments()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	result := utils.BootstrapRankings(scorerJudgments, options)
+
+	return c.JSON(RankStabilityResponse{
+		BootstrapResult: result,
+		JudgmentCount:   len(judgments),
+	})
+}
@@ -1,6 +1,10 @@
 package judging
 
-import "time"
+import (
+	"backend/internal/models"
+	"backend/internal/utils"
+	"time"
+)
 
 // JudgeCreateRequest contains the details for creating a new judge.
 type JudgeCreateRequest struct {
@@ -13,6 +17,12 @@ type JudgeDeleteRequest struct {
 	ID string `json:"id" example:"abc123"`
 }
 
+// JudgmentInvalidateRequest identifies the judgment to discard and why.
+type JudgmentInvalidateRequest struct {
+	ID     string `json:"id" example:"abc123"`
+	Reason string `json:"reason" example:"judge reported tapping the wrong team"`
+}
+
 // JudgeConnectRequest contains the judge ID to request a connect token.
 type JudgeConnectRequest struct {
 	ID string `json:"id" example:"judge_001"`
@@ -32,9 +42,25 @@ type JudgeProgressResponse struct {
 	NextTeamTime time.Time `json:"nextTeamTime" example:"2024-01-15T14:30:00Z"`
 }
 
-// GetFinalistsResponse returns the current finalists with full team details.
-type GetFinalistsResponse struct {
-	Finalists []interface{} `json:"finalists" description:"Array of full team objects"`
+// FinalistsResponse returns the finalist list with full team details.
+type FinalistsResponse struct {
+	Finalists []models.Team `json:"finalists" description:"Full team objects in finalist order"`
+	Count     int           `json:"count" example:"5"`
+	Locked    bool          `json:"locked" example:"false"`
+	Source    string        `json:"source" example:"computed" enums:"computed,manual"`
+	UpdatedAt time.Time     `json:"updatedAt" example:"2024-01-15T14:30:00Z"`
+	UpdatedBy string        `json:"updatedBy" example:"admin"`
+}
+
+// FinalistsUpdateRequest overrides the finalists. Fields left out are kept.
+type FinalistsUpdateRequest struct {
+	Count *int     `json:"count" example:"3"`
+	Teams []string `json:"teams" example:"XYZ789,ABC123,DEF456"`
+}
+
+// FinalistsLockRequest locks or unlocks the finalists.
+type FinalistsLockRequest struct {
+	Locked bool `json:"locked" example:"true"`
 }
 
 // VotingResultItem represents a single finalist with its vote count.
@@ -76,4 +102,126 @@ type ComputeRankingsResponse struct {
 	TeamUncertainty  map[string]float64            `json:"teamUncertainty" description:"Team skill uncertainty (sigma_sq)"`
 	JudgeReliability map[string]map[string]float64 `json:"judgeReliability" description:"Judge reliability parameters (alpha, beta)"`
 	JudgmentCount    int                           `json:"judgmentCount" description:"Total number of judgments processed"`
+	Finalists        []models.Team                 `json:"finalists" description:"Finalists in order; the top teams unless the list is locked"`
+	FinalistsLocked  bool                          `json:"finalistsLocked" description:"Whether the finalists were kept because they are locked"`
+	Diagnostics      utils.ScorerDiagnostics       `json:"diagnostics" description:"Iterations, final delta and per-iteration log-likelihood of the fit"`
+}
+
+// RankStabilityResponse returns bootstrap rank distributions for every ranked team.
+type RankStabilityResponse struct {
+	utils.BootstrapResult
+	JudgmentCount int `json:"judgmentCount" description:"Total number of judgments resampled"`
+}
+
+// RubricRankingsResponse returns per-criterion and overall rubric rankings next to the pairwise standings.
+type RubricRankingsResponse struct {
+	utils.RubricRankings
+	Rubric     models.Rubric            `json:"rubric"`
+	ScoreCount int                      `json:"scoreCount" description:"Number of rubric score sheets ranked"`
+	Pairwise   []models.LeaderboardTeam `json:"pairwise" description:"Live Crowd-BT standings"`
+}
+
+// SkipReportResponse returns all judge skips and a per-team summary.
+type SkipReportResponse struct {
+	Teams []models.TeamSkipSummary `json:"teams" description:"Per-team skip counts, most often absent first"`
+	Skips []models.JudgeSkip       `json:"skips" description:"Every recorded skip, oldest first"`
+}
+
+// JudgeConflictRequest names the judge and either a team or a university
+type JudgeConflictRequest struct {
+	JudgeID    string `json:"judgeID" example:"abc123"`
+	TeamID     string `json:"teamID,omitempty" example:"team_001"`
+	University string `json:"university,omitempty" example:"Example University"`
+	Reason     string `json:"reason" example:"sponsor contact"`
+}
+
+type JudgeConflictDeleteRequest struct {
+	ID string `json:"id" example:"abc123"`
+}
+
+// JudgeReplanResponse summarizes an incremental re-plan of the judging matrix
+type JudgeReplanResponse struct {
+	Message            string  `json:"message" example:"judging re-planned"`
+	NumTeams           int     `json:"numTeams" example:"17"`
+	NumJudges          int     `json:"numJudges" example:"15"`
+	NumPairGroups      int     `json:"numPairGroups" example:"11"`
+	NumSteps           int     `json:"numSteps" example:"17"`
+	Seed               int64   `json:"seed" example:"4242"`
+	AddedGroups        int     `json:"addedGroups" example:"1"`
+	RemovedGroups      int     `json:"removedGroups" example:"0"`
+	ClosedGroups       int     `json:"closedGroups" example:"2"`     // groups whose judges had all finished, which get no new visits
+	KeptCells          int     `json:"keptCells" example:"40"`       // assignments from steps already reached
+	ReplannedCells     int     `json:"replannedCells" example:"120"` // assignments in the rebuilt steps
+	DroppedCells       int     `json:"droppedCells" example:"2"`     // kept assignments removed because the team was deleted
+	AddedSteps         int     `json:"addedSteps" example:"0"`       // steps appended because visits could not be fitted without a collision
+	DroppedRetries     int     `json:"droppedRetries" example:"1"`   // pending retries dropped because the team was deleted, is in conflict, or is visited again
+	Collisions         int     `json:"collisions" example:"0"`
+	Conflicts          int     `json:"conflicts" example:"1"`
+	GavelScore         float64 `json:"gavelScore" example:"72.5"`
+	UniquePairs        int     `json:"uniquePairs" example:"99"`
+	TotalPossiblePairs int     `json:"totalPossiblePairs" example:"136"`
+	AverageRedundancy  float64 `json:"averageRedundancy" example:"1.4"`
+}
+
+// JudgingDashboardResponse summarizes judge progress and team coverage.
+type JudgingDashboardResponse struct {
+	GeneratedAt  time.Time             `json:"generatedAt" example:"2024-01-15T14:30:00Z"`
+	TotalSteps   int                   `json:"totalSteps" example:"17"`
+	WaitMinutes  int                   `json:"waitMinutes" example:"5"`
+	StatusCounts map[string]int        `json:"statusCounts" description:"Number of judges per status (not_started, judging, resting, stuck, finished)"`
+	Judges       []JudgeDashboardEntry `json:"judges"`
+	Teams        []TeamCoverageEntry   `json:"teams" description:"Least judged teams first"`
+}
+
+// JudgeDashboardEntry is one judge's progress through the matrix.
+type JudgeDashboardEntry struct {
+	JudgeID                  string     `json:"judgeID" example:"abc123"`
+	Name                     string     `json:"name" example:"Judge Alice"`
+	Pair                     string     `json:"pair" example:"pair_1"`
+	GroupIdx                 int        `json:"groupIdx" example:"3" description:"Matrix column, or -1 for a judge added after init"`
+	Status                   string     `json:"status" example:"judging" enums:"not_started,judging,resting,stuck,finished"`
+	CurrentStep              int        `json:"currentStep" example:"5"`
+	TotalSteps               int        `json:"totalSteps" example:"17"`
+	CurrentTeamID            string     `json:"currentTeamID" example:"XYZ789"`
+	ScheduledVisits          int        `json:"scheduledVisits" example:"15" description:"Steps in the judge's column that send them to a team"`
+	RetryQueue               int        `json:"retryQueue" example:"1"`
+	JudgmentsMade            int        `json:"judgmentsMade" example:"4"`
+	LastJudgmentAt           *time.Time `json:"lastJudgmentAt" example:"2024-01-15T14:25:00Z"`
+	SecondsSinceLastJudgment int64      `json:"secondsSinceLastJudgment" example:"300"`
+	NextTeamTime             time.Time  `json:"nextTeamTime" example:"2024-01-15T14:30:00Z"`
+	Alpha                    float64    `json:"alpha" example:"10.4"`
+	Beta                     float64    `json:"beta" example:"1.1"`
+	Reliability              float64    `json:"reliability" example:"0.9" description:"alpha / (alpha + beta)"`
+	ProjectedFinish          *time.Time `json:"projectedFinish" example:"2024-01-15T15:40:00Z"`
+}
+
+// TeamCoverageEntry is how well a team has been covered by judgments so far.
+type TeamCoverageEntry struct {
+	TeamID          string `json:"teamID" example:"XYZ789"`
+	Name            string `json:"name" example:"Team Rocket"`
+	TimesJudged     int    `json:"timesJudged" example:"6"`
+	Wins            int    `json:"wins" example:"4"`
+	UniqueOpponents int    `json:"uniqueOpponents" example:"5"`
+	UniqueJudges    int    `json:"uniqueJudges" example:"3"`
+	ScheduledVisits int    `json:"scheduledVisits" example:"8" description:"Visits to the team in the judging matrix"`
+}
+
+// JudgeOutliersResponse lists every judge who made a counted judgment, with any flags raised.
+type JudgeOutliersResponse struct {
+	Flagged int                       `json:"flagged" example:"1"`
+	Options utils.JudgeOutlierOptions `json:"options"`
+	Judges  []JudgeOutlierEntry       `json:"judges"`
+}
+
+// JudgeOutlierEntry is one judge's outlier analysis along with their current weight.
+type JudgeOutlierEntry struct {
+	utils.JudgeOutlierReport
+	Name   string  `json:"name" example:"Judge Alice"`
+	Weight float64 `json:"weight" example:"1"`
+}
+
+// JudgeWeightRequest sets how much a judge's judgments count.
+type JudgeWeightRequest struct {
+	JudgeID string   `json:"judgeID" example:"abc123"`
+	Weight  *float64 `json:"weight" example:"0.5" description:"0 excludes the judge, 1 restores the default"`
 }
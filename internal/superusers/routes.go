@@ -6,9 +6,11 @@ import (
 	"backend/internal/superusers/badges"
 	"backend/internal/superusers/flags"
 	"backend/internal/superusers/flagstages"
+	"backend/internal/superusers/history"
 	"backend/internal/superusers/judging"
 	"backend/internal/superusers/participants"
 	"backend/internal/superusers/staff"
+	"backend/internal/superusers/voting"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -30,9 +32,11 @@ func Routes(r fiber.Router) {
 
 	flags.Routes(r.Group("/flags"))
 	flagstages.Routes(r.Group("/flagstages"))
+	history.Routes(r.Group("/history"))
 	badges.Routes(r.Group("/badges"))
 	judging.Routes(r.Group("/judging"))
 	participants.Routes(r.Group("/participants"))
+	voting.Routes(r.Group("/voting"))
 
 	staff.Routes(r.Group("/staff"))
 }
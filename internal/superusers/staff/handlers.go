@@ -6,6 +6,7 @@ import (
 	"backend/internal/models"
 	"backend/internal/utils"
 	"encoding/json"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/bson"
@@ -21,6 +22,7 @@ import (
 // @Success 200 {object} StaffRegisterResponse
 // @Failure 401 {object} errmsg._SuperUserNoToken
 // @Failure 404 {object} errmsg._AccountNotFound
+// @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/staff/register [post]
 func staffRegisterHandler(c fiber.Ctx) error {
 	var su models.SuperUser
@@ -44,6 +46,10 @@ func staffRegisterHandler(c fiber.Ctx) error {
 
 	events.Em.StaffRegister(su.Username, account.ID)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"account": account,
 		"pile":    utils.PileForAccount(account.ID, utils.BadgePileSalt()),
@@ -60,6 +66,7 @@ func staffRegisterHandler(c fiber.Ctx) error {
 // @Success 200 {object} models.Account
 // @Failure 401 {object} errmsg._SuperUserNoToken
 // @Failure 404 {object} errmsg._AccountNotFound
+// @Failure 500 {object} errmsg._InternalServerError
 // @Router /superusers/staff/account [get]
 func staffAccountGetHandler(c fiber.Ctx) error {
 	account := models.Account{ID: c.Query("accountID")}
@@ -70,12 +77,16 @@ func staffAccountGetHandler(c fiber.Ctx) error {
 		)
 	}
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(account)
 }
 
 // tagsGetHandler fetches a stored account by the linked tag ID.
 // @Summary Fetch a tag's linked account
-// @Description Pulls tag details from cache or Mongo and then fetches the linked account.
+// @Description Pulls tag details from cache or Mongo and then fetches the linked account. The scan is recorded on the account, which voting sessions can require to have happened recently.
 // @Tags Superusers Staff
 // @Security SuperUserAuth
 // @Produce json
@@ -102,6 +113,15 @@ func staffTagGetHandler(c fiber.Ctx) error {
 		return utils.StatusError(c, errmsg.AccountNotFound)
 	}
 
+	err = account.MarkScanned(time.Now())
+	if err != nil {
+		return utils.StatusError(c, errmsg.InternalServerError(err))
+	}
+
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(account)
 }
 
@@ -0,0 +1,149 @@
+package voting
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getVoteAnomaliesHandler looks for bursts of votes in a voting session.
+// @Summary Detect vote anomalies
+// @Description Splits the session into the buckets vote times are kept at and flags every bucket in which a finalist received at least minVotes first choices and burstFactor times their mean over the session's other buckets. Each bucket also counts the account registrations, logins and failed logins recorded in it, and a burst is marked as an activity spike when registrations and logins in its bucket and the one before reach activityFactor times their usual level. Votes are never matched to accounts; only counts are compared. Votes already voided are left out, and the voids are listed.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Produce json
+// @Param sessionID query string true "Voting session to analyze"
+// @Param minVotes query int false "Votes a team needs in a bucket before it can be flagged (default 5)"
+// @Param burstFactor query number false "Flag a team getting this many times its usual votes per bucket (default 3)"
+// @Param activityFactor query number false "Account activity this many times its usual level is a spike (default 3)"
+// @Success 200 {object} VoteAnomaliesResponse
+// @Failure 400 {object} errmsg._VotingInvalidAnomalyOptions
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/anomalies [get]
+func getVoteAnomaliesHandler(c fiber.Ctx) error {
+	options := utils.DefaultVoteAnomalyOptions()
+
+	if raw := c.Query("minVotes"); raw != "" {
+		minVotes, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return utils.StatusError(c, errmsg.VotingInvalidAnomalyOptions)
+		}
+		options.MinVotes = minVotes
+	}
+
+	thresholds := map[string]*float64{
+		"burstFactor":    &options.BurstFactor,
+		"activityFactor": &options.ActivityFactor,
+	}
+	for name, threshold := range thresholds {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return utils.StatusError(c, errmsg.VotingInvalidAnomalyOptions)
+		}
+		*threshold = value
+	}
+
+	if err := options.Validate(); err != nil {
+		return utils.StatusError(c, errmsg.VotingInvalidAnomalyOptions)
+	}
+
+	session := models.VotingSession{ID: c.Query("sessionID")}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	// Only the part of the session that has happened so far is analyzed
+	end := session.ClosesAt
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+	buckets := utils.VoteBuckets(session.OpensAt, end, models.VoteBucket)
+
+	counts, serr := models.GetVoteBucketCounts(session.ID)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	var activity []utils.AccountActivityBucket
+	if len(buckets) > 0 {
+		activity, serr = models.GetAccountActivity(buckets[0], buckets[len(buckets)-1].Add(models.VoteBucket), models.VoteBucket)
+		if serr != errmsg.EmptyStatusError {
+			return utils.StatusError(c, serr)
+		}
+	}
+
+	voids, serr := models.GetVoteVoids(session.ID)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	report := utils.AnalyzeVoteAnomalies(buckets, counts, activity, options)
+
+	return c.JSON(VoteAnomaliesResponse{
+		SessionID:     session.ID,
+		BucketMinutes: int(models.VoteBucket / time.Minute),
+		Options:       options,
+		Buckets:       report.Buckets,
+		Bursts:        report.Bursts,
+		Voids:         voids,
+	})
+}
+
+// voidVotesHandler voids the votes cast in one bucket of a voting session.
+// @Summary Void a bucket of votes
+// @Description Stops counting the votes cast in one bucket of a voting session, for one finalist or for all of them, and records who voided them and why. Votes can't be traced to accounts, so a bucket is the finest unit that can be voided. The votes are kept and marked void; the results and live tally are recounted without them. Votes cast into the bucket after it was voided still count.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body VoteVoidRequest true "Bucket to void and the justification"
+// @Success 200 {object} models.VoteVoid
+// @Failure 400 {object} errmsg._VoteVoidInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._VoteVoidNoVotes
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/voids [post]
+func voidVotesHandler(c fiber.Ctx) error {
+	var body VoteVoidRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.VoteVoidInvalid)
+	}
+
+	session := models.VotingSession{ID: body.SessionID}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	void := models.VoteVoid{
+		SessionID:     session.ID,
+		Bucket:        body.Bucket,
+		TeamID:        body.TeamID,
+		Justification: body.Justification,
+		CreatedBy:     superuser.Username,
+	}
+	if serr := void.Validate(session); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+	if serr := void.Create(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.VotesVoided(superuser.Username, session.ID, void.ID, void.Bucket, void.TeamID, void.Votes, void.Justification)
+
+	return c.JSON(void)
+}
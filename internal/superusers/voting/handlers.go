@@ -0,0 +1,228 @@
+package voting
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func sessionResponse(session models.VotingSession, now time.Time) VotingSessionResponse {
+	return VotingSessionResponse{
+		ID:                  session.ID,
+		Name:                session.Name,
+		OpensAt:             session.OpensAt,
+		ClosesAt:            session.ClosesAt,
+		Finalists:           session.Finalists,
+		Mode:                session.Mode,
+		Eligibility:         session.Eligibility,
+		HideTallyUntilClose: session.HideTallyUntilClose,
+		CreatedBy:           session.CreatedBy,
+		CreatedAt:           session.CreatedAt,
+		Status:              session.Status(now),
+	}
+}
+
+// getVotingSessionsHandler lists the voting sessions.
+// @Summary List voting sessions
+// @Description Returns every voting session in opening order, each with whether it is scheduled, open or closed.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Produce json
+// @Success 200 {array} VotingSessionResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/sessions [get]
+func getVotingSessionsHandler(c fiber.Ctx) error {
+	sessions, serr := models.GetVotingSessions()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	now := time.Now()
+	response := make([]VotingSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, sessionResponse(session, now))
+	}
+
+	return c.JSON(response)
+}
+
+// createVotingSessionHandler schedules a voting session.
+// @Summary Create a voting session
+// @Description Schedules a round of audience voting between opensAt and closesAt. The finalists are snapshotted when the session is created, from the request or else from the current finalists, so later finalist changes don't affect it. Each session keeps its own votes, cast as a single choice, a ranking (tallied by instant runoff and Borda count) or a set of approved teams. The first session ever created takes over the votes cast before there were sessions, so those voters can't vote again in it.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body VotingSessionCreateRequest true "Voting session"
+// @Success 200 {object} VotingSessionResponse
+// @Failure 400 {object} errmsg._VotingSessionInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._TeamNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/sessions [post]
+func createVotingSessionHandler(c fiber.Ctx) error {
+	var body VotingSessionCreateRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.VotingSessionInvalid)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	session := models.VotingSession{
+		Name:                body.Name,
+		OpensAt:             body.OpensAt,
+		ClosesAt:            body.ClosesAt,
+		Finalists:           body.Finalists,
+		Mode:                body.Mode,
+		Eligibility:         body.Eligibility,
+		HideTallyUntilClose: body.HideTallyUntilClose,
+		CreatedBy:           superuser.Username,
+	}
+
+	if session.Finalists == nil {
+		finalists, serr := models.GetFinalists()
+		if serr != errmsg.EmptyStatusError {
+			return utils.StatusError(c, serr)
+		}
+		session.Finalists = finalists.Teams
+	}
+
+	if serr := session.Create(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	events.Em.VotingSessionCreated(superuser.Username, session.ID, session.Name, session.Mode, session.OpensAt, session.ClosesAt, session.Finalists)
+
+	return c.JSON(sessionResponse(session, time.Now()))
+}
+
+// updateVotingSessionHandler changes a voting session.
+// @Summary Update a voting session
+// @Description Renames or reschedules a voting session, or changes its finalists, voting mode and eligibility requirements. Once the session has opened only its name, closing time and whether the live tally is hidden can change, so it can be extended or closed early.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Accept json
+// @Produce json
+// @Param payload body VotingSessionUpdateRequest true "Session ID and the fields to change"
+// @Success 200 {object} VotingSessionResponse
+// @Failure 400 {object} errmsg._VotingSessionInvalid
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 409 {object} errmsg._VotingSessionStarted
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/sessions [put]
+func updateVotingSessionHandler(c fiber.Ctx) error {
+	var body VotingSessionUpdateRequest
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return utils.StatusError(c, errmsg.VotingSessionInvalid)
+	}
+
+	session := models.VotingSession{ID: body.ID}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	now := time.Now()
+	if session.Status(now) != models.VotingSessionScheduled {
+		started := (body.OpensAt != nil && !body.OpensAt.Equal(session.OpensAt)) ||
+			(body.Finalists != nil && !slices.Equal(body.Finalists, session.Finalists)) ||
+			(body.Mode != nil && *body.Mode != session.Mode) ||
+			(body.Eligibility != nil && *body.Eligibility != session.Eligibility)
+		if started {
+			return utils.StatusError(c, errmsg.VotingSessionStarted)
+		}
+	}
+
+	if body.Name != nil {
+		session.Name = *body.Name
+	}
+	if body.OpensAt != nil {
+		session.OpensAt = *body.OpensAt
+	}
+	if body.ClosesAt != nil {
+		session.ClosesAt = *body.ClosesAt
+	}
+	if body.Finalists != nil {
+		session.Finalists = body.Finalists
+	}
+	if body.Mode != nil {
+		session.Mode = *body.Mode
+	}
+	if body.Eligibility != nil {
+		session.Eligibility = *body.Eligibility
+	}
+	if body.HideTallyUntilClose != nil {
+		session.HideTallyUntilClose = *body.HideTallyUntilClose
+	}
+
+	if serr := session.Update(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	events.Em.VotingSessionUpdated(superuser.Username, session.ID, session.OpensAt, session.ClosesAt, session.Finalists)
+
+	return c.JSON(sessionResponse(session, now))
+}
+
+// getVotingResultsHandler tallies a voting session.
+// @Summary Get voting session results
+// @Description Tallies a voting session under its voting mode. Single-choice sessions count votes and approval sessions count approvals. Ranked sessions report first preferences and Borda points for every finalist, and the instant-runoff count round by round: the ballots each remaining team holds, the exhausted ballots, and which team was eliminated.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Produce json
+// @Param sessionID query string true "Voting session to tally"
+// @Success 200 {object} VotingResultsResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/results [get]
+func getVotingResultsHandler(c fiber.Ctx) error {
+	session := models.VotingSession{ID: c.Query("sessionID")}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	tally, serr := models.TallyVotingSession(session)
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teams, serr := session.LoadFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	response := VotingResultsResponse{
+		SessionID: session.ID,
+		Name:      session.Name,
+		Mode:      session.Mode,
+		Status:    session.Status(time.Now()),
+		Ballots:   tally.Ballots,
+		Results:   make([]VotingResultEntry, 0, len(teams)),
+		Rounds:    tally.Rounds,
+	}
+	for _, team := range teams {
+		response.Results = append(response.Results, VotingResultEntry{
+			Team:        team,
+			Count:       tally.Counts[team.ID],
+			BordaPoints: tally.BordaPoints[team.ID],
+		})
+		if team.ID == tally.Winner {
+			winner := team
+			response.Winner = &winner
+		}
+	}
+
+	return c.JSON(response)
+}
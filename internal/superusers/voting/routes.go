@@ -0,0 +1,46 @@
+package voting
+
+import (
+	"backend/internal/models"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func Routes(r fiber.Router) {
+	r.Get("/sessions",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		getVotingSessionsHandler,
+	)
+	r.Post("/sessions",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		createVotingSessionHandler,
+	)
+	r.Put("/sessions",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		updateVotingSessionHandler,
+	)
+	r.Get("/results",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		getVotingResultsHandler,
+	)
+	r.Get("/tally",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		getVotingTallyHandler,
+	)
+	r.Post("/tally/stream-token",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		issueTallyStreamTokenHandler,
+	)
+	r.Get("/tally/stream",
+		models.TallyStreamMiddlewareBuilder([]string{"admin"}),
+		streamVotingTallyHandler,
+	)
+	r.Get("/anomalies",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		getVoteAnomaliesHandler,
+	)
+	r.Post("/voids",
+		models.SuperUserMiddlewareBuilder([]string{"admin"}),
+		voidVotesHandler,
+	)
+}
@@ -0,0 +1,108 @@
+package voting
+
+import (
+	"backend/internal/models"
+	"backend/internal/utils"
+	"time"
+)
+
+// VotingSessionCreateRequest schedules a voting round.
+type VotingSessionCreateRequest struct {
+	Name                string                   `json:"name" example:"People's Choice"`
+	OpensAt             time.Time                `json:"opensAt" example:"2026-10-19T18:00:00Z"`
+	ClosesAt            time.Time                `json:"closesAt" example:"2026-10-19T18:30:00Z"`
+	Finalists           []string                 `json:"finalists" description:"Ordered team IDs; the current finalists are snapshotted when left out"`
+	Mode                string                   `json:"mode" example:"ranked" description:"single, ranked or approval; defaults to single"`
+	Eligibility         models.VotingEligibility `json:"eligibility" description:"Requirements to vote; anyone may vote when left out"`
+	HideTallyUntilClose bool                     `json:"hideTallyUntilClose" description:"Only show shares, not exact counts, on the live tally until the session closes"`
+}
+
+// VotingSessionUpdateRequest changes a voting round. Fields left out keep their value.
+type VotingSessionUpdateRequest struct {
+	ID                  string                    `json:"id" example:"abc123"`
+	Name                *string                   `json:"name" example:"Semifinal"`
+	OpensAt             *time.Time                `json:"opensAt" example:"2026-10-19T18:00:00Z"`
+	ClosesAt            *time.Time                `json:"closesAt" example:"2026-10-19T18:45:00Z"`
+	Finalists           []string                  `json:"finalists" description:"Ordered team IDs; only before the session opens"`
+	Mode                *string                   `json:"mode" example:"approval" description:"Only before the session opens"`
+	Eligibility         *models.VotingEligibility `json:"eligibility" description:"Only before the session opens"`
+	HideTallyUntilClose *bool                     `json:"hideTallyUntilClose"`
+}
+
+// VotingSessionResponse is a voting round along with its state at the time of the request.
+type VotingSessionResponse struct {
+	ID                  string                   `json:"id"`
+	Name                string                   `json:"name"`
+	OpensAt             time.Time                `json:"opensAt"`
+	ClosesAt            time.Time                `json:"closesAt"`
+	Finalists           []string                 `json:"finalists"`
+	Mode                string                   `json:"mode"`
+	Eligibility         models.VotingEligibility `json:"eligibility"`
+	HideTallyUntilClose bool                     `json:"hideTallyUntilClose"`
+	CreatedBy           string                   `json:"createdBy"`
+	CreatedAt           time.Time                `json:"createdAt"`
+	Status              string                   `json:"status" example:"open" description:"scheduled, open or closed"`
+}
+
+// VotingResultsResponse is a voting session's tally. Rounds and Borda points are only given for ranked sessions.
+type VotingResultsResponse struct {
+	SessionID string               `json:"sessionID"`
+	Name      string               `json:"name"`
+	Mode      string               `json:"mode" example:"ranked"`
+	Status    string               `json:"status" example:"closed"`
+	Ballots   int64                `json:"ballots" example:"120"`
+	Results   []VotingResultEntry  `json:"results" description:"Finalists in session order"`
+	Rounds    []models.RunoffRound `json:"rounds" description:"Instant-runoff rounds, each with the ballots held by every remaining team and the team eliminated"`
+	Winner    *models.Team         `json:"winner" description:"Instant-runoff winner for ranked sessions, otherwise the team with the most votes or approvals; null without votes"`
+}
+
+// VotingResultEntry is one finalist's share of a voting session's tally.
+type VotingResultEntry struct {
+	Team        models.Team `json:"team"`
+	Count       int64       `json:"count" example:"42" description:"Votes, first preferences in ranked sessions, or approvals in approval sessions"`
+	BordaPoints int64       `json:"bordaPoints" example:"310"`
+}
+
+// TallyStreamTokenResponse holds a token that opens one session's tally stream.
+type TallyStreamTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2026-10-19T18:02:00Z"`
+}
+
+// VotingTallyUpdate is a snapshot of a voting session's live tally, as shown on the stage screen.
+type VotingTallyUpdate struct {
+	SessionID string            `json:"sessionID"`
+	Name      string            `json:"name"`
+	Mode      string            `json:"mode" example:"single"`
+	Status    string            `json:"status" example:"open"`
+	ClosesAt  time.Time         `json:"closesAt"`
+	Hidden    bool              `json:"hidden" description:"Exact counts are withheld until the session closes"`
+	Ballots   *int64            `json:"ballots,omitempty" example:"120" description:"Left out while hidden"`
+	Teams     []VotingTallyTeam `json:"teams" description:"Finalists in session order"`
+}
+
+// VotingTallyTeam is one finalist's standing on the live tally.
+type VotingTallyTeam struct {
+	TeamID string  `json:"teamID"`
+	Name   string  `json:"name"`
+	Count  *int64  `json:"count,omitempty" example:"42" description:"Votes, first preferences in ranked sessions, or approvals in approval sessions; left out while hidden"`
+	Share  float64 `json:"share" example:"0.35" description:"Share of all counted votes; rounded to whole percents while hidden"`
+}
+
+// VoteAnomaliesResponse is a voting session's timeline, bucket by bucket, with the bursts found in it
+type VoteAnomaliesResponse struct {
+	SessionID     string                    `json:"sessionID"`
+	BucketMinutes int                       `json:"bucketMinutes" example:"5"`
+	Options       utils.VoteAnomalyOptions  `json:"options"`
+	Buckets       []utils.VoteBucketSummary `json:"buckets" description:"Counted votes and account activity per bucket, from the session's opening until now or its close"`
+	Bursts        []utils.VoteBurst         `json:"bursts"`
+	Voids         []models.VoteVoid         `json:"voids" description:"Buckets already voided, oldest first"`
+}
+
+// VoteVoidRequest voids the votes in one bucket of a voting session
+type VoteVoidRequest struct {
+	SessionID     string    `json:"sessionID" example:"abc123"`
+	Bucket        time.Time `json:"bucket" example:"2026-10-19T18:15:00Z" description:"Start of the bucket, as reported by GET /superusers/voting/anomalies"`
+	TeamID        string    `json:"teamID" description:"Only void the votes for this finalist; every finalist's when left out"`
+	Justification string    `json:"justification" example:"30 votes for one team right after 25 accounts were registered"`
+}
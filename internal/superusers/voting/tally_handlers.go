@@ -0,0 +1,215 @@
+package voting
+
+import (
+	"backend/internal/errmsg"
+	"backend/internal/events"
+	"backend/internal/models"
+	"backend/internal/utils"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// tallyStreamInterval is how often the stream checks the counters for changes
+var tallyStreamInterval = time.Second
+
+// tallyStreamKeepAlive is how long the stream may stay silent before a comment
+// keeps proxies from closing it
+var tallyStreamKeepAlive = 15 * time.Second
+
+// liveTally reads the session's counters into the stage screen's view of them,
+// withholding exact numbers while the session hides its tally
+func liveTally(session models.VotingSession, teams []models.Team, now time.Time) (VotingTallyUpdate, errmsg.StatusError) {
+	counts, err := models.GetVoteCounts(session.ID)
+	if err != nil {
+		return VotingTallyUpdate{}, errmsg.InternalServerError(err)
+	}
+	byTeam := counts.ForMode(session.Mode)
+
+	status := session.Status(now)
+	update := VotingTallyUpdate{
+		SessionID: session.ID,
+		Name:      session.Name,
+		Mode:      session.Mode,
+		Status:    status,
+		ClosesAt:  session.ClosesAt,
+		Hidden:    session.HideTallyUntilClose && status != models.VotingSessionClosed,
+		Teams:     make([]VotingTallyTeam, 0, len(teams)),
+	}
+
+	total := int64(0)
+	for _, team := range teams {
+		total += byTeam[team.ID]
+	}
+
+	for _, team := range teams {
+		count := byTeam[team.ID]
+		entry := VotingTallyTeam{TeamID: team.ID, Name: team.Name}
+		if total > 0 {
+			entry.Share = float64(count) / float64(total)
+		}
+
+		if update.Hidden {
+			entry.Share = math.Round(entry.Share*100) / 100
+		} else {
+			entry.Count = &count
+		}
+		update.Teams = append(update.Teams, entry)
+	}
+
+	if !update.Hidden {
+		update.Ballots = &counts.Ballots
+	}
+
+	return update, errmsg.EmptyStatusError
+}
+
+// getVotingTallyHandler returns a snapshot of the live tally.
+// @Summary Get the live tally of a voting session
+// @Description Returns the same snapshot the tally stream sends, for screens that poll instead. While a session hides its tally, exact counts and the number of ballots are left out and shares are rounded to whole percents until it closes.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Produce json
+// @Param sessionID query string true "Voting session"
+// @Success 200 {object} VotingTallyUpdate
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/tally [get]
+func getVotingTallyHandler(c fiber.Ctx) error {
+	session := models.VotingSession{ID: c.Query("sessionID")}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teams, serr := session.LoadFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	update, serr := liveTally(session, teams, time.Now())
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	return c.JSON(update)
+}
+
+// issueTallyStreamTokenHandler issues a short-lived token for opening the tally stream.
+// @Summary Issue a tally stream token
+// @Description Browsers' EventSource cannot send an Authorization header, so the stage screen fetches this token first and opens the tally stream with it as the token query parameter. The token only opens the stream of the given session and expires after 2 minutes; a stream that is already open stays open.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Produce json
+// @Param sessionID query string true "Voting session"
+// @Success 200 {object} TallyStreamTokenResponse
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/tally/stream-token [post]
+func issueTallyStreamTokenHandler(c fiber.Ctx) error {
+	session := models.VotingSession{ID: c.Query("sessionID")}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	superuser := models.SuperUser{}
+	utils.GetLocals(c, "superuser", &superuser)
+
+	token, expiresAt := session.IssueTallyStreamToken(superuser.Username)
+
+	events.Em.VotingTallyStreamTokenIssued(superuser.Username, session.ID)
+
+	return c.JSON(TallyStreamTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// streamVotingTallyHandler streams the live tally as Server-Sent Events.
+// @Summary Stream the live tally of a voting session
+// @Description Sends a "tally" event with a VotingTallyUpdate right away and again whenever the tally changes, so the stage screen can animate results. Rescheduling or closing the session early is picked up as well. Once the session closes the final tally is sent, followed by a "closed" event, and the stream ends. Hidden tallies are withheld as in the snapshot endpoint. Send either a superuser token in the Authorization header or, from a browser's EventSource, a token from the stream-token endpoint as the token query parameter.
+// @Tags Superusers Voting
+// @Security SuperUserAuth
+// @Produce text/event-stream
+// @Param sessionID query string true "Voting session"
+// @Param token query string false "Stream token for this session, instead of the Authorization header"
+// @Success 200 {object} VotingTallyUpdate
+// @Failure 401 {object} errmsg._SuperUserNoToken
+// @Failure 401 {object} errmsg._VotingStreamTokenInvalid
+// @Failure 404 {object} errmsg._VotingSessionNotFound
+// @Failure 500 {object} errmsg._InternalServerError
+// @Router /superusers/voting/tally/stream [get]
+func streamVotingTallyHandler(c fiber.Ctx) error {
+	session := models.VotingSession{ID: c.Query("sessionID")}
+	if serr := session.Get(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	teams, serr := session.LoadFinalists()
+	if serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		ticker := time.NewTicker(tallyStreamInterval)
+		defer ticker.Stop()
+
+		var last []byte
+		lastSent := time.Now()
+
+		for {
+			// pick up sessions that were extended or closed early
+			latest := models.VotingSession{ID: session.ID}
+			if latest.Get() == errmsg.EmptyStatusError {
+				session = latest
+			}
+
+			update, serr := liveTally(session, teams, time.Now())
+			if serr != errmsg.EmptyStatusError {
+				data, _ := json.Marshal(map[string]string{"message": serr.Message})
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+				w.Flush()
+				return
+			}
+
+			data, err := json.Marshal(update)
+			if err != nil {
+				return
+			}
+
+			switch {
+			case !bytes.Equal(data, last):
+				fmt.Fprintf(w, "event: tally\ndata: %s\n\n", data)
+				last = data
+				lastSent = time.Now()
+			case time.Since(lastSent) >= tallyStreamKeepAlive:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				lastSent = time.Now()
+			}
+
+			if update.Status == models.VotingSessionClosed {
+				fmt.Fprint(w, "event: closed\ndata: {}\n\n")
+				w.Flush()
+				return
+			}
+
+			// a failed flush means the screen went away
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			<-ticker.C
+		}
+	})
+}
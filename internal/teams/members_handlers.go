@@ -113,6 +113,10 @@ func TeamMembersJoinHandler(c fiber.Ctx) error {
 		team.ID,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
@@ -183,6 +187,10 @@ func TeamMembersLeaveHandler(c fiber.Ctx) error {
 		team.ID,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
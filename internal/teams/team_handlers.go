@@ -86,6 +86,10 @@ func TeamCreateHandler(c fiber.Ctx) error {
 		team.ID,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
@@ -236,6 +240,10 @@ func TeamDeleteHandler(c fiber.Ctx) error {
 		oldID,
 	)
 
+	if serr := account.LoadHasVoted(); serr != errmsg.EmptyStatusError {
+		return utils.StatusError(c, serr)
+	}
+
 	return c.JSON(bson.M{
 		"token":   token,
 		"account": account,
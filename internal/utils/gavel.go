@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"maps"
 	"math"
 	"sort"
 )
@@ -34,6 +36,77 @@ type CrowdBTScorer struct {
 	sigmaSqPrior float64 // prior variance for team skill (default 1.0)
 	alphaPrior   float64 // prior alpha for judge reliability (default 10.0)
 	betaPrior    float64 // prior beta for judge reliability (default 1.0)
+
+	// Iteration control
+	maxIterations       int     // hard cap on EM iterations (default 500)
+	tolerance           float64 // stop once the largest change in team skill drops below this (default 0.001)
+	muStepSize          float64 // step size for team skill updates (default 0.05)
+	reliabilityStepSize float64 // step size for judge reliability updates (default 0.1)
+
+	// Diagnostics from the most recent Score call
+	diagnostics ScorerDiagnostics
+}
+
+// ScorerOptions configures the hyperparameters, priors and iteration control of a CrowdBTScorer.
+// Zero values are not substituted with defaults; start from DefaultScorerOptions and override fields.
+// Gamma is not an option: it only weighs expected information gain, which scoring doesn't compute.
+type ScorerOptions struct {
+	Lambda float64 `json:"lambda"`
+	Kappa  float64 `json:"kappa"`
+
+	MuPrior      float64 `json:"muPrior"`
+	SigmaSqPrior float64 `json:"sigmaSqPrior"`
+	AlphaPrior   float64 `json:"alphaPrior"`
+	BetaPrior    float64 `json:"betaPrior"`
+
+	MaxIterations       int     `json:"maxIterations"`
+	Tolerance           float64 `json:"tolerance"`
+	MuStepSize          float64 `json:"muStepSize"`
+	ReliabilityStepSize float64 `json:"reliabilityStepSize"`
+}
+
+// ScorerDiagnostics describes how the last Score call converged.
+type ScorerDiagnostics struct {
+	Iterations    int       `json:"iterations"`
+	Converged     bool      `json:"converged"`
+	FinalDelta    float64   `json:"finalDelta"`
+	LogLikelihood []float64 `json:"logLikelihood"` // one entry per iteration
+}
+
+// DefaultScorerOptions returns the standard parameters from the paper
+func DefaultScorerOptions() ScorerOptions {
+	return ScorerOptions{
+		Lambda:              1.0,
+		Kappa:               0.0001,
+		MuPrior:             0.0,
+		SigmaSqPrior:        1.0,
+		AlphaPrior:          10.0,
+		BetaPrior:           1.0,
+		MaxIterations:       500,
+		Tolerance:           0.001,
+		MuStepSize:          0.05,
+		ReliabilityStepSize: 0.1,
+	}
+}
+
+// Validate reports whether the options can be used to run the scorer
+func (o ScorerOptions) Validate() error {
+	switch {
+	case o.MaxIterations <= 0:
+		return errors.New("maxIterations must be positive")
+	case o.Tolerance < 0:
+		return errors.New("tolerance must not be negative")
+	case o.MuStepSize <= 0 || o.ReliabilityStepSize <= 0:
+		return errors.New("step sizes must be positive")
+	case o.SigmaSqPrior <= 0 || o.AlphaPrior <= 0 || o.BetaPrior <= 0:
+		return errors.New("sigmaSqPrior, alphaPrior and betaPrior must be positive")
+	case o.Kappa <= 0:
+		return errors.New("kappa must be positive")
+	case o.Lambda < 0:
+		return errors.New("lambda must not be negative")
+	}
+
+	return nil
 }
 
 // Judgment represents a pairwise comparison made by a judge
@@ -41,52 +114,223 @@ type JudgmentWithJudge struct {
 	WinningTeamID string
 	LosingTeamID  string
 	JudgeID       string
+	Weight        float64 // scales the judgment's updates; zero counts as a full weight of 1
+}
+
+// weight returns how much the judgment counts
+func (j JudgmentWithJudge) weight() float64 {
+	if j.Weight <= 0 {
+		return 1.0
+	}
+	return j.Weight
 }
 
 // NewCrowdBTScorer creates a new Crowd BT scorer with standard parameters from the paper
 func NewCrowdBTScorer() *CrowdBTScorer {
+	return NewCrowdBTScorerWithOptions(DefaultScorerOptions())
+}
+
+// NewCrowdBTScorerWithOptions creates a new Crowd BT scorer with custom hyperparameters
+func NewCrowdBTScorerWithOptions(opts ScorerOptions) *CrowdBTScorer {
 	return &CrowdBTScorer{
-		teamMu:       make(map[string]float64),
-		teamSigmaSq:  make(map[string]float64),
-		judgeAlpha:   make(map[string]float64),
-		judgeBeta:    make(map[string]float64),
-		gamma:        0.1,
-		lambda:       1.0,
-		kappa:        0.0001,
-		muPrior:      0.0,
-		sigmaSqPrior: 1.0,
-		alphaPrior:   10.0,
-		betaPrior:    1.0,
+		teamMu:              make(map[string]float64),
+		teamSigmaSq:         make(map[string]float64),
+		judgeAlpha:          make(map[string]float64),
+		judgeBeta:           make(map[string]float64),
+		gamma:               0.1,
+		lambda:              opts.Lambda,
+		kappa:               opts.Kappa,
+		muPrior:             opts.MuPrior,
+		sigmaSqPrior:        opts.SigmaSqPrior,
+		alphaPrior:          opts.AlphaPrior,
+		betaPrior:           opts.BetaPrior,
+		maxIterations:       opts.MaxIterations,
+		tolerance:           opts.Tolerance,
+		muStepSize:          opts.MuStepSize,
+		reliabilityStepSize: opts.ReliabilityStepSize,
 	}
 }
 
 // Score computes team skill ratings and judge reliability parameters from judgments.
 // Returns a map of teamID to (mu, sigma_sq) representing skill and uncertainty.
 // Judge parameters are updated internally and can be retrieved with GetJudgeParams.
+// Iteration stops once the largest change in a team's skill (mu) falls below the tolerance
+// or the iteration cap is reached; judge reliability is not part of that delta. See
+// Diagnostics for how the run converged.
 func (cbt *CrowdBTScorer) Score(judgments []JudgmentWithJudge) map[string]float64 {
+	cbt.diagnostics = ScorerDiagnostics{LogLikelihood: []float64{}}
+
 	if len(judgments) == 0 {
+		cbt.diagnostics.Converged = true
 		return cbt.teamMu
 	}
 
 	// Initialize all teams and judges
 	cbt.initializeFromJudgments(judgments)
 
-	// Iterative EM-style updates (convergence typically happens in 5-10 iterations)
-	for iteration := 0; iteration < 10; iteration++ {
+	// Updates are applied in place, so visit judges and teams in a fixed order
+	// to keep results reproducible
+	judgeIDs := sortedKeys(cbt.judgeAlpha)
+	teamIDs := sortedKeys(cbt.teamMu)
+
+	// Index judgments once so each iteration is linear in the number of judgments
+	byJudge := make(map[string][]JudgmentWithJudge, len(judgeIDs))
+	byTeam := make(map[string][]JudgmentWithJudge, len(teamIDs))
+	for _, j := range judgments {
+		byJudge[j.JudgeID] = append(byJudge[j.JudgeID], j)
+		byTeam[j.WinningTeamID] = append(byTeam[j.WinningTeamID], j)
+		if j.LosingTeamID != j.WinningTeamID {
+			byTeam[j.LosingTeamID] = append(byTeam[j.LosingTeamID], j)
+		}
+	}
+
+	// Iterative EM-style updates until the parameters stop moving
+	for iteration := 0; iteration < cbt.maxIterations; iteration++ {
+		// Convergence is tracked on team skill; judge reliability is re-derived
+		// from the prior every iteration and follows the skill estimates
+		delta := 0.0
+
 		// Update all judges based on their judgments
-		for judgeID := range cbt.judgeAlpha {
-			cbt.updateJudge(judgeID, judgments)
+		for _, judgeID := range judgeIDs {
+			cbt.updateJudge(judgeID, byJudge[judgeID])
 		}
 
 		// Update all teams based on judgments weighted by judge reliability
-		for teamID := range cbt.teamMu {
-			cbt.updateTeam(teamID, judgments)
+		for _, teamID := range teamIDs {
+			mu := cbt.teamMu[teamID]
+			cbt.updateTeam(teamID, byTeam[teamID])
+			delta = math.Max(delta, math.Abs(cbt.teamMu[teamID]-mu))
+		}
+
+		cbt.diagnostics.Iterations = iteration + 1
+		cbt.diagnostics.FinalDelta = delta
+		cbt.diagnostics.LogLikelihood = append(cbt.diagnostics.LogLikelihood, cbt.LogLikelihood(judgments))
+
+		if delta < cbt.tolerance {
+			cbt.diagnostics.Converged = true
+			break
 		}
 	}
 
 	return cbt.teamMu
 }
 
+// Observe folds a single new judgment into the current estimates with one online
+// Crowd-BT update, touching only the judge and the two teams involved. It is meant
+// for live standings between full Score refits.
+func (cbt *CrowdBTScorer) Observe(j JudgmentWithJudge) {
+	cbt.initializeFromJudgments([]JudgmentWithJudge{j})
+
+	alpha := cbt.judgeAlpha[j.JudgeID]
+	beta := cbt.judgeBeta[j.JudgeID]
+	mu_w, sigma_sq_w := cbt.teamMu[j.WinningTeamID], cbt.teamSigmaSq[j.WinningTeamID]
+	mu_l, sigma_sq_l := cbt.teamMu[j.LosingTeamID], cbt.teamSigmaSq[j.LosingTeamID]
+
+	c_1 := cbt.winProbability(mu_w, sigma_sq_w, mu_l, sigma_sq_l)
+	cbt.judgeAlpha[j.JudgeID], cbt.judgeBeta[j.JudgeID] = cbt.reliabilityPosterior(alpha, beta, c_1)
+
+	mu_delta, sigma_sq_delta := cbt.updateMuSigmaSq(alpha, beta, mu_w, sigma_sq_w, mu_l, sigma_sq_l, true)
+	mu_delta *= j.weight()
+	sigma_sq_delta *= j.weight()
+	cbt.teamMu[j.WinningTeamID] = mu_w + sigma_sq_w*mu_delta
+	cbt.teamMu[j.LosingTeamID] = mu_l - sigma_sq_l*mu_delta
+	cbt.teamSigmaSq[j.WinningTeamID] = sigma_sq_w * math.Max(1.0+sigma_sq_w*sigma_sq_delta, cbt.kappa)
+	cbt.teamSigmaSq[j.LosingTeamID] = sigma_sq_l * math.Max(1.0+sigma_sq_l*sigma_sq_delta, cbt.kappa)
+}
+
+// ScorerState is a serializable snapshot of all learned scorer parameters
+type ScorerState struct {
+	TeamMu      map[string]float64 `json:"teamMu" bson:"teamMu"`
+	TeamSigmaSq map[string]float64 `json:"teamSigmaSq" bson:"teamSigmaSq"`
+	JudgeAlpha  map[string]float64 `json:"judgeAlpha" bson:"judgeAlpha"`
+	JudgeBeta   map[string]float64 `json:"judgeBeta" bson:"judgeBeta"`
+}
+
+// State returns a copy of the learned parameters
+func (cbt *CrowdBTScorer) State() ScorerState {
+	return ScorerState{
+		TeamMu:      maps.Clone(cbt.teamMu),
+		TeamSigmaSq: maps.Clone(cbt.teamSigmaSq),
+		JudgeAlpha:  maps.Clone(cbt.judgeAlpha),
+		JudgeBeta:   maps.Clone(cbt.judgeBeta),
+	}
+}
+
+// LoadState replaces the learned parameters with a previously saved snapshot
+func (cbt *CrowdBTScorer) LoadState(state ScorerState) {
+	cbt.teamMu = cloneOrEmpty(state.TeamMu)
+	cbt.teamSigmaSq = cloneOrEmpty(state.TeamSigmaSq)
+	cbt.judgeAlpha = cloneOrEmpty(state.JudgeAlpha)
+	cbt.judgeBeta = cloneOrEmpty(state.JudgeBeta)
+}
+
+func cloneOrEmpty(m map[string]float64) map[string]float64 {
+	if m == nil {
+		return make(map[string]float64)
+	}
+	return maps.Clone(m)
+}
+
+// Diagnostics returns the convergence details of the most recent Score call
+func (cbt *CrowdBTScorer) Diagnostics() ScorerDiagnostics {
+	return cbt.diagnostics
+}
+
+// LogLikelihood computes the log-likelihood of the judgments under the current
+// Crowd-BT parameters: a judge answers according to Bradley-Terry with probability
+// alpha/(alpha+beta) and flips the outcome otherwise.
+func (cbt *CrowdBTScorer) LogLikelihood(judgments []JudgmentWithJudge) float64 {
+	ll := 0.0
+	for _, j := range judgments {
+		exp_w := math.Exp(cbt.teamMu[j.WinningTeamID])
+		exp_l := math.Exp(cbt.teamMu[j.LosingTeamID])
+
+		alpha := cbt.judgeAlpha[j.JudgeID]
+		beta := cbt.judgeBeta[j.JudgeID]
+		reliability := alpha / (alpha + beta)
+
+		prob := reliability*exp_w/(exp_w+exp_l) + (1.0-reliability)*exp_l/(exp_w+exp_l)
+		ll += j.weight() * math.Log(math.Max(prob, cbt.kappa))
+	}
+
+	return ll
+}
+
+// teamParams returns a team's (mu, sigma_sq), falling back to the priors for unseen teams
+func (cbt *CrowdBTScorer) teamParams(teamID string) (float64, float64) {
+	mu, ok := cbt.teamMu[teamID]
+	if !ok {
+		return cbt.muPrior, cbt.sigmaSqPrior
+	}
+	return mu, cbt.teamSigmaSq[teamID]
+}
+
+// reliabilityPosterior moment-matches the Beta posterior of a judge after one decision
+// that agrees with the current estimates with probability c_1
+func (cbt *CrowdBTScorer) reliabilityPosterior(alpha, beta, c_1 float64) (float64, float64) {
+	c_2 := 1.0 - c_1
+	c := c_1*alpha + c_2*beta
+
+	expt := (c_1*(alpha+1.0)*alpha + c_2*alpha*beta) / (c * (alpha + beta + 1.0))
+	expt_sq := (c_1*(alpha+2.0)*(alpha+1.0)*alpha + c_2*(alpha+1.0)*alpha*beta) / (c * (alpha + beta + 2.0) * (alpha + beta + 1.0))
+
+	variance := math.Max(expt_sq-expt*expt, cbt.kappa)
+	new_alpha := math.Max((expt-expt_sq)*expt/variance, 0.1)
+	new_beta := math.Max((expt-expt_sq)*(1.0-expt)/variance, 0.1)
+
+	return new_alpha, new_beta
+}
+
+// sortedKeys returns the keys of a parameter map in ascending order
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // initializeFromJudgments extracts all unique teams and judges, initializing their parameters
 func (cbt *CrowdBTScorer) initializeFromJudgments(judgments []JudgmentWithJudge) {
 	for _, j := range judgments {
@@ -109,15 +353,8 @@ func (cbt *CrowdBTScorer) initializeFromJudgments(judgments []JudgmentWithJudge)
 }
 
 // updateJudge updates a judge's reliability parameters (alpha, beta) based on their voting pattern
-func (cbt *CrowdBTScorer) updateJudge(judgeID string, judgments []JudgmentWithJudge) {
-	// Find all judgments made by this judge
-	var judgeJudgments []JudgmentWithJudge
-	for _, j := range judgments {
-		if j.JudgeID == judgeID {
-			judgeJudgments = append(judgeJudgments, j)
-		}
-	}
-
+// judgeJudgments must contain only the judgments made by this judge
+func (cbt *CrowdBTScorer) updateJudge(judgeID string, judgeJudgments []JudgmentWithJudge) {
 	if len(judgeJudgments) == 0 {
 		return
 	}
@@ -154,8 +391,8 @@ func (cbt *CrowdBTScorer) updateJudge(judgeID string, judgments []JudgmentWithJu
 		alpha_delta := ((expt - expt_sq) * expt) / variance
 		beta_delta := (expt - expt_sq) * (1.0 - expt) / variance
 
-		sumAlphaDelta += alpha_delta
-		sumBetaDelta += beta_delta
+		sumAlphaDelta += j.weight() * alpha_delta
+		sumBetaDelta += j.weight() * beta_delta
 	}
 
 	// Average and apply updates with regularization
@@ -166,8 +403,8 @@ func (cbt *CrowdBTScorer) updateJudge(judgeID string, judgments []JudgmentWithJu
 	alpha := cbt.judgeAlpha[judgeID]
 	beta := cbt.judgeBeta[judgeID]
 
-	cbt.judgeAlpha[judgeID] = alpha + 0.1*avgAlphaDelta + cbt.lambda*(cbt.alphaPrior-alpha)
-	cbt.judgeBeta[judgeID] = beta + 0.1*avgBetaDelta + cbt.lambda*(cbt.betaPrior-beta)
+	cbt.judgeAlpha[judgeID] = alpha + cbt.reliabilityStepSize*avgAlphaDelta + cbt.lambda*(cbt.alphaPrior-alpha)
+	cbt.judgeBeta[judgeID] = beta + cbt.reliabilityStepSize*avgBetaDelta + cbt.lambda*(cbt.betaPrior-beta)
 
 	// Ensure positivity
 	if cbt.judgeAlpha[judgeID] < 0.1 {
@@ -179,15 +416,10 @@ func (cbt *CrowdBTScorer) updateJudge(judgeID string, judgments []JudgmentWithJu
 }
 
 // updateTeam updates a team's skill parameters (mu, sigma_sq) weighted by judge reliability
-func (cbt *CrowdBTScorer) updateTeam(teamID string, judgments []JudgmentWithJudge) {
-	// Find all judgments involving this team
-	var teamJudgments []JudgmentWithJudge
-	for _, j := range judgments {
-		if j.WinningTeamID == teamID || j.LosingTeamID == teamID {
-			teamJudgments = append(teamJudgments, j)
-		}
-	}
-
+// teamJudgments must contain only the judgments involving this team. Each judgment's
+// share of the averaged update is scaled by its weight, so a down-weighted judgment
+// moves the team less than a full one.
+func (cbt *CrowdBTScorer) updateTeam(teamID string, teamJudgments []JudgmentWithJudge) {
 	if len(teamJudgments) == 0 {
 		return
 	}
@@ -224,8 +456,8 @@ func (cbt *CrowdBTScorer) updateTeam(teamID string, judgments []JudgmentWithJudg
 				mu_opp, sigma_sq_opp,
 				true,
 			)
-			sumMuDelta += mu_delta
-			sumSigmaSqDelta += sigma_sq_delta
+			sumMuDelta += j.weight() * mu_delta
+			sumSigmaSqDelta += j.weight() * sigma_sq_delta
 		} else {
 			mu_delta, sigma_sq_delta := cbt.updateMuSigmaSq(
 				alpha, beta,
@@ -233,8 +465,8 @@ func (cbt *CrowdBTScorer) updateTeam(teamID string, judgments []JudgmentWithJudg
 				mu_team, sigma_sq_team,
 				false,
 			)
-			sumMuDelta -= mu_delta
-			sumSigmaSqDelta += sigma_sq_delta
+			sumMuDelta -= j.weight() * mu_delta
+			sumSigmaSqDelta += j.weight() * sigma_sq_delta
 		}
 	}
 
@@ -243,7 +475,7 @@ func (cbt *CrowdBTScorer) updateTeam(teamID string, judgments []JudgmentWithJudg
 	avgSigmaSqDelta := sumSigmaSqDelta / float64(len(teamJudgments))
 
 	// Apply updates
-	cbt.teamMu[teamID] += 0.05 * avgMuDelta
+	cbt.teamMu[teamID] += cbt.muStepSize * avgMuDelta
 	newSigmaSq := cbt.teamSigmaSq[teamID] * math.Max(1.0+cbt.teamSigmaSq[teamID]*avgSigmaSqDelta, cbt.kappa)
 	cbt.teamSigmaSq[teamID] = newSigmaSq
 }
@@ -381,8 +613,9 @@ func (cbt *CrowdBTScorer) GetJudgeStats(judgeID string) (float64, float64, bool)
 
 // ScoreCrowdBTWithParams allows custom hyperparameter tuning
 func ScoreCrowdBTWithParams(judgments []JudgmentWithJudge, gamma, lambda float64) map[string]float64 {
-	scorer := NewCrowdBTScorer()
+	opts := DefaultScorerOptions()
+	opts.Lambda = lambda
+	scorer := NewCrowdBTScorerWithOptions(opts)
 	scorer.gamma = gamma
-	scorer.lambda = lambda
 	return scorer.Score(judgments)
 }
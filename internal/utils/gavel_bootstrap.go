@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// BootstrapOptions configures a resampling analysis of Crowd-BT rankings
+type BootstrapOptions struct {
+	Samples int   // number of bootstrap resamples (default 100)
+	TopN    int   // size of the group whose membership probability is reported (default 5)
+	Seed    int64 // seed for the resampling, so an analysis can be reproduced
+	Scorer  ScorerOptions
+}
+
+// TeamRankStability summarizes how a team's rank moves across bootstrap resamples
+type TeamRankStability struct {
+	TeamID           string  `json:"teamID"`
+	Rank             int     `json:"rank"` // 1-based rank in the fit on all judgments
+	Mu               float64 `json:"mu"`
+	MeanRank         float64 `json:"meanRank"`
+	RankLow          int     `json:"rankLow"`          // 2.5th percentile rank
+	RankHigh         int     `json:"rankHigh"`         // 97.5th percentile rank
+	RankDistribution []int   `json:"rankDistribution"` // RankDistribution[r-1] = resamples where the team ranked r
+	TopNProbability  float64 `json:"topNProbability"`
+}
+
+// BootstrapResult holds per-team rank distributions and pairwise ordering probabilities
+type BootstrapResult struct {
+	Samples int                 `json:"samples"`
+	TopN    int                 `json:"topN"`
+	Seed    int64               `json:"seed"`
+	Teams   []TeamRankStability `json:"teams"`
+	// PairwiseWinProbability[a][b] is the fraction of resamples where a ranked above b
+	PairwiseWinProbability map[string]map[string]float64 `json:"pairwiseWinProbability"`
+}
+
+// DefaultBootstrapOptions returns a resampling setup suited for finalist selection
+func DefaultBootstrapOptions() BootstrapOptions {
+	return BootstrapOptions{
+		Samples: 100,
+		TopN:    5,
+		Seed:    1,
+		Scorer:  DefaultScorerOptions(),
+	}
+}
+
+// Validate reports whether the options can be used for a bootstrap run
+func (o BootstrapOptions) Validate() error {
+	if o.Samples <= 0 || o.Samples > 1000 {
+		return errors.New("samples must be between 1 and 1000")
+	}
+	if o.TopN <= 0 {
+		return errors.New("topN must be positive")
+	}
+
+	return o.Scorer.Validate()
+}
+
+// BootstrapRankings refits the Crowd-BT scorer on judgments resampled with replacement
+// and reports how stable each team's rank is. Teams missing from a resample keep the
+// prior skill for that resample. Each resample uses its own seeded source, so results
+// only depend on the options and the judgment order.
+func BootstrapRankings(judgments []JudgmentWithJudge, opts BootstrapOptions) BootstrapResult {
+	result := BootstrapResult{
+		Samples:                opts.Samples,
+		TopN:                   opts.TopN,
+		Seed:                   opts.Seed,
+		Teams:                  []TeamRankStability{},
+		PairwiseWinProbability: map[string]map[string]float64{},
+	}
+
+	if len(judgments) == 0 {
+		return result
+	}
+
+	// The full-data fit defines the reported order and the team universe
+	full := NewCrowdBTScorerWithOptions(opts.Scorer)
+	full.Score(judgments)
+	fullMu := full.GetTeamScores()
+	teamIDs := rankByMu(fullMu, full.GetTeamUncertainty())
+
+	teamIndex := make(map[string]int, len(teamIDs))
+	for i, teamID := range teamIDs {
+		teamIndex[teamID] = i
+	}
+
+	numTeams := len(teamIDs)
+	sampleRanks := make([][]int, opts.Samples) // sampleRanks[s][teamIdx] = 0-based rank
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > opts.Samples {
+		workers = opts.Samples
+	}
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range next {
+				rng := rand.New(rand.NewSource(opts.Seed + int64(s)))
+
+				resample := make([]JudgmentWithJudge, len(judgments))
+				for i := range resample {
+					resample[i] = judgments[rng.Intn(len(judgments))]
+				}
+
+				scorer := NewCrowdBTScorerWithOptions(opts.Scorer)
+				scorer.Score(resample)
+
+				mu := make(map[string]float64, numTeams)
+				sigmaSq := make(map[string]float64, numTeams)
+				for _, teamID := range teamIDs {
+					mu[teamID], sigmaSq[teamID] = scorer.teamParams(teamID)
+				}
+
+				ranks := make([]int, numTeams)
+				for rank, teamID := range rankByMu(mu, sigmaSq) {
+					ranks[teamIndex[teamID]] = rank
+				}
+				sampleRanks[s] = ranks
+			}
+		}()
+	}
+	for s := range opts.Samples {
+		next <- s
+	}
+	close(next)
+	wg.Wait()
+
+	// Aggregate rank distributions and pairwise orderings
+	distributions := make([][]int, numTeams)
+	for i := range distributions {
+		distributions[i] = make([]int, numTeams)
+	}
+	above := make([][]int, numTeams)
+	for i := range above {
+		above[i] = make([]int, numTeams)
+	}
+
+	for _, ranks := range sampleRanks {
+		for a := range numTeams {
+			distributions[a][ranks[a]]++
+			for b := range numTeams {
+				if a != b && ranks[a] < ranks[b] {
+					above[a][b]++
+				}
+			}
+		}
+	}
+
+	samples := float64(opts.Samples)
+	for a, teamID := range teamIDs {
+		stability := TeamRankStability{
+			TeamID:           teamID,
+			Rank:             a + 1,
+			Mu:               fullMu[teamID],
+			RankDistribution: distributions[a],
+			RankLow:          rankPercentile(distributions[a], opts.Samples, 0.025),
+			RankHigh:         rankPercentile(distributions[a], opts.Samples, 0.975),
+		}
+
+		rankSum := 0
+		topN := 0
+		for rank, count := range distributions[a] {
+			rankSum += (rank + 1) * count
+			if rank < opts.TopN {
+				topN += count
+			}
+		}
+		stability.MeanRank = float64(rankSum) / samples
+		stability.TopNProbability = float64(topN) / samples
+
+		result.Teams = append(result.Teams, stability)
+
+		pairwise := make(map[string]float64, numTeams-1)
+		for b, otherID := range teamIDs {
+			if a != b {
+				pairwise[otherID] = float64(above[a][b]) / samples
+			}
+		}
+		result.PairwiseWinProbability[teamID] = pairwise
+	}
+
+	return result
+}
+
+// rankByMu orders teams by mu descending, using lower uncertainty and then ID as tiebreakers
+func rankByMu(mu map[string]float64, sigmaSq map[string]float64) []string {
+	teamIDs := make([]string, 0, len(mu))
+	for teamID := range mu {
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	sort.Slice(teamIDs, func(i, j int) bool {
+		a, b := teamIDs[i], teamIDs[j]
+		if mu[a] != mu[b] {
+			return mu[a] > mu[b]
+		}
+		if sigmaSq[a] != sigmaSq[b] {
+			return sigmaSq[a] < sigmaSq[b]
+		}
+		return a < b
+	})
+
+	return teamIDs
+}
+
+// rankPercentile returns the 1-based rank at quantile q of a rank histogram
+func rankPercentile(distribution []int, total int, q float64) int {
+	threshold := q * float64(total)
+	cumulative := 0
+	for rank, count := range distribution {
+		cumulative += count
+		if float64(cumulative) >= threshold && cumulative > 0 {
+			return rank + 1
+		}
+	}
+	return len(distribution)
+}
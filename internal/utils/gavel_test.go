@@ -466,3 +466,139 @@ func generateInverseBiasedJudgments(teamIDs []string, judgeID string, numJudgmen
 
 	return judgments
 }
+
+// TestCrowdBTConvergenceDiagnostics tests that scoring stops on tolerance and reports diagnostics
+func TestCrowdBTConvergenceDiagnostics(t *testing.T) {
+	groundTruthMu := make(map[string]float64)
+	for i := range 8 {
+		groundTruthMu[fmt.Sprintf("team_%d", i)] = (float64(i) - 3.5) * 0.5
+	}
+	judgments := generateRealisticSyntheticJudgments(groundTruthMu, 6, 6)
+
+	// A loose tolerance should stop well before the iteration cap
+	opts := DefaultScorerOptions()
+	opts.Tolerance = 0.05
+	scorer := NewCrowdBTScorerWithOptions(opts)
+	scorer.Score(judgments)
+
+	diagnostics := scorer.Diagnostics()
+	require.True(t, diagnostics.Converged, "scorer should converge with a loose tolerance")
+	require.Less(t, diagnostics.Iterations, opts.MaxIterations)
+	require.Less(t, diagnostics.FinalDelta, opts.Tolerance)
+	require.Len(t, diagnostics.LogLikelihood, diagnostics.Iterations)
+
+	// A tight tolerance should hit the cap and report it
+	opts = DefaultScorerOptions()
+	opts.MaxIterations = 5
+	opts.Tolerance = 0
+	scorer = NewCrowdBTScorerWithOptions(opts)
+	scorer.Score(judgments)
+
+	diagnostics = scorer.Diagnostics()
+	require.False(t, diagnostics.Converged)
+	require.Equal(t, 5, diagnostics.Iterations)
+	require.Len(t, diagnostics.LogLikelihood, 5)
+	require.Greater(t, diagnostics.LogLikelihood[4], diagnostics.LogLikelihood[0], "fit should improve the likelihood")
+
+	// No judgments means nothing to fit
+	scorer = NewCrowdBTScorer()
+	scorer.Score(nil)
+	require.True(t, scorer.Diagnostics().Converged)
+	require.Equal(t, 0, scorer.Diagnostics().Iterations)
+}
+
+// TestCrowdBTScorerOptionsValidate tests rejection of unusable scorer options
+func TestCrowdBTScorerOptionsValidate(t *testing.T) {
+	require.NoError(t, DefaultScorerOptions().Validate())
+
+	opts := DefaultScorerOptions()
+	opts.MaxIterations = 0
+	require.Error(t, opts.Validate())
+
+	opts = DefaultScorerOptions()
+	opts.MuStepSize = -1
+	require.Error(t, opts.Validate())
+
+	opts = DefaultScorerOptions()
+	opts.SigmaSqPrior = 0
+	require.Error(t, opts.Validate())
+
+	opts = DefaultScorerOptions()
+	opts.Lambda = -1
+	require.EqualError(t, opts.Validate(), "lambda must not be negative")
+}
+
+// TestCrowdBTBootstrapRankings tests rank distributions and pairwise probabilities from resampling
+func TestCrowdBTBootstrapRankings(t *testing.T) {
+	groundTruthMu := make(map[string]float64)
+	for i := range 6 {
+		groundTruthMu[fmt.Sprintf("team_%d", i)] = (float64(i) - 2.5) * 1.5
+	}
+	judgments := generateRealisticSyntheticJudgments(groundTruthMu, 5, 8)
+
+	opts := DefaultBootstrapOptions()
+	opts.Samples = 40
+	opts.TopN = 2
+	opts.Scorer.MaxIterations = 100
+	require.NoError(t, opts.Validate())
+
+	result := BootstrapRankings(judgments, opts)
+	require.Len(t, result.Teams, 6)
+	require.Equal(t, 40, result.Samples)
+
+	topNTotal := 0.0
+	for i, team := range result.Teams {
+		require.Equal(t, i+1, team.Rank)
+		require.LessOrEqual(t, team.RankLow, team.RankHigh)
+
+		counted := 0
+		for _, count := range team.RankDistribution {
+			counted += count
+		}
+		require.Equal(t, opts.Samples, counted, "every resample should rank every team")
+		topNTotal += team.TopNProbability
+	}
+	require.InDelta(t, float64(opts.TopN), topNTotal, 1e-9, "top-N probabilities should sum to N")
+
+	// Pairwise orderings are complementary
+	for a, row := range result.PairwiseWinProbability {
+		for b, p := range row {
+			require.InDelta(t, 1.0, p+result.PairwiseWinProbability[b][a], 1e-9)
+		}
+	}
+
+	// The strongest team should clearly lead the weakest one
+	best := result.Teams[0].TeamID
+	worst := result.Teams[len(result.Teams)-1].TeamID
+	require.Greater(t, result.PairwiseWinProbability[best][worst], 0.9)
+
+	// Same seed, same answer
+	again := BootstrapRankings(judgments, opts)
+	require.Equal(t, result.Teams, again.Teams)
+}
+
+// TestCrowdBTObserveIncremental tests online updates and state snapshots for live standings
+func TestCrowdBTObserveIncremental(t *testing.T) {
+	scorer := NewCrowdBTScorer()
+	scorer.Score([]JudgmentWithJudge{
+		{WinningTeamID: "a", LosingTeamID: "b", JudgeID: "j1"},
+		{WinningTeamID: "b", LosingTeamID: "c", JudgeID: "j2"},
+	})
+
+	before := scorer.State()
+	scorer.Observe(JudgmentWithJudge{WinningTeamID: "c", LosingTeamID: "a", JudgeID: "j3"})
+	after := scorer.State()
+
+	require.Greater(t, after.TeamMu["c"], before.TeamMu["c"], "winner skill should rise")
+	require.Less(t, after.TeamMu["a"], before.TeamMu["a"], "loser skill should drop")
+	require.Equal(t, before.TeamMu["b"], after.TeamMu["b"], "uninvolved team should not move")
+	require.Contains(t, after.JudgeAlpha, "j3", "new judges should be initialized")
+
+	// The snapshot is a copy and restores into a fresh scorer
+	after.TeamMu["c"] = 100
+	restored := NewCrowdBTScorer()
+	restored.LoadState(scorer.State())
+	require.Equal(t, scorer.GetTeamScores(), restored.GetTeamScores())
+	require.Equal(t, scorer.GetJudgeReliabilityAll(), restored.GetJudgeReliabilityAll())
+	require.Equal(t, scorer.RankTeams(), restored.RankTeams())
+}
@@ -1,38 +1,51 @@
 package utils
 
 import (
-	"math/rand"
-	"strconv"
+	"crypto/rand"
+	"math/big"
 )
 
 var Encoding string = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-"
 var TeamIDEncoding string = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 var CodeEncoding string = "abcdefghijklmnopqrstuvwxyz0123456789"
 
-func GenTeamID() (ID string) {
-	for range 6 {
-		ID += string(TeamIDEncoding[rand.Intn(26)])
+// genFrom draws n characters uniformly from alphabet using crypto/rand
+func genFrom(alphabet string, n int) string {
+	max := big.NewInt(int64(len(alphabet)))
+	ID := make([]byte, n)
+
+	for i := range ID {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			// crypto/rand only fails if the OS has no entropy source
+			panic(err)
+		}
+		ID[i] = alphabet[idx.Int64()]
 	}
 
-	return
+	return string(ID)
 }
 
-func GenID(n int) string {
-	var ID string
-
-	for range n {
-		ID += string(Encoding[rand.Intn(64)])
+// GenSeed returns a random non-negative seed below 2^53, so it survives a round
+// trip through JSON numbers in JavaScript clients
+func GenSeed() int64 {
+	max := big.NewInt(1 << 53)
+	seed, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		panic(err)
 	}
 
-	return ID
+	return seed.Int64()
 }
 
-func GenCode(n int) string {
-	var code string
+func GenTeamID() (ID string) {
+	return genFrom(TeamIDEncoding, 6)
+}
 
-	for range n {
-		code += strconv.Itoa(rand.Intn(10))
-	}
+func GenID(n int) string {
+	return genFrom(Encoding, n)
+}
 
-	return code
+func GenCode(n int) string {
+	return genFrom("0123456789", n)
 }
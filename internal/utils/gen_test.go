@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenID tests that generated IDs have the requested length and alphabet
+func TestGenID(t *testing.T) {
+	seen := map[string]bool{}
+	for range 1000 {
+		ID := GenID(6)
+		require.Len(t, ID, 6)
+		for _, r := range ID {
+			require.True(t, strings.ContainsRune(Encoding, r), "unexpected character %q", r)
+		}
+		seen[ID] = true
+	}
+	require.Greater(t, len(seen), 990, "IDs should rarely repeat")
+
+	teamID := GenTeamID()
+	require.Len(t, teamID, 6)
+	require.Equal(t, strings.ToUpper(teamID), teamID)
+
+	code := GenCode(4)
+	require.Len(t, code, 4)
+	require.Equal(t, strings.Trim(code, "0123456789"), "")
+}
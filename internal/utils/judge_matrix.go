@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// getCoprimeMultipliers returns a list of numbers coprime to n
+func getCoprimeMultipliers(n int) []int {
+	coprimes := []int{}
+	for i := 1; i < n; i++ {
+		if gcd(i, n) == 1 {
+			coprimes = append(coprimes, i)
+		}
+	}
+	return coprimes
+}
+
+// gcd computes the greatest common divisor using Euclidean algorithm
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// BuildJudgeMatrix lays teams out in a step-by-group Latin rectangle: each group
+// visits every team at most once, no team is visited by two groups in the same
+// step, and each group's rest steps are spread out at random. Teams in a group's
+// blocked set are never assigned to it. matrix[step][groupIdx] is a team ID, or
+// "" when the group rests.
+func BuildJudgeMatrix(rng *rand.Rand, teamOrder []string, numGroups int, blockedTeams map[int]map[string]bool) ([][]string, error) {
+	numTeams := len(teamOrder)
+	if numTeams == 0 || numGroups == 0 {
+		return nil, errors.New("no teams or groups to assign")
+	}
+
+	numSteps := numGroups
+	if numTeams > numGroups {
+		numSteps = numTeams
+	}
+
+	// Strategy: For each group, randomly shuffle step assignments with blanks evenly distributed
+	// Then assign teams per-step ensuring no collisions (same team in same step for different groups)
+
+	matrix := make([][]string, numSteps)
+	for i := range numSteps {
+		matrix[i] = make([]string, numGroups)
+	}
+
+	pairGroupTeamsSeen := make(map[int]map[string]bool)
+	for i := range numGroups {
+		pairGroupTeamsSeen[i] = make(map[string]bool)
+	}
+
+	// For each group, determine which steps it will be active (rest of steps are blank)
+	// Distribute blanks evenly
+	activeStepsPerGroup := make([][]int, numGroups)
+	blanksPerGroup := numSteps - numTeams // How many blanks each group should have
+	if blanksPerGroup < 0 {
+		blanksPerGroup = 0
+	}
+
+	for groupIdx := 0; groupIdx < numGroups; groupIdx++ {
+		// Create list of all steps
+		allSteps := make([]int, numSteps)
+		for i := 0; i < numSteps; i++ {
+			allSteps[i] = i
+		}
+
+		// Shuffle and take the first (numSteps - blanksPerGroup) as active
+		rng.Shuffle(len(allSteps), func(i, j int) {
+			allSteps[i], allSteps[j] = allSteps[j], allSteps[i]
+		})
+
+		activeCount := numSteps - blanksPerGroup
+		activeStepsPerGroup[groupIdx] = allSteps[:activeCount]
+		sort.Ints(activeStepsPerGroup[groupIdx]) // Keep in order for consistency
+	}
+
+	// Now assign teams to active steps, ensuring no collisions
+	teamsAssignedPerStep := make(map[int]map[string]bool)
+	for i := 0; i < numSteps; i++ {
+		teamsAssignedPerStep[i] = make(map[string]bool)
+	}
+
+	coprimes := getCoprimeMultipliers(numTeams)
+	if len(coprimes) == 0 {
+		return nil, errors.New("no coprime multipliers found")
+	}
+
+	// Assign offset and multiplier to each group for team selection
+	pairOffsets := make([]int, numGroups)
+	pairMultipliers := make([]int, numGroups)
+
+	for i := range numGroups {
+		pairOffsets[i] = rng.Intn(numTeams)
+		pairMultipliers[i] = coprimes[i%len(coprimes)]
+	}
+
+	rng.Shuffle(len(pairMultipliers), func(i, j int) {
+		pairMultipliers[i], pairMultipliers[j] = pairMultipliers[j], pairMultipliers[i]
+	})
+
+	// Assign teams step-by-step to prevent collisions
+	// For each step, go through groups that are active in that step
+	for step := 0; step < numSteps; step++ {
+		for groupIdx := 0; groupIdx < numGroups; groupIdx++ {
+			// Check if this group is active in this step
+			isActiveInStep := false
+			stepInGroup := -1
+			for idx, activeStep := range activeStepsPerGroup[groupIdx] {
+				if activeStep == step {
+					isActiveInStep = true
+					stepInGroup = idx
+					break
+				}
+			}
+
+			if !isActiveInStep {
+				// This group is resting in this step
+				matrix[step][groupIdx] = ""
+				continue
+			}
+
+			// This group is active in this step, find a team
+			offset := pairOffsets[groupIdx]
+			multiplier := pairMultipliers[groupIdx]
+
+			// Calculate team index using offset + step*multiplier
+			teamIndex := (offset + stepInGroup*multiplier) % numTeams
+
+			// Try to find a team that:
+			// 1. Hasn't been assigned to this group yet
+			// 2. Isn't already assigned to ANY other group in this step
+			// 3. Doesn't conflict with any judge in this group
+			found := false
+			attempts := 0
+			maxAttempts := numTeams
+
+			for attempts < maxAttempts {
+				candidateTeamID := teamOrder[teamIndex]
+
+				if !pairGroupTeamsSeen[groupIdx][candidateTeamID] &&
+					!teamsAssignedPerStep[step][candidateTeamID] &&
+					!blockedTeams[groupIdx][candidateTeamID] {
+					matrix[step][groupIdx] = candidateTeamID
+					pairGroupTeamsSeen[groupIdx][candidateTeamID] = true
+					teamsAssignedPerStep[step][candidateTeamID] = true
+					found = true
+					break
+				}
+
+				teamIndex = (teamIndex + 1) % numTeams
+				attempts++
+			}
+
+			if !found {
+				// No valid team found for this group in this step, leave blank
+				matrix[step][groupIdx] = ""
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// JudgeMatrixMetrics describes how well an assignment matrix covers the teams
+type JudgeMatrixMetrics struct {
+	Assignments   int     `json:"assignments"`
+	BlankCells    int     `json:"blankCells"`
+	Collisions    int     `json:"collisions"`    // extra visits to a team already visited in the same step
+	GavelScore    float64 `json:"gavelScore"`    // percentage of team pairs seen together in some step
+	UniquePairs   int     `json:"uniquePairs"`   // team pairs seen together in some step
+	TotalPairs    int     `json:"totalPairs"`    // team pairs there are
+	AvgRedundancy float64 `json:"avgRedundancy"` // times each seen pair is seen
+}
+
+// MeasureJudgeMatrix counts assignments, blanks and collisions and the pairwise
+// coverage of a step-by-group matrix
+func MeasureJudgeMatrix(matrix [][]string, numGroups int, numTeams int) JudgeMatrixMetrics {
+	metrics := JudgeMatrixMetrics{}
+
+	for step := range matrix {
+		teamCounts := make(map[string]int)
+		for groupIdx := 0; groupIdx < numGroups; groupIdx++ {
+			teamID := matrix[step][groupIdx]
+			if teamID == "" {
+				metrics.BlankCells++
+			} else {
+				metrics.Assignments++
+				teamCounts[teamID]++
+			}
+		}
+		for _, count := range teamCounts {
+			if count > 1 {
+				metrics.Collisions += (count - 1)
+			}
+		}
+	}
+
+	// Calculate Gavel score (pairwise coverage)
+	type TeamPair struct {
+		TeamA string
+		TeamB string
+	}
+
+	uniquePairs := make(map[TeamPair]int)
+	for step := range matrix {
+		assignedTeams := []string{}
+		for groupIdx := 0; groupIdx < numGroups; groupIdx++ {
+			if matrix[step][groupIdx] != "" {
+				assignedTeams = append(assignedTeams, matrix[step][groupIdx])
+			}
+		}
+
+		// Record pairwise comparisons
+		for i := 0; i < len(assignedTeams); i++ {
+			for j := i + 1; j < len(assignedTeams); j++ {
+				pair := TeamPair{}
+				if assignedTeams[i] < assignedTeams[j] {
+					pair.TeamA = assignedTeams[i]
+					pair.TeamB = assignedTeams[j]
+				} else {
+					pair.TeamA = assignedTeams[j]
+					pair.TeamB = assignedTeams[i]
+				}
+				uniquePairs[pair]++
+			}
+		}
+	}
+
+	metrics.TotalPairs = (numTeams * (numTeams - 1)) / 2
+	metrics.UniquePairs = len(uniquePairs)
+	if metrics.TotalPairs > 0 {
+		metrics.GavelScore = float64(metrics.UniquePairs) / float64(metrics.TotalPairs) * 100
+	}
+
+	totalComparisons := 0
+	for _, count := range uniquePairs {
+		totalComparisons += count
+	}
+
+	if metrics.UniquePairs > 0 {
+		metrics.AvgRedundancy = float64(totalComparisons) / float64(metrics.UniquePairs)
+	}
+
+	return metrics
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildJudgeMatrix tests that the matrix is collision free, respects blocked teams and is reproducible
+func TestBuildJudgeMatrix(t *testing.T) {
+	teams := []string{}
+	for i := range 17 {
+		teams = append(teams, fmt.Sprintf("team_%d", i))
+	}
+	numGroups := 9
+	blocked := map[int]map[string]bool{3: {"team_5": true}}
+
+	matrix, err := BuildJudgeMatrix(rand.New(rand.NewSource(7)), teams, numGroups, blocked)
+	require.NoError(t, err)
+	require.Len(t, matrix, len(teams))
+
+	seen := make([]map[string]bool, numGroups)
+	for groupIdx := range numGroups {
+		seen[groupIdx] = map[string]bool{}
+	}
+	for step, row := range matrix {
+		require.Len(t, row, numGroups)
+		inStep := map[string]bool{}
+		for groupIdx, teamID := range row {
+			if teamID == "" {
+				continue
+			}
+			require.False(t, inStep[teamID], "team %s visited twice at step %d", teamID, step)
+			require.False(t, seen[groupIdx][teamID], "group %d visits %s twice", groupIdx, teamID)
+			require.False(t, blocked[groupIdx][teamID], "group %d visits blocked team %s", groupIdx, teamID)
+			inStep[teamID] = true
+			seen[groupIdx][teamID] = true
+		}
+	}
+
+	metrics := MeasureJudgeMatrix(matrix, numGroups, len(teams))
+	require.Equal(t, 0, metrics.Collisions)
+	require.Equal(t, len(teams)*numGroups, metrics.Assignments+metrics.BlankCells)
+	require.Equal(t, 17*16/2, metrics.TotalPairs)
+
+	again, err := BuildJudgeMatrix(rand.New(rand.NewSource(7)), teams, numGroups, blocked)
+	require.NoError(t, err)
+	require.Equal(t, matrix, again, "the same seed should give the same matrix")
+}
@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+var JudgeFlagLowAgreement = "low_agreement"
+var JudgeFlagLowReliability = "low_reliability"
+var JudgeFlagFast = "fast"
+var JudgeFlagSideBias = "side_bias"
+
+// JudgeObservation is a judgment along with when it was made and which team the
+// judge was shown first
+type JudgeObservation struct {
+	JudgmentWithJudge
+	Date      time.Time
+	FirstTeam string // the judge's previous team, shown before the current one; empty if unknown
+}
+
+// JudgeOutlierOptions configures when a judge is flagged
+type JudgeOutlierOptions struct {
+	MinJudgments    int           `json:"minJudgments"`    // judges with fewer judgments are reported but never flagged (default 5)
+	MinAgreement    float64       `json:"minAgreement"`    // flag agreement with the consensus below this (default 0.5)
+	AgreementZScore float64       `json:"agreementZScore"` // flag agreement this many standard deviations below the mean judge (default 2)
+	MinReliability  float64       `json:"minReliability"`  // flag alpha/(alpha+beta) below this (default 0.75)
+	FastSeconds     float64       `json:"fastSeconds"`     // gaps between judgments shorter than this count as fast (default 60)
+	FastShare       float64       `json:"fastShare"`       // flag when at least this share of gaps is fast (default 0.5)
+	SideBias        float64       `json:"sideBias"`        // flag when the first team wins at least this often, or at most 1 minus this (default 0.9)
+	Scorer          ScorerOptions `json:"scorer"`
+}
+
+// JudgeOutlierReport summarizes one judge's behavior against the other judges
+type JudgeOutlierReport struct {
+	JudgeID              string   `json:"judgeID"`
+	Judgments            int      `json:"judgments"`
+	Alpha                float64  `json:"alpha"`
+	Beta                 float64  `json:"beta"`
+	Reliability          float64  `json:"reliability"`          // alpha/(alpha+beta) in the fit on all judgments
+	Agreement            float64  `json:"agreement"`            // share of judgments matching a fit on the other judges' judgments
+	AgreementZScore      float64  `json:"agreementZScore"`      // agreement relative to the judges with enough judgments
+	MedianSecondsBetween float64  `json:"medianSecondsBetween"` // median gap between consecutive judgments
+	FastShare            float64  `json:"fastShare"`
+	FirstPickRate        float64  `json:"firstPickRate"` // share of judgments won by the team shown first
+	Flags                []string `json:"flags"`
+}
+
+// DefaultJudgeOutlierOptions returns thresholds that only flag clear outliers
+func DefaultJudgeOutlierOptions() JudgeOutlierOptions {
+	return JudgeOutlierOptions{
+		MinJudgments:    5,
+		MinAgreement:    0.5,
+		AgreementZScore: 2.0,
+		MinReliability:  0.75,
+		FastSeconds:     60,
+		FastShare:       0.5,
+		SideBias:        0.9,
+		Scorer:          DefaultScorerOptions(),
+	}
+}
+
+// Validate reports whether the options can be used for an analysis
+func (o JudgeOutlierOptions) Validate() error {
+	switch {
+	case o.MinJudgments < 1:
+		return errors.New("minJudgments must be positive")
+	case o.MinAgreement < 0 || o.MinAgreement > 1:
+		return errors.New("minAgreement must be between 0 and 1")
+	case o.AgreementZScore <= 0:
+		return errors.New("agreementZScore must be positive")
+	case o.MinReliability < 0 || o.MinReliability > 1:
+		return errors.New("minReliability must be between 0 and 1")
+	case o.FastSeconds < 0:
+		return errors.New("fastSeconds must not be negative")
+	case o.FastShare <= 0 || o.FastShare > 1:
+		return errors.New("fastShare must be between 0 and 1")
+	case o.SideBias <= 0.5 || o.SideBias > 1:
+		return errors.New("sideBias must be above 0.5 and at most 1")
+	}
+
+	return o.Scorer.Validate()
+}
+
+// AnalyzeJudgeOutliers flags judges who disagree with the consensus, whom the
+// scorer finds unreliable, who judge suspiciously fast, or who nearly always
+// pick the same side. Agreement is measured against a fit that leaves the judge
+// out, so an outlier cannot pull the consensus towards themselves. Reports are
+// sorted by judge ID.
+func AnalyzeJudgeOutliers(observations []JudgeObservation, opts JudgeOutlierOptions) []JudgeOutlierReport {
+	byJudge := make(map[string][]JudgeObservation)
+	all := make([]JudgmentWithJudge, 0, len(observations))
+	for _, o := range observations {
+		byJudge[o.JudgeID] = append(byJudge[o.JudgeID], o)
+		all = append(all, o.JudgmentWithJudge)
+	}
+
+	full := NewCrowdBTScorerWithOptions(opts.Scorer)
+	full.Score(all)
+
+	judgeIDs := make([]string, 0, len(byJudge))
+	for judgeID := range byJudge {
+		judgeIDs = append(judgeIDs, judgeID)
+	}
+	sort.Strings(judgeIDs)
+
+	reports := make([]JudgeOutlierReport, 0, len(judgeIDs))
+	for _, judgeID := range judgeIDs {
+		judgeObservations := byJudge[judgeID]
+		report := JudgeOutlierReport{
+			JudgeID:   judgeID,
+			Judgments: len(judgeObservations),
+			Flags:     []string{},
+		}
+
+		report.Alpha, report.Beta = full.GetJudgeReliability(judgeID)
+		report.Reliability = report.Alpha / (report.Alpha + report.Beta)
+		report.Agreement = leaveOneOutAgreement(judgeID, judgeObservations, all, opts.Scorer)
+		report.MedianSecondsBetween, report.FastShare = judgmentPace(judgeObservations, opts.FastSeconds)
+		report.FirstPickRate = firstPickRate(judgeObservations)
+
+		reports = append(reports, report)
+	}
+
+	// Agreement is compared only among judges with enough judgments to say anything
+	var agreements []float64
+	for _, report := range reports {
+		if report.Judgments >= opts.MinJudgments {
+			agreements = append(agreements, report.Agreement)
+		}
+	}
+	mean, stddev := meanStddev(agreements)
+
+	for i := range reports {
+		report := &reports[i]
+		if stddev > 0 {
+			report.AgreementZScore = (report.Agreement - mean) / stddev
+		}
+		if report.Judgments < opts.MinJudgments {
+			continue
+		}
+
+		if report.Agreement < opts.MinAgreement || (len(agreements) >= 3 && report.AgreementZScore < -opts.AgreementZScore) {
+			report.Flags = append(report.Flags, JudgeFlagLowAgreement)
+		}
+		if report.Reliability < opts.MinReliability {
+			report.Flags = append(report.Flags, JudgeFlagLowReliability)
+		}
+		if report.Judgments > 1 && report.FastShare >= opts.FastShare {
+			report.Flags = append(report.Flags, JudgeFlagFast)
+		}
+		if report.FirstPickRate >= opts.SideBias || report.FirstPickRate <= 1-opts.SideBias {
+			report.Flags = append(report.Flags, JudgeFlagSideBias)
+		}
+	}
+
+	return reports
+}
+
+// leaveOneOutAgreement is the share of the judge's judgments whose winner ranks
+// above the loser in a fit on everyone else's judgments. Judgments involving a
+// team nobody else judged are left out.
+func leaveOneOutAgreement(judgeID string, judgeObservations []JudgeObservation, all []JudgmentWithJudge, opts ScorerOptions) float64 {
+	others := make([]JudgmentWithJudge, 0, len(all))
+	for _, j := range all {
+		if j.JudgeID != judgeID {
+			others = append(others, j)
+		}
+	}
+
+	scorer := NewCrowdBTScorerWithOptions(opts)
+	scorer.Score(others)
+	mu := scorer.GetTeamScores()
+
+	agreed, compared := 0, 0
+	for _, o := range judgeObservations {
+		muW, okW := mu[o.WinningTeamID]
+		muL, okL := mu[o.LosingTeamID]
+		if !okW || !okL {
+			continue
+		}
+		compared++
+		if muW > muL {
+			agreed++
+		}
+	}
+
+	if compared == 0 {
+		return 1.0
+	}
+	return float64(agreed) / float64(compared)
+}
+
+// judgmentPace returns the median gap between consecutive judgments and the
+// share of gaps shorter than fastSeconds
+func judgmentPace(judgeObservations []JudgeObservation, fastSeconds float64) (float64, float64) {
+	dates := make([]time.Time, len(judgeObservations))
+	for i, o := range judgeObservations {
+		dates[i] = o.Date
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	if len(dates) < 2 {
+		return 0, 0
+	}
+
+	gaps := make([]float64, 0, len(dates)-1)
+	fast := 0
+	for i := 1; i < len(dates); i++ {
+		gap := dates[i].Sub(dates[i-1]).Seconds()
+		gaps = append(gaps, gap)
+		if gap < fastSeconds {
+			fast++
+		}
+	}
+	sort.Float64s(gaps)
+
+	median := gaps[len(gaps)/2]
+	if len(gaps)%2 == 0 {
+		median = (gaps[len(gaps)/2-1] + gaps[len(gaps)/2]) / 2
+	}
+
+	return median, float64(fast) / float64(len(gaps))
+}
+
+// firstPickRate is the share of judgments with a known first team that it won.
+// Without any it is 0.5, which never counts as a bias.
+func firstPickRate(judgeObservations []JudgeObservation) float64 {
+	first, sided := 0, 0
+	for _, o := range judgeObservations {
+		if o.FirstTeam == "" {
+			continue
+		}
+		sided++
+		if o.WinningTeamID == o.FirstTeam {
+			first++
+		}
+	}
+
+	if sided == 0 {
+		return 0.5
+	}
+	return float64(first) / float64(sided)
+}
+
+func meanStddev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzeJudgeOutliers plants a contrarian, a rushed and a side-biased judge
+// among honest ones and expects each to be flagged for what they did
+func TestAnalyzeJudgeOutliers(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	numTeams := 12
+	skill := func(teamID string) int {
+		var i int
+		fmt.Sscanf(teamID, "team_%d", &i)
+		return i
+	}
+
+	var observations []JudgeObservation
+	judge := func(judgeID string, gap time.Duration, pick func(first, second string) string) {
+		order := rng.Perm(numTeams)
+		for step := 1; step < len(order); step++ {
+			first := fmt.Sprintf("team_%d", order[step-1])
+			second := fmt.Sprintf("team_%d", order[step])
+			winner := pick(first, second)
+			loser := first
+			if winner == first {
+				loser = second
+			}
+			observations = append(observations, JudgeObservation{
+				JudgmentWithJudge: JudgmentWithJudge{WinningTeamID: winner, LosingTeamID: loser, JudgeID: judgeID},
+				Date:              start.Add(time.Duration(step) * gap),
+				FirstTeam:         first,
+			})
+		}
+	}
+
+	better := func(first, second string) string {
+		if skill(first) > skill(second) {
+			return first
+		}
+		return second
+	}
+	worse := func(first, second string) string {
+		if better(first, second) == first {
+			return second
+		}
+		return first
+	}
+
+	for i := range 6 {
+		judge(fmt.Sprintf("honest_%d", i), 6*time.Minute, better)
+	}
+	judge("contrarian", 6*time.Minute, worse)
+	judge("rushed", 20*time.Second, better)
+	judge("lefty", 6*time.Minute, func(first, second string) string { return first })
+
+	opts := DefaultJudgeOutlierOptions()
+	require.NoError(t, opts.Validate())
+
+	reports := AnalyzeJudgeOutliers(observations, opts)
+	require.Len(t, reports, 9)
+
+	flags := make(map[string][]string)
+	for _, report := range reports {
+		flags[report.JudgeID] = report.Flags
+		require.Equal(t, numTeams-1, report.Judgments)
+	}
+
+	require.Contains(t, flags["contrarian"], JudgeFlagLowAgreement)
+	require.Contains(t, flags["rushed"], JudgeFlagFast)
+	require.NotContains(t, flags["rushed"], JudgeFlagLowAgreement)
+	require.Contains(t, flags["lefty"], JudgeFlagSideBias)
+	for i := range 6 {
+		judgeID := fmt.Sprintf("honest_%d", i)
+		require.False(t, slices.Contains(flags[judgeID], JudgeFlagLowAgreement), "%s agrees with the consensus", judgeID)
+		require.NotContains(t, flags[judgeID], JudgeFlagFast)
+		require.NotContains(t, flags[judgeID], JudgeFlagSideBias)
+	}
+
+	bad := DefaultJudgeOutlierOptions()
+	bad.SideBias = 0.4
+	require.Error(t, bad.Validate())
+}
+
+// TestCrowdBTJudgmentWeight checks that a down-weighted judgment moves the teams less
+func TestCrowdBTJudgmentWeight(t *testing.T) {
+	full := NewCrowdBTScorer()
+	full.Observe(JudgmentWithJudge{WinningTeamID: "a", LosingTeamID: "b", JudgeID: "j"})
+
+	half := NewCrowdBTScorer()
+	half.Observe(JudgmentWithJudge{WinningTeamID: "a", LosingTeamID: "b", JudgeID: "j", Weight: 0.5})
+
+	require.Greater(t, full.GetTeamScores()["a"], half.GetTeamScores()["a"])
+	require.Greater(t, half.GetTeamScores()["a"], 0.0)
+}
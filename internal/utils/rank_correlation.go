@@ -0,0 +1,78 @@
+package utils
+
+// rankPositions returns each item's position in the ranking, keeping only items
+// that appear in both rankings, renumbered from 0 in the order of a
+func rankPositions(a []string, b []string) (posA map[string]int, posB map[string]int) {
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[item] = true
+	}
+
+	posA = map[string]int{}
+	for _, item := range a {
+		if inB[item] {
+			if _, seen := posA[item]; !seen {
+				posA[item] = len(posA)
+			}
+		}
+	}
+
+	posB = map[string]int{}
+	for _, item := range b {
+		if _, ok := posA[item]; ok {
+			if _, seen := posB[item]; !seen {
+				posB[item] = len(posB)
+			}
+		}
+	}
+
+	return posA, posB
+}
+
+// KendallTau measures how well two rankings agree, from -1 (reversed) to 1
+// (identical), as the share of item pairs they order the same way minus the
+// share they order differently. Items missing from either ranking are ignored.
+func KendallTau(a []string, b []string) float64 {
+	posA, posB := rankPositions(a, b)
+	n := len(posA)
+	if n < 2 {
+		return 1
+	}
+
+	items := make([]string, n)
+	for item, pos := range posA {
+		items[pos] = item
+	}
+
+	concordant, discordant := 0, 0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if posB[items[i]] < posB[items[j]] {
+				concordant++
+			} else {
+				discordant++
+			}
+		}
+	}
+
+	return float64(concordant-discordant) / float64(n*(n-1)/2)
+}
+
+// SpearmanRho is the rank correlation of two rankings, from -1 (reversed) to 1
+// (identical), weighing large displacements more than KendallTau does. Items
+// missing from either ranking are ignored.
+func SpearmanRho(a []string, b []string) float64 {
+	posA, posB := rankPositions(a, b)
+	n := len(posA)
+	if n < 2 {
+		return 1
+	}
+
+	sumSq := 0.0
+	for item, pos := range posA {
+		d := float64(pos - posB[item])
+		sumSq += d * d
+	}
+
+	return 1 - 6*sumSq/float64(n*(n*n-1))
+}
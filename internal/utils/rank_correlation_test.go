@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRankCorrelation tests Kendall tau and Spearman rho on known rankings
+func TestRankCorrelation(t *testing.T) {
+	truth := []string{"a", "b", "c", "d", "e"}
+
+	require.Equal(t, 1.0, KendallTau(truth, truth))
+	require.Equal(t, 1.0, SpearmanRho(truth, truth))
+
+	reversed := []string{"e", "d", "c", "b", "a"}
+	require.Equal(t, -1.0, KendallTau(reversed, truth))
+	require.Equal(t, -1.0, SpearmanRho(reversed, truth))
+
+	// One adjacent swap: 9 of 10 pairs agree, and two items move by one place
+	swapped := []string{"b", "a", "c", "d", "e"}
+	require.InDelta(t, 0.8, KendallTau(swapped, truth), 1e-9)
+	require.InDelta(t, 1-6*2.0/(5*24), SpearmanRho(swapped, truth), 1e-9)
+
+	// Items only one ranking knows about are ignored
+	require.Equal(t, 1.0, KendallTau([]string{"a", "x", "b", "c"}, []string{"a", "b", "y", "c"}))
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// RubricEntry is one judge's rubric scores for one team, keyed by criterion ID
+type RubricEntry struct {
+	JudgeID string
+	TeamID  string
+	Scores  map[string]float64
+}
+
+// RubricTeamScore is a team's standing on a single criterion or overall
+type RubricTeamScore struct {
+	TeamID  string  `json:"teamID"`
+	Rank    int     `json:"rank"`
+	Score   float64 `json:"score"`   // mean judge-normalized z-score
+	RawMean float64 `json:"rawMean"` // mean of the scores as submitted
+	Count   int     `json:"count"`   // number of judges who scored the team
+}
+
+// RubricRankings holds per-criterion rankings and the weighted overall ranking
+type RubricRankings struct {
+	Criteria map[string][]RubricTeamScore `json:"criteria"`
+	Overall  []RubricTeamScore            `json:"overall"`
+}
+
+// RankRubric ranks teams on every weighted criterion. Scores are standardized
+// per judge and criterion first, so a lenient judge and a harsh judge who agree
+// on the order contribute the same; a judge whose scores on a criterion don't
+// vary contributes zero. The overall score is the weight-averaged criterion score.
+func RankRubric(entries []RubricEntry, weights map[string]float64) RubricRankings {
+	rankings := RubricRankings{
+		Criteria: map[string][]RubricTeamScore{},
+		Overall:  []RubricTeamScore{},
+	}
+
+	totalWeight := 0.0
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+
+	overallSum := map[string]float64{}
+	overallRaw := map[string]float64{}
+	overallCount := map[string]int{}
+
+	for _, criterionID := range sortedKeys(weights) {
+		z := standardizeByJudge(entries, criterionID)
+
+		zSum := map[string]float64{}
+		rawSum := map[string]float64{}
+		count := map[string]int{}
+		for i, entry := range entries {
+			raw, ok := entry.Scores[criterionID]
+			if !ok {
+				continue
+			}
+			zSum[entry.TeamID] += z[i]
+			rawSum[entry.TeamID] += raw
+			count[entry.TeamID]++
+		}
+
+		scores := []RubricTeamScore{}
+		for teamID, n := range count {
+			score := RubricTeamScore{
+				TeamID:  teamID,
+				Score:   zSum[teamID] / float64(n),
+				RawMean: rawSum[teamID] / float64(n),
+				Count:   n,
+			}
+			scores = append(scores, score)
+
+			if totalWeight > 0 {
+				overallSum[teamID] += weights[criterionID] * score.Score / totalWeight
+				overallRaw[teamID] += weights[criterionID] * score.RawMean / totalWeight
+			}
+			if n > overallCount[teamID] {
+				overallCount[teamID] = n
+			}
+		}
+		rankings.Criteria[criterionID] = rankRubricScores(scores)
+	}
+
+	for teamID, n := range overallCount {
+		rankings.Overall = append(rankings.Overall, RubricTeamScore{
+			TeamID:  teamID,
+			Score:   overallSum[teamID],
+			RawMean: overallRaw[teamID],
+			Count:   n,
+		})
+	}
+	rankings.Overall = rankRubricScores(rankings.Overall)
+
+	return rankings
+}
+
+// standardizeByJudge returns, for each entry, the z-score of its criterion score
+// among all scores the same judge gave on that criterion
+func standardizeByJudge(entries []RubricEntry, criterionID string) []float64 {
+	type moments struct {
+		sum, sumSq float64
+		n          int
+	}
+
+	byJudge := map[string]*moments{}
+	for _, entry := range entries {
+		raw, ok := entry.Scores[criterionID]
+		if !ok {
+			continue
+		}
+		m := byJudge[entry.JudgeID]
+		if m == nil {
+			m = &moments{}
+			byJudge[entry.JudgeID] = m
+		}
+		m.sum += raw
+		m.sumSq += raw * raw
+		m.n++
+	}
+
+	z := make([]float64, len(entries))
+	for i, entry := range entries {
+		raw, ok := entry.Scores[criterionID]
+		if !ok {
+			continue
+		}
+		m := byJudge[entry.JudgeID]
+		mean := m.sum / float64(m.n)
+		variance := m.sumSq/float64(m.n) - mean*mean
+		if variance <= 1e-12 {
+			continue
+		}
+		z[i] = (raw - mean) / math.Sqrt(variance)
+	}
+
+	return z
+}
+
+// rankRubricScores sorts by score descending, then raw mean and team ID, and assigns ranks
+func rankRubricScores(scores []RubricTeamScore) []RubricTeamScore {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		if scores[i].RawMean != scores[j].RawMean {
+			return scores[i].RawMean > scores[j].RawMean
+		}
+		return scores[i].TeamID < scores[j].TeamID
+	})
+
+	for i := range scores {
+		scores[i].Rank = i + 1
+	}
+
+	return scores
+}
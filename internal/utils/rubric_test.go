@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRankRubricNormalizesJudges tests that judge leniency does not distort rubric rankings
+func TestRankRubricNormalizesJudges(t *testing.T) {
+	// The lenient judge scores everything high and sees the weakest team only,
+	// so raw means would favour team c; after standardization a > b > c.
+	entries := []RubricEntry{
+		{JudgeID: "harsh", TeamID: "a", Scores: map[string]float64{"design": 3, "tech": 4}},
+		{JudgeID: "harsh", TeamID: "b", Scores: map[string]float64{"design": 2, "tech": 2}},
+		{JudgeID: "harsh", TeamID: "c", Scores: map[string]float64{"design": 1, "tech": 1}},
+		{JudgeID: "lenient", TeamID: "b", Scores: map[string]float64{"design": 10, "tech": 10}},
+		{JudgeID: "lenient", TeamID: "c", Scores: map[string]float64{"design": 9, "tech": 9}},
+	}
+
+	rankings := RankRubric(entries, map[string]float64{"design": 1, "tech": 3})
+
+	require.Len(t, rankings.Criteria, 2)
+	for criterionID, scores := range rankings.Criteria {
+		require.Len(t, scores, 3, "criterion %s", criterionID)
+		require.Equal(t, []string{"a", "b", "c"}, rubricTeamOrder(scores), "criterion %s", criterionID)
+	}
+
+	require.Equal(t, []string{"a", "b", "c"}, rubricTeamOrder(rankings.Overall))
+	require.Equal(t, 2, rankings.Overall[1].Count)
+	require.InDelta(t, (1*6.0+3*6.0)/4, rankings.Overall[1].RawMean, 1e-9, "overall raw mean should be weight-averaged")
+
+	// A judge with no spread on a criterion contributes nothing
+	flat := RankRubric([]RubricEntry{
+		{JudgeID: "j", TeamID: "a", Scores: map[string]float64{"design": 5}},
+		{JudgeID: "j", TeamID: "b", Scores: map[string]float64{"design": 5}},
+	}, map[string]float64{"design": 1})
+	for _, score := range flat.Overall {
+		require.Equal(t, 0.0, score.Score)
+	}
+}
+
+func rubricTeamOrder(scores []RubricTeamScore) []string {
+	order := []string{}
+	for i, score := range scores {
+		if score.Rank != i+1 {
+			return nil
+		}
+		order = append(order, score.TeamID)
+	}
+	return order
+}
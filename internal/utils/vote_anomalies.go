@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// VoteBucketCount is how many votes a team received within one time bucket
+type VoteBucketCount struct {
+	Bucket time.Time
+	TeamID string
+	Votes  int64
+}
+
+// AccountActivityBucket counts the account events within one time bucket. Only
+// counts are kept, so activity can't be traced back to a voter.
+type AccountActivityBucket struct {
+	Bucket        time.Time `json:"bucket"`
+	Registrations int64     `json:"registrations"`
+	Logins        int64     `json:"logins"`
+	FailedLogins  int64     `json:"failedLogins"`
+}
+
+// VoteAnomalyOptions configures when a bucket counts as a burst
+type VoteAnomalyOptions struct {
+	MinVotes       int64   `json:"minVotes"`       // buckets with fewer votes for the team are never flagged (default 5)
+	BurstFactor    float64 `json:"burstFactor"`    // flag when a team gets this many times its usual votes per bucket (default 3)
+	ActivityFactor float64 `json:"activityFactor"` // account activity this many times its usual level counts as a spike (default 3)
+}
+
+// VoteBucketSummary is one bucket of a voting session's timeline
+type VoteBucketSummary struct {
+	Bucket        time.Time `json:"bucket"`
+	Votes         int64     `json:"votes"`
+	Registrations int64     `json:"registrations"`
+	Logins        int64     `json:"logins"`
+	FailedLogins  int64     `json:"failedLogins"`
+}
+
+// VoteBurst is a bucket in which a team received far more votes than usual
+type VoteBurst struct {
+	Bucket        time.Time `json:"bucket"`
+	TeamID        string    `json:"teamID"`
+	Votes         int64     `json:"votes"`
+	Expected      float64   `json:"expected"`      // the team's mean votes over the other buckets
+	Ratio         float64   `json:"ratio"`         // votes over expected, with expected taken as at least 1
+	BucketShare   float64   `json:"bucketShare"`   // share of the bucket's votes that went to the team
+	Registrations int64     `json:"registrations"` // in this bucket and the one before
+	Logins        int64     `json:"logins"`        // in this bucket and the one before
+	ActivitySpike bool      `json:"activitySpike"` // registrations and logins around the bucket were ActivityFactor times their usual level
+}
+
+// VoteAnomalyReport is a voting session's timeline along with its bursts
+type VoteAnomalyReport struct {
+	Buckets []VoteBucketSummary `json:"buckets"`
+	Bursts  []VoteBurst         `json:"bursts"`
+}
+
+// DefaultVoteAnomalyOptions returns thresholds that only flag clear bursts
+func DefaultVoteAnomalyOptions() VoteAnomalyOptions {
+	return VoteAnomalyOptions{
+		MinVotes:       5,
+		BurstFactor:    3,
+		ActivityFactor: 3,
+	}
+}
+
+// Validate reports whether the options can be used for an analysis
+func (o VoteAnomalyOptions) Validate() error {
+	switch {
+	case o.MinVotes < 1:
+		return errors.New("minVotes must be positive")
+	case o.BurstFactor < 1:
+		return errors.New("burstFactor must be at least 1")
+	case o.ActivityFactor < 1:
+		return errors.New("activityFactor must be at least 1")
+	}
+
+	return nil
+}
+
+// VoteBuckets lists the start of every bucket of the given size from the one
+// holding from up to the one holding to
+func VoteBuckets(from, to time.Time, size time.Duration) []time.Time {
+	var buckets []time.Time
+	for bucket := from.Truncate(size); !bucket.After(to); bucket = bucket.Add(size) {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// AnalyzeVoteAnomalies flags buckets in which a team received at least
+// MinVotes votes and BurstFactor times its mean over the other buckets, so a
+// burst cannot raise its own baseline. Each burst is set against the account
+// registrations and logins in its bucket and the one before, since accounts
+// created or taken over to vote log in just before voting. Counts outside the
+// given buckets are ignored. Bursts are sorted by bucket, then team.
+func AnalyzeVoteAnomalies(buckets []time.Time, counts []VoteBucketCount, activity []AccountActivityBucket, opts VoteAnomalyOptions) VoteAnomalyReport {
+	index := make(map[int64]int, len(buckets))
+	for i, bucket := range buckets {
+		index[bucket.UnixMilli()] = i
+	}
+
+	report := VoteAnomalyReport{
+		Buckets: make([]VoteBucketSummary, len(buckets)),
+		Bursts:  []VoteBurst{},
+	}
+	for i, bucket := range buckets {
+		report.Buckets[i].Bucket = bucket
+	}
+
+	for _, a := range activity {
+		i, ok := index[a.Bucket.UnixMilli()]
+		if !ok {
+			continue
+		}
+		report.Buckets[i].Registrations += a.Registrations
+		report.Buckets[i].Logins += a.Logins
+		report.Buckets[i].FailedLogins += a.FailedLogins
+	}
+
+	byTeam := make(map[string][]int64)
+	for _, c := range counts {
+		i, ok := index[c.Bucket.UnixMilli()]
+		if !ok {
+			continue
+		}
+		if byTeam[c.TeamID] == nil {
+			byTeam[c.TeamID] = make([]int64, len(buckets))
+		}
+		byTeam[c.TeamID][i] += c.Votes
+		report.Buckets[i].Votes += c.Votes
+	}
+
+	// Account activity is looked at over a bucket and the one before it
+	window := make([]int64, len(buckets))
+	var windowTotal int64
+	for i := range buckets {
+		window[i] = report.Buckets[i].Registrations + report.Buckets[i].Logins
+		if i > 0 {
+			window[i] += report.Buckets[i-1].Registrations + report.Buckets[i-1].Logins
+		}
+		windowTotal += window[i]
+	}
+
+	teamIDs := make([]string, 0, len(byTeam))
+	for teamID := range byTeam {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Strings(teamIDs)
+
+	for _, teamID := range teamIDs {
+		perBucket := byTeam[teamID]
+		var teamTotal int64
+		for _, votes := range perBucket {
+			teamTotal += votes
+		}
+
+		for i, votes := range perBucket {
+			if votes < opts.MinVotes {
+				continue
+			}
+
+			expected := 0.0
+			if len(buckets) > 1 {
+				expected = float64(teamTotal-votes) / float64(len(buckets)-1)
+			}
+			ratio := float64(votes) / math.Max(expected, 1)
+			if ratio < opts.BurstFactor {
+				continue
+			}
+
+			usualActivity := 0.0
+			if len(buckets) > 1 {
+				usualActivity = float64(windowTotal-window[i]) / float64(len(buckets)-1)
+			}
+
+			burst := VoteBurst{
+				Bucket:        buckets[i],
+				TeamID:        teamID,
+				Votes:         votes,
+				Expected:      expected,
+				Ratio:         ratio,
+				BucketShare:   float64(votes) / float64(report.Buckets[i].Votes),
+				Registrations: report.Buckets[i].Registrations,
+				Logins:        report.Buckets[i].Logins,
+				ActivitySpike: window[i] > 0 && float64(window[i]) >= opts.ActivityFactor*math.Max(usualActivity, 1),
+			}
+			if i > 0 {
+				burst.Registrations += report.Buckets[i-1].Registrations
+				burst.Logins += report.Buckets[i-1].Logins
+			}
+			report.Bursts = append(report.Bursts, burst)
+		}
+	}
+
+	sort.SliceStable(report.Bursts, func(a, b int) bool {
+		return report.Bursts[a].Bucket.Before(report.Bursts[b].Bucket)
+	})
+
+	return report
+}
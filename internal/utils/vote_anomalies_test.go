@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzeVoteAnomalies plants a burst for one team, right after a wave of
+// registrations, among steady voting for two teams
+func TestAnalyzeVoteAnomalies(t *testing.T) {
+	start := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+	buckets := VoteBuckets(start.Add(90*time.Second), start.Add(55*time.Minute), 5*time.Minute)
+	require.Len(t, buckets, 12)
+	require.Equal(t, start, buckets[0])
+
+	var counts []VoteBucketCount
+	var activity []AccountActivityBucket
+	for i, bucket := range buckets {
+		counts = append(counts,
+			VoteBucketCount{Bucket: bucket, TeamID: "team_a", Votes: 4},
+			VoteBucketCount{Bucket: bucket, TeamID: "team_b", Votes: 3},
+		)
+		activity = append(activity, AccountActivityBucket{Bucket: bucket, Logins: 2})
+		if i == 7 {
+			activity[i].Registrations = 20
+		}
+	}
+	counts = append(counts, VoteBucketCount{Bucket: buckets[8], TeamID: "team_b", Votes: 27})
+	// Votes outside the analyzed buckets are ignored
+	counts = append(counts, VoteBucketCount{Bucket: start.Add(-time.Hour), TeamID: "team_a", Votes: 100})
+
+	report := AnalyzeVoteAnomalies(buckets, counts, activity, DefaultVoteAnomalyOptions())
+
+	require.Len(t, report.Buckets, len(buckets))
+	require.Equal(t, int64(7), report.Buckets[0].Votes)
+	require.Equal(t, int64(34), report.Buckets[8].Votes)
+	require.Equal(t, int64(20), report.Buckets[7].Registrations)
+
+	require.Len(t, report.Bursts, 1)
+	burst := report.Bursts[0]
+	require.Equal(t, buckets[8], burst.Bucket)
+	require.Equal(t, "team_b", burst.TeamID)
+	require.Equal(t, int64(30), burst.Votes)
+	require.InDelta(t, 3.0, burst.Expected, 1e-9)
+	require.InDelta(t, 10.0, burst.Ratio, 1e-9)
+	require.InDelta(t, 30.0/34.0, burst.BucketShare, 1e-9)
+	require.Equal(t, int64(20), burst.Registrations, "registrations in the bucket before count towards the burst")
+	require.Equal(t, int64(4), burst.Logins)
+	require.True(t, burst.ActivitySpike)
+}
+
+// TestAnalyzeVoteAnomaliesThresholds checks that small or steady counts are
+// never flagged, and that a burst without account activity is not a spike
+func TestAnalyzeVoteAnomaliesThresholds(t *testing.T) {
+	start := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+	buckets := VoteBuckets(start, start.Add(15*time.Minute), 5*time.Minute)
+	require.Len(t, buckets, 4)
+
+	counts := []VoteBucketCount{
+		{Bucket: buckets[1], TeamID: "team_a", Votes: 4},
+		{Bucket: buckets[1], TeamID: "team_b", Votes: 6},
+		{Bucket: buckets[2], TeamID: "team_b", Votes: 6},
+		{Bucket: buckets[3], TeamID: "team_b", Votes: 6},
+	}
+
+	report := AnalyzeVoteAnomalies(buckets, counts, nil, DefaultVoteAnomalyOptions())
+	require.Empty(t, report.Bursts, "4 votes are below minVotes and team_b's votes are steady")
+
+	options := DefaultVoteAnomalyOptions()
+	options.MinVotes = 1
+	report = AnalyzeVoteAnomalies(buckets, counts, nil, options)
+	require.Len(t, report.Bursts, 1)
+	require.Equal(t, "team_a", report.Bursts[0].TeamID)
+	require.InDelta(t, 4.0, report.Bursts[0].Ratio, 1e-9, "an expected count below 1 is taken as 1")
+	require.False(t, report.Bursts[0].ActivitySpike)
+
+	require.Error(t, VoteAnomalyOptions{MinVotes: 0, BurstFactor: 3, ActivityFactor: 3}.Validate())
+	require.Error(t, VoteAnomalyOptions{MinVotes: 5, BurstFactor: 0.5, ActivityFactor: 3}.Validate())
+	require.NoError(t, DefaultVoteAnomalyOptions().Validate())
+}
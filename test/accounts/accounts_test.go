@@ -328,6 +328,35 @@ func TestAccountsGetFlags(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAccountsGetFlagsTargeted(t *testing.T) {
+	flags := models.Flags{}
+	require.NoError(t, flags.Get())
+	require.NoError(t, flags.Set("accountstesting_targeted", false, "accountstesting"))
+	require.NoError(t, flags.SetRule("accountstesting_targeted", models.FlagRule{AccountIDs: []string{testAccount.ID}}, "accountstesting"))
+	require.NoError(t, flags.SetRule("accountstesting_elsewhere", models.FlagRule{AccountIDs: []string{"someone_else"}}, "accountstesting"))
+	require.NoError(t, flags.SetRule("accountstesting_rollout", models.FlagRule{Percentage: 100}, "accountstesting"))
+
+	bodyBytes, statusCode := helpers.API_AccountsGetFlags(
+		t,
+		app,
+		testAccountToken,
+	)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var body models.Flags
+	err := json.Unmarshal(bodyBytes, &body)
+	require.NoError(t, err)
+
+	require.True(t, body.Flags["accountstesting_targeted"], "the rule turns the flag on for the account")
+	require.False(t, body.Flags["accountstesting_elsewhere"])
+	require.True(t, body.Flags["accountstesting_rollout"])
+	require.Empty(t, body.Rules, "rules are not shared with participants")
+
+	require.NoError(t, flags.Unset("accountstesting_targeted", "accountstesting"))
+	require.NoError(t, flags.Unset("accountstesting_elsewhere", "accountstesting"))
+	require.NoError(t, flags.Unset("accountstesting_rollout", "accountstesting"))
+}
+
 func TestAccountsCleanup(t *testing.T) {
 	err := testAccount.Delete()
 	if err != nil {
@@ -175,3 +175,55 @@ func API_AccountsVotingCastVote(
 		&token,
 	)
 }
+
+func API_AccountsVotingCastVoteInSession(
+	t *testing.T,
+	app *fiber.App,
+	teamID string,
+	sessionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		TeamID    string `json:"teamID"`
+		SessionID string `json:"sessionID"`
+	}{
+		TeamID:    teamID,
+		SessionID: sessionID,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/accounts/voting/vote",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_AccountsVotingCastBallot(
+	t *testing.T,
+	app *fiber.App,
+	choices []string,
+	sessionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Choices   []string `json:"choices"`
+		SessionID string   `json:"sessionID"`
+	}{
+		Choices:   choices,
+		SessionID: sessionID,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/accounts/voting/vote",
+		sendBytes,
+		&token,
+	)
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"slices"
+
+	"backend/internal/models"
+)
+
+// testFlagsConfig declares the flags only the integration tests use, so the
+// shipped flags_config.json doesn't have to
+//
+//go:embed testdata/flags_config.json
+var testFlagsConfig []byte
+
+// RegisterTestFlags adds the test flags to the registry SetupApp loaded
+func RegisterTestFlags() {
+	var fixture models.FlagRegistry
+	if err := json.Unmarshal(testFlagsConfig, &fixture); err != nil {
+		log.Fatalf("failed to read the test flag registry: %v", err)
+	}
+
+	combined := models.FlagRegistry{
+		Flags: append(slices.Clone(models.FlagsRegistry.Flags), fixture.Flags...),
+	}
+	data, err := json.Marshal(combined)
+	if err != nil {
+		log.Fatalf("failed to combine the flag registries: %v", err)
+	}
+
+	registry, err := models.LoadFlagRegistry(data)
+	if err != nil {
+		log.Fatalf("failed to load the test flag registry: %v", err)
+	}
+
+	models.FlagsRegistry = registry
+}
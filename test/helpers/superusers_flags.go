@@ -4,6 +4,7 @@ import (
 	"backend/internal/models"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/require"
@@ -70,6 +71,64 @@ func API_SuperUsersFlagsSetBulk(
 	)
 }
 
+func API_SuperUsersFlagsSetResolved(
+	t *testing.T,
+	app *fiber.App,
+	flag string,
+	value bool,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Flag  string `json:"flag"`
+		Value bool   `json:"value"`
+	}{
+		Flag:  flag,
+		Value: value,
+	}
+
+	// marshalling the payload into JSON
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/flags?resolve=true",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersFlagsSetBulkResolved(
+	t *testing.T,
+	app *fiber.App,
+	rawFlags map[string]bool,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	// marshalling the payload into JSON
+	sendBytes, err := json.Marshal(rawFlags)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/flags?resolve=true",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersFlagsRegistry(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/flags/registry",
+		[]byte{},
+		&token,
+	)
+}
+
 func API_SuperUsersFlagsReset(
 	t *testing.T,
 	app *fiber.App,
@@ -180,3 +239,172 @@ func API_SuperUsersFlagStagesExecute(
 		&token,
 	)
 }
+
+func API_SuperUsersFlagStageSchedulesGet(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/flagstages/schedules",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersFlagStageScheduleCreate(
+	t *testing.T,
+	app *fiber.App,
+	stageID string,
+	runAt time.Time,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		StageID string    `json:"stageID"`
+		RunAt   time.Time `json:"runAt"`
+	}{
+		StageID: stageID,
+		RunAt:   runAt,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/flagstages/schedules",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersFlagStageScheduleUpdate(
+	t *testing.T,
+	app *fiber.App,
+	id string,
+	runAt time.Time,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		ID    string    `json:"id"`
+		RunAt time.Time `json:"runAt"`
+	}{
+		ID:    id,
+		RunAt: runAt,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/flagstages/schedules",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersFlagStageScheduleCancel(
+	t *testing.T,
+	app *fiber.App,
+	id string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"DELETE",
+		"/superusers/flagstages/schedules?id="+id,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersFlagStagesDryRun(
+	t *testing.T,
+	app *fiber.App,
+	id string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/flagstages/dry-run?id="+id,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersFlagStageExecutionsGet(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/flagstages/executions",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersFlagStagesRollback(
+	t *testing.T,
+	app *fiber.App,
+	executionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/flagstages/rollback?id="+executionID,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersFlagRulesSet(
+	t *testing.T,
+	app *fiber.App,
+	flag string,
+	rule models.FlagRule,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Flag string `json:"flag"`
+		models.FlagRule
+	}{
+		Flag:     flag,
+		FlagRule: rule,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/flags/rules",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersFlagRulesUnset(
+	t *testing.T,
+	app *fiber.App,
+	flag string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Flag string `json:"flag"`
+	}{
+		Flag: flag,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"DELETE",
+		"/superusers/flags/rules",
+		sendBytes,
+		&token,
+	)
+}
@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func API_SuperUsersHistoryGet(
+	t *testing.T,
+	app *fiber.App,
+	kind string,
+	key string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	query := url.Values{}
+	query.Set("kind", kind)
+	query.Set("key", key)
+
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/history?"+query.Encode(),
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersHistoryRestore(
+	t *testing.T,
+	app *fiber.App,
+	at time.Time,
+	kind string,
+	keys []string,
+	dryRun bool,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		At     time.Time `json:"at"`
+		Kind   string    `json:"kind"`
+		Keys   []string  `json:"keys"`
+		DryRun bool      `json:"dryRun"`
+	}{
+		At:     at,
+		Kind:   kind,
+		Keys:   keys,
+		DryRun: dryRun,
+	}
+
+	// marshalling the payload into JSON
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/history/restore",
+		sendBytes,
+		&token,
+	)
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"backend/internal/models"
 	"encoding/json"
 	"testing"
 
@@ -21,6 +22,34 @@ func API_SuperUsersJudgingInit(
 	)
 }
 
+func API_SuperUsersJudgingInitWithSeed(
+	t *testing.T,
+	app *fiber.App,
+	seed string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/judging/init?seed="+seed,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingReplan(
+	t *testing.T,
+	app *fiber.App,
+	mode string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/judging/init?mode="+mode,
+		[]byte{},
+		&token,
+	)
+}
+
 func API_SuperUsersJudgingCreate(
 	t *testing.T,
 	app *fiber.App,
@@ -170,3 +199,445 @@ func API_SuperUsersJudgingComputeRankings(
 		&token,
 	)
 }
+
+func API_SuperUsersJudgingRankStability(
+	t *testing.T,
+	app *fiber.App,
+	query string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/rank-stability"+query,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingLeaderboard(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/leaderboard",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_JudgeRetractJudgment(
+	t *testing.T,
+	app *fiber.App,
+	judgmentID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"id": judgmentID,
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"DELETE",
+		"/judge/judgment",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingJudgments(
+	t *testing.T,
+	app *fiber.App,
+	query string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/judgments"+query,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingInvalidateJudgment(
+	t *testing.T,
+	app *fiber.App,
+	judgmentID string,
+	reason string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"id":     judgmentID,
+		"reason": reason,
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/judging/judgment/invalidate",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_JudgeRubric(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/judge/rubric",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_JudgeSubmitRubricScore(
+	t *testing.T,
+	app *fiber.App,
+	scores map[string]int,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]any{
+		"scores": scores,
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/judge/rubric-score",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingRubric(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/rubric",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingUpdateRubric(
+	t *testing.T,
+	app *fiber.App,
+	rubric models.Rubric,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(rubric)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/judging/rubric",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingRubricRankings(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/rubric-rankings",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_JudgePreviousTeam(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/judge/previous-team",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_JudgeSaveNote(
+	t *testing.T,
+	app *fiber.App,
+	teamID string,
+	text string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"teamID": teamID,
+		"text":   text,
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/judge/notes",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingNotes(
+	t *testing.T,
+	app *fiber.App,
+	query string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/notes"+query,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_JudgeSkip(
+	t *testing.T,
+	app *fiber.App,
+	reason string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"reason": reason,
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/judge/skip",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingSkips(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/skips",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingDashboard(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/dashboard",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingOutliers(
+	t *testing.T,
+	app *fiber.App,
+	query string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/judge-outliers"+query,
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingSetJudgeWeight(
+	t *testing.T,
+	app *fiber.App,
+	judgeID string,
+	weight float64,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		JudgeID string  `json:"judgeID"`
+		Weight  float64 `json:"weight"`
+	}{
+		JudgeID: judgeID,
+		Weight:  weight,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/judging/judge-weight",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingFinalists(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/finalists",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingUpdateFinalists(
+	t *testing.T,
+	app *fiber.App,
+	count *int,
+	teamIDs []string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Count *int     `json:"count,omitempty"`
+		Teams []string `json:"teams,omitempty"`
+	}{
+		Count: count,
+		Teams: teamIDs,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/judging/finalists",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingLockFinalists(
+	t *testing.T,
+	app *fiber.App,
+	locked bool,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Locked bool `json:"locked"`
+	}{
+		Locked: locked,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/judging/finalists/lock",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingDeclareConflict(
+	t *testing.T,
+	app *fiber.App,
+	judgeID string,
+	teamID string,
+	university string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"judgeID":    judgeID,
+		"teamID":     teamID,
+		"university": university,
+		"reason":     "test conflict",
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/judging/conflicts",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingConflicts(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/judging/conflicts",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_SuperUsersJudgingDeleteConflict(
+	t *testing.T,
+	app *fiber.App,
+	conflictID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"id": conflictID,
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"DELETE",
+		"/superusers/judging/conflicts",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_JudgeConflicts(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/judge/conflicts",
+		[]byte{},
+		&token,
+	)
+}
+
+func API_JudgeDeclareConflict(
+	t *testing.T,
+	app *fiber.App,
+	teamID string,
+	university string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	sendBytes, err := json.Marshal(map[string]string{
+		"teamID":     teamID,
+		"university": university,
+		"reason":     "test conflict",
+	})
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/judge/conflicts",
+		sendBytes,
+		&token,
+	)
+}
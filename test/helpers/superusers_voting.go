@@ -0,0 +1,272 @@
+package helpers
+
+import (
+	"backend/internal/models"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func API_SuperUsersVotingSessions(
+	t *testing.T,
+	app *fiber.App,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/voting/sessions",
+		nil,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingCreateSession(
+	t *testing.T,
+	app *fiber.App,
+	name string,
+	mode string,
+	opensAt time.Time,
+	closesAt time.Time,
+	finalists []string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Name      string    `json:"name"`
+		Mode      string    `json:"mode,omitempty"`
+		OpensAt   time.Time `json:"opensAt"`
+		ClosesAt  time.Time `json:"closesAt"`
+		Finalists []string  `json:"finalists,omitempty"`
+	}{
+		Name:      name,
+		Mode:      mode,
+		OpensAt:   opensAt,
+		ClosesAt:  closesAt,
+		Finalists: finalists,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/voting/sessions",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingCreateSessionWithEligibility(
+	t *testing.T,
+	app *fiber.App,
+	name string,
+	opensAt time.Time,
+	closesAt time.Time,
+	finalists []string,
+	eligibility models.VotingEligibility,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		Name        string                   `json:"name"`
+		OpensAt     time.Time                `json:"opensAt"`
+		ClosesAt    time.Time                `json:"closesAt"`
+		Finalists   []string                 `json:"finalists,omitempty"`
+		Eligibility models.VotingEligibility `json:"eligibility"`
+	}{
+		Name:        name,
+		OpensAt:     opensAt,
+		ClosesAt:    closesAt,
+		Finalists:   finalists,
+		Eligibility: eligibility,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/voting/sessions",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingUpdateSession(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	opensAt *time.Time,
+	closesAt *time.Time,
+	finalists []string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		ID        string     `json:"id"`
+		OpensAt   *time.Time `json:"opensAt,omitempty"`
+		ClosesAt  *time.Time `json:"closesAt,omitempty"`
+		Finalists []string   `json:"finalists,omitempty"`
+	}{
+		ID:        sessionID,
+		OpensAt:   opensAt,
+		ClosesAt:  closesAt,
+		Finalists: finalists,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/voting/sessions",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingResults(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/voting/results?sessionID="+sessionID,
+		nil,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingTally(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/voting/tally?sessionID="+sessionID,
+		nil,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingTallyStream(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/voting/tally/stream?sessionID="+sessionID,
+		nil,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingTallyStreamToken(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/voting/tally/stream-token?sessionID="+sessionID,
+		nil,
+		&token,
+	)
+}
+
+// API_SuperUsersVotingTallyStreamWithToken opens the stream the way a browser's
+// EventSource does, with the stream token in the query and no Authorization header
+func API_SuperUsersVotingTallyStreamWithToken(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	streamToken string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/voting/tally/stream?sessionID="+sessionID+"&token="+url.QueryEscape(streamToken),
+		nil,
+		nil,
+	)
+}
+
+func API_SuperUsersVotingSetTallyHidden(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	hidden bool,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		ID                  string `json:"id"`
+		HideTallyUntilClose bool   `json:"hideTallyUntilClose"`
+	}{
+		ID:                  sessionID,
+		HideTallyUntilClose: hidden,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"PUT",
+		"/superusers/voting/sessions",
+		sendBytes,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingAnomalies(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	query string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	return RequestRunner(t, app,
+		"GET",
+		"/superusers/voting/anomalies?sessionID="+sessionID+query,
+		nil,
+		&token,
+	)
+}
+
+func API_SuperUsersVotingVoidVotes(
+	t *testing.T,
+	app *fiber.App,
+	sessionID string,
+	bucket time.Time,
+	teamID string,
+	justification string,
+	token string,
+) (bodyBytes []byte, statusCode int) {
+	payload := struct {
+		SessionID     string    `json:"sessionID"`
+		Bucket        time.Time `json:"bucket"`
+		TeamID        string    `json:"teamID,omitempty"`
+		Justification string    `json:"justification"`
+	}{
+		SessionID:     sessionID,
+		Bucket:        bucket,
+		TeamID:        teamID,
+		Justification: justification,
+	}
+
+	sendBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return RequestRunner(t, app,
+		"POST",
+		"/superusers/voting/voids",
+		sendBytes,
+		&token,
+	)
+}
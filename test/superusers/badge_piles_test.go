@@ -183,7 +183,7 @@ func TestBadgePilesCleanup(t *testing.T) {
 	if badgeOriginalSaltExists {
 		fmt.Printf("Original badge pile salt before test: %s\n", badgeOriginalSetting.Value)
 		// Restore original setting
-		_ = badgeOriginalSetting.Update()
+		_ = badgeOriginalSetting.Update(env.SUPERUSER_USERNAME)
 	} else {
 		t.Log("No badge pile salt existed prior to test; keeping newly generated value")
 	}
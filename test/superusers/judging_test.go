@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -29,6 +31,7 @@ var (
 		NumPairGroups      int     `json:"numPairGroups"`
 		NumSteps           int     `json:"numSteps"`
 		Collisions         int     `json:"collisions"`
+		Seed               int64   `json:"seed"`
 		GavelScore         float64 `json:"gavelScore"`
 		UniquePairs        int     `json:"uniquePairs"`
 		TotalPossiblePairs int     `json:"totalPossiblePairs"`
@@ -41,6 +44,8 @@ var (
 		Assignments   int        `json:"assignments"`
 		BlankCells    int        `json:"blankCells"`
 		Collisions    int        `json:"collisions"`
+		Seed          int64      `json:"seed"`
+		Conflicts     int        `json:"conflicts"`
 		GavelScore    float64    `json:"gavelScore"`
 		UniquePairs   int        `json:"uniquePairs"`
 		TotalPairs    int        `json:"totalPairs"`
@@ -51,6 +56,7 @@ var (
 	createdPairingAccounts   []models.Account
 	createdPairingTeams      []models.Team
 	createdPairingJudgesByID map[string]*models.Judge
+	pairingConflicts         map[string]string // judgeID -> teamID kept out of the judge's group
 )
 
 // Configuration for judge pairing test (customize here)
@@ -293,6 +299,66 @@ func TestJudgingPairsFormTeams(t *testing.T) {
 	fmt.Printf("Created %d teams: %d teams of 3 + %d teams of 4 members\n", len(createdPairingTeams), numTeamsSize3, numTeamsSize4)
 }
 
+// TestJudgingPairsConflicts declares conflicts of interest before the matrix is built
+func TestJudgingPairsConflicts(t *testing.T) {
+	require.Len(t, createdPairingJudges, totalPairingJudges)
+	require.Len(t, createdPairingTeams, numPairingTeams)
+
+	teamJudge := createdPairingJudges[0]
+	universityJudge := createdPairingJudges[len(createdPairingJudges)-1]
+	require.NotEqual(t, teamJudge.Pair, universityJudge.Pair, "conflict judges should be in different groups")
+
+	conflictTeam := createdPairingTeams[0]
+	universityTeam := createdPairingTeams[2]
+
+	_, statusCode := helpers.API_SuperUsersJudgingDeclareConflict(t, app, teamJudge.ID, conflictTeam.ID, "", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersJudgingDeclareConflict(t, app, teamJudge.ID, conflictTeam.ID, "", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusConflict, statusCode, "a conflict can only be declared once")
+
+	_, statusCode = helpers.API_SuperUsersJudgingDeclareConflict(t, app, teamJudge.ID, conflictTeam.ID, "Test Pair University", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "a conflict names a team or a university, not both")
+
+	_, statusCode = helpers.API_SuperUsersJudgingDeclareConflict(t, app, "no_such_judge", conflictTeam.ID, "", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusNotFound, statusCode)
+
+	// University conflicts apply to every team with a member from that university
+	_, err := db.Accounts.UpdateOne(db.Ctx,
+		bson.M{"id": universityTeam.Members[0]},
+		bson.M{"$set": bson.M{"university": "Test Pair University"}},
+	)
+	require.NoError(t, err)
+
+	_, statusCode = helpers.API_SuperUsersJudgingDeclareConflict(t, app, universityJudge.ID, "", "  test pair  university ", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// A removed conflict no longer constrains the matrix
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingDeclareConflict(t, app, teamJudge.ID, createdPairingTeams[1].ID, "", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var removed models.JudgeConflict
+	require.NoError(t, json.Unmarshal(bodyBytes, &removed))
+
+	_, statusCode = helpers.API_SuperUsersJudgingDeleteConflict(t, app, removed.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersJudgingDeleteConflict(t, app, removed.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusNotFound, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingConflicts(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var conflicts []models.JudgeConflict
+	require.NoError(t, json.Unmarshal(bodyBytes, &conflicts))
+	require.Len(t, conflicts, 2)
+
+	pairingConflicts = map[string]string{
+		teamJudge.ID:       conflictTeam.ID,
+		universityJudge.ID: universityTeam.ID,
+	}
+}
+
 // TestJudgingPairsInitialize runs the new pairing-based judging initialization
 func TestJudgingPairsInitialize(t *testing.T) {
 	require.NotEmpty(t, pairingTestSuperUserToken, "superuser token should be initialized")
@@ -334,6 +400,22 @@ func TestJudgingPairsInitialize(t *testing.T) {
 
 	require.NoError(t, json.Unmarshal([]byte(matrixSetting.Value.(string)), &pairingInitMatrix))
 
+	// Declared conflicts keep the team away from the judge's whole group
+	judgeToGroupIndexSetting := &models.Setting{Name: models.SettingJudgeToGroupIndex}
+	require.Equal(t, errmsg.EmptyStatusError, judgeToGroupIndexSetting.Get())
+
+	var judgeToGroupIdx map[string]int
+	require.NoError(t, json.Unmarshal([]byte(judgeToGroupIndexSetting.Value.(string)), &judgeToGroupIdx))
+
+	require.Equal(t, len(pairingConflicts), pairingInitMatrix.Conflicts)
+	for judgeID, teamID := range pairingConflicts {
+		groupIdx := judgeToGroupIdx[judgeID]
+		for step := 0; step < pairingInitMatrix.Steps; step++ {
+			require.NotEqual(t, teamID, pairingInitMatrix.Matrix[step][groupIdx],
+				"group %d should never visit conflicted team %s", groupIdx, teamID)
+		}
+	}
+
 	// Log matrix in compact format
 	fmt.Printf("\n========================================\n")
 	fmt.Printf("      LATIN RECTANGLE ASSIGNMENTS\n")
@@ -408,6 +490,370 @@ func TestJudgingPairsInitialize(t *testing.T) {
 	fmt.Printf("\n")
 }
 
+// TestJudgingPairsSeededInit re-runs initialization with the recorded seed and
+// expects the exact same matrix back
+func TestJudgingPairsSeededInit(t *testing.T) {
+	require.NotEmpty(t, pairingInitMatrix.Matrix, "matrix should be initialized")
+	require.Equal(t, pairingInitResp.Seed, pairingInitMatrix.Seed)
+
+	_, statusCode := helpers.API_SuperUsersJudgingInitWithSeed(t, app, "not-a-number", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	seed := strconv.FormatInt(pairingInitMatrix.Seed, 10)
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingInitWithSeed(t, app, seed, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var seededResp struct {
+		Seed int64 `json:"seed"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &seededResp))
+	require.Equal(t, pairingInitMatrix.Seed, seededResp.Seed)
+
+	matrixSetting := &models.Setting{Name: models.SettingJudgeInitMatrix}
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Get())
+
+	var seededMatrix struct {
+		Seed   int64      `json:"seed"`
+		Matrix [][]string `json:"matrix"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(matrixSetting.Value.(string)), &seededMatrix))
+	require.Equal(t, pairingInitMatrix.Seed, seededMatrix.Seed)
+	require.Equal(t, pairingInitMatrix.Matrix, seededMatrix.Matrix, "same seed and roster should rebuild the same matrix")
+}
+
+// TestJudgingPairsReinit starts judging over after a judge has made progress
+// and expects the judge to follow the new matrix from the first step
+func TestJudgingPairsReinit(t *testing.T) {
+	require.NotEmpty(t, pairingInitMatrix.Matrix, "matrix should be initialized")
+
+	judge := createdPairingJudges[0]
+	oldTeam := createdPairingTeams[0].ID
+	_, err := db.Judges.UpdateOne(db.Ctx, bson.M{"id": judge.ID}, bson.M{"$set": bson.M{
+		"currentTeam": 3,
+		"visits":      []models.JudgeVisit{{Step: 2, TeamID: oldTeam}, {Step: 3, TeamID: oldTeam, Retry: true}},
+		"retryQueue":  []string{createdPairingTeams[1].ID},
+		"retryTeam":   oldTeam,
+	}})
+	require.NoError(t, err)
+
+	seed := strconv.FormatInt(pairingInitMatrix.Seed+1, 10)
+	_, statusCode := helpers.API_SuperUsersJudgingInitWithSeed(t, app, seed, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	reset := models.Judge{ID: judge.ID}
+	require.NoError(t, reset.Get())
+	require.Equal(t, -1, reset.CurrentTeam)
+	require.Empty(t, reset.Visits)
+	require.Empty(t, reset.RetryQueue)
+	require.Empty(t, reset.RetryTeam)
+
+	matrixSetting := &models.Setting{Name: models.SettingJudgeInitMatrix}
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Get())
+	var reinitMatrix struct {
+		Matrix [][]string `json:"matrix"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(matrixSetting.Value.(string)), &reinitMatrix))
+
+	judgeToGroupIndexSetting := &models.Setting{Name: models.SettingJudgeToGroupIndex}
+	require.Equal(t, errmsg.EmptyStatusError, judgeToGroupIndexSetting.Get())
+	var judgeToGroupIdx map[string]int
+	require.NoError(t, json.Unmarshal([]byte(judgeToGroupIndexSetting.Value.(string)), &judgeToGroupIdx))
+	column := judgeToGroupIdx[judge.ID]
+
+	// Walk the first two steps; each lands on the new matrix, and the previous
+	// team is the last one visited in it
+	previous := ""
+	for step := 0; step < 2; step++ {
+		teamID, serr := reset.GetNextTeam()
+		require.Equal(t, errmsg.EmptyStatusError, serr)
+		require.Equal(t, reinitMatrix.Matrix[step][column], teamID, "step %d", step)
+
+		currentTeamID, serr := reset.GetCurrentTeamID()
+		if teamID == "" {
+			require.Equal(t, errmsg.JudgeResting, serr)
+		} else {
+			require.Equal(t, errmsg.EmptyStatusError, serr)
+			require.Equal(t, teamID, currentTeamID)
+		}
+
+		previousTeamID, serr := reset.GetPreviousTeam()
+		if previous == "" {
+			require.Equal(t, errmsg.JudgeResting, serr)
+		} else {
+			require.Equal(t, errmsg.EmptyStatusError, serr)
+			require.Equal(t, previous, previousTeamID)
+		}
+		if teamID != "" {
+			previous = teamID
+		}
+	}
+
+	// Back to the original matrix, which starts everyone over again
+	seed = strconv.FormatInt(pairingInitMatrix.Seed, 10)
+	_, statusCode = helpers.API_SuperUsersJudgingInitWithSeed(t, app, seed, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	require.NoError(t, reset.Get())
+	require.Equal(t, -1, reset.CurrentTeam)
+	require.Empty(t, reset.Visits)
+}
+
+// TestJudgingPairsReinitAfterJudging judges a step, initializes judging again
+// and judges the same step of the new plan
+func TestJudgingPairsReinitAfterJudging(t *testing.T) {
+	require.NotEmpty(t, pairingInitMatrix.Matrix, "matrix should be initialized")
+
+	// Judgments are only accepted once the wait window has passed, so skip it
+	waitMinutesSetting := &models.Setting{Name: models.SettingWaitMinutes, Value: "0"}
+	require.Equal(t, errmsg.EmptyStatusError, waitMinutesSetting.Save(env.SUPERUSER_USERNAME))
+
+	judge := models.Judge{ID: createdPairingJudges[0].ID}
+	require.NoError(t, judge.Get())
+
+	// judgeFirstStep walks the judge to the first step with a previous team to
+	// compare against and judges it
+	judgeFirstStep := func() models.Judgment {
+		for {
+			teamID, serr := judge.GetNextTeam()
+			require.Equal(t, errmsg.EmptyStatusError, serr)
+			if teamID == "" {
+				continue
+			}
+			previousTeamID, serr := judge.GetPreviousTeam()
+			if serr != errmsg.EmptyStatusError {
+				continue
+			}
+
+			judgment := models.Judgment{WinningTeamID: teamID, LosingTeamID: previousTeamID}
+			require.Equal(t, errmsg.EmptyStatusError, judgment.Validate(judge))
+			require.NoError(t, judgment.Create())
+			return judgment
+		}
+	}
+
+	first := judgeFirstStep()
+
+	seed := strconv.FormatInt(pairingInitMatrix.Seed, 10)
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingInitWithSeed(t, app, seed, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var initResp struct {
+		ArchivedJudgments int64 `json:"archivedJudgments"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &initResp))
+	require.Equal(t, int64(1), initResp.ArchivedJudgments)
+
+	archived := models.Judgment{ID: first.ID}
+	require.NoError(t, archived.Get())
+	require.Equal(t, models.JudgmentStatusArchived, archived.Status)
+	require.Equal(t, models.JudgmentStatusArchived, archived.History[len(archived.History)-1].Status)
+
+	// the same seed gives the same plan, so the judge judges the same step again
+	require.Equal(t, errmsg.EmptyStatusError, waitMinutesSetting.Save(env.SUPERUSER_USERNAME))
+	require.NoError(t, judge.Get())
+	second := judgeFirstStep()
+	require.Equal(t, first.Step, second.Step)
+
+	_, err := db.Judgments.DeleteMany(db.Ctx, bson.M{"id": bson.M{"$in": []string{first.ID, second.ID}}})
+	require.NoError(t, err)
+
+	// Start everyone over on the original matrix
+	_, statusCode = helpers.API_SuperUsersJudgingInitWithSeed(t, app, seed, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+}
+
+// TestJudgingPairsReplan re-plans the matrix after a judge has made progress
+func TestJudgingPairsReplan(t *testing.T) {
+	require.NotEmpty(t, pairingInitMatrix.Matrix, "matrix should be initialized")
+
+	_, statusCode := helpers.API_SuperUsersJudgingReplan(t, app, "reshuffle", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "unknown init modes should be rejected")
+
+	judgeToGroupIndexSetting := &models.Setting{Name: models.SettingJudgeToGroupIndex}
+	require.Equal(t, errmsg.EmptyStatusError, judgeToGroupIndexSetting.Get())
+
+	var judgeToGroupIdx map[string]int
+	require.NoError(t, json.Unmarshal([]byte(judgeToGroupIndexSetting.Value.(string)), &judgeToGroupIdx))
+
+	// Pretend one judge is three steps in, so those steps must survive the re-plan
+	progressJudge := createdPairingJudges[0]
+	progressStep := 2
+	progressGroup := judgeToGroupIdx[progressJudge.ID]
+	keptColumn := []string{}
+	for step := 0; step <= progressStep; step++ {
+		keptColumn = append(keptColumn, pairingInitMatrix.Matrix[step][progressGroup])
+	}
+
+	_, err := db.Judges.UpdateOne(db.Ctx, bson.M{"id": progressJudge.ID}, bson.M{"$set": bson.M{"currentTeam": progressStep}})
+	require.NoError(t, err)
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingReplan(t, app, "replan", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, err = db.Judges.UpdateOne(db.Ctx, bson.M{"id": progressJudge.ID}, bson.M{"$set": bson.M{"currentTeam": -1}})
+	require.NoError(t, err)
+
+	var replanResp struct {
+		NumPairGroups int `json:"numPairGroups"`
+		NumSteps      int `json:"numSteps"`
+		AddedGroups   int `json:"addedGroups"`
+		RemovedGroups int `json:"removedGroups"`
+		KeptCells     int `json:"keptCells"`
+		Collisions    int `json:"collisions"`
+		Conflicts     int `json:"conflicts"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &replanResp))
+
+	require.Equal(t, totalPairingJudgeGroups, replanResp.NumPairGroups)
+	require.Equal(t, 0, replanResp.AddedGroups)
+	require.Equal(t, 0, replanResp.RemovedGroups)
+	require.Equal(t, 0, replanResp.Collisions)
+	require.Equal(t, len(pairingConflicts), replanResp.Conflicts)
+
+	keptAssignments := 0
+	for _, teamID := range keptColumn {
+		if teamID != "" {
+			keptAssignments++
+		}
+	}
+	require.Equal(t, keptAssignments, replanResp.KeptCells)
+
+	matrixSetting := &models.Setting{Name: models.SettingJudgeInitMatrix}
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Get())
+	require.NoError(t, json.Unmarshal([]byte(matrixSetting.Value.(string)), &pairingInitMatrix))
+	require.Equal(t, replanResp.NumSteps, pairingInitMatrix.Steps)
+
+	require.Equal(t, errmsg.EmptyStatusError, judgeToGroupIndexSetting.Get())
+	var replannedGroupIdx map[string]int
+	require.NoError(t, json.Unmarshal([]byte(judgeToGroupIndexSetting.Value.(string)), &replannedGroupIdx))
+	require.Equal(t, judgeToGroupIdx, replannedGroupIdx, "existing groups should keep their columns")
+
+	for step, teamID := range keptColumn {
+		require.Equal(t, teamID, pairingInitMatrix.Matrix[step][progressGroup], "step %d was already reached", step)
+	}
+
+	// Every group still visits every team it has no conflict with, exactly once
+	for groupIdx := 0; groupIdx < pairingInitMatrix.Groups; groupIdx++ {
+		visits := map[string]int{}
+		for step := 0; step < pairingInitMatrix.Steps; step++ {
+			if teamID := pairingInitMatrix.Matrix[step][groupIdx]; teamID != "" {
+				visits[teamID]++
+			}
+		}
+
+		for _, team := range createdPairingTeams {
+			expected := 1
+			for judgeID, conflictTeamID := range pairingConflicts {
+				if judgeToGroupIdx[judgeID] == groupIdx && conflictTeamID == team.ID {
+					expected = 0
+				}
+			}
+			require.Equal(t, expected, visits[team.ID], "group %d visits to %s", groupIdx, team.ID)
+		}
+	}
+}
+
+// TestJudgingPairsReplanFinishedGroup re-plans while every judge of a group has
+// finished, and expects the group to get no visits it would never make
+func TestJudgingPairsReplanFinishedGroup(t *testing.T) {
+	require.NotEmpty(t, pairingInitMatrix.Matrix, "matrix should be initialized")
+
+	matrixSetting := &models.Setting{Name: models.SettingJudgeInitMatrix}
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Get())
+	original := matrixSetting.Value
+
+	judgeToGroupIndexSetting := &models.Setting{Name: models.SettingJudgeToGroupIndex}
+	require.Equal(t, errmsg.EmptyStatusError, judgeToGroupIndexSetting.Get())
+	var judgeToGroupIdx map[string]int
+	require.NoError(t, json.Unmarshal([]byte(judgeToGroupIndexSetting.Value.(string)), &judgeToGroupIdx))
+
+	group := judgeToGroupIdx[createdPairingJudges[0].ID]
+	groupJudges := []string{}
+	for judgeID, groupIdx := range judgeToGroupIdx {
+		if groupIdx == group {
+			groupJudges = append(groupJudges, judgeID)
+		}
+	}
+
+	// Take a team out of the group's column, as if it had joined after the
+	// group went through it, so the group has a team left it hasn't seen
+	planned := pairingInitMatrix
+	planned.Matrix = make([][]string, len(pairingInitMatrix.Matrix))
+	unseenTeam := ""
+	for step, row := range pairingInitMatrix.Matrix {
+		planned.Matrix[step] = slices.Clone(row)
+		if unseenTeam == "" && row[group] != "" {
+			unseenTeam = row[group]
+			planned.Matrix[step][group] = ""
+		}
+	}
+	require.NotEmpty(t, unseenTeam)
+
+	plannedJSON, err := json.Marshal(planned)
+	require.NoError(t, err)
+	matrixSetting.Value = string(plannedJSON)
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Save(env.SUPERUSER_USERNAME))
+
+	_, err = db.Judges.UpdateMany(db.Ctx, bson.M{"id": bson.M{"$in": groupJudges}}, bson.M{"$set": bson.M{"currentTeam": 9000}})
+	require.NoError(t, err)
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingReplan(t, app, "replan", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var replanResp struct {
+		ClosedGroups int `json:"closedGroups"`
+		Collisions   int `json:"collisions"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &replanResp))
+	require.Equal(t, 1, replanResp.ClosedGroups)
+	require.Equal(t, 0, replanResp.Collisions)
+
+	var replanned struct {
+		Matrix [][]string `json:"matrix"`
+	}
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Get())
+	require.NoError(t, json.Unmarshal([]byte(matrixSetting.Value.(string)), &replanned))
+	for step, row := range replanned.Matrix {
+		if step < planned.Steps {
+			require.Equal(t, planned.Matrix[step][group], row[group], "step %d was already reached", step)
+		} else {
+			require.Empty(t, row[group], "the finished group should get nothing at step %d", step)
+		}
+	}
+
+	// Put the plan and the judges back for the simulation
+	matrixSetting.Value = original
+	require.Equal(t, errmsg.EmptyStatusError, matrixSetting.Save(env.SUPERUSER_USERNAME))
+	_, err = db.Judges.UpdateMany(db.Ctx, bson.M{"id": bson.M{"$in": groupJudges}}, bson.M{"$set": bson.M{"currentTeam": -1}})
+	require.NoError(t, err)
+}
+
+// TestJudgingPairsRubricConfig configures the rubric judges fill during the simulation
+func TestJudgingPairsRubricConfig(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	invalid := models.Rubric{Criteria: []models.RubricCriterion{
+		{ID: "design", Weight: 1, Min: 1, Max: 5},
+		{ID: "design", Weight: 1, Min: 1, Max: 5},
+	}}
+	_, statusCode := helpers.API_SuperUsersJudgingUpdateRubric(t, app, invalid, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "duplicate criteria should be rejected")
+
+	rubric := models.Rubric{Criteria: []models.RubricCriterion{
+		{ID: "innovation", Name: "Innovation", Weight: 2, Min: 1, Max: 10},
+		{ID: "technical", Name: "Technical depth", Weight: 1, Min: 1, Max: 10},
+		{ID: "presentation", Name: "Presentation", Weight: 1, Min: 1, Max: 10},
+	}}
+	_, statusCode = helpers.API_SuperUsersJudgingUpdateRubric(t, app, rubric, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingRubric(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var stored models.Rubric
+	require.NoError(t, json.Unmarshal(bodyBytes, &stored))
+	require.Equal(t, rubric, stored)
+}
+
 // TestJudgingPairsFullSimulation runs a complete simulation through all steps
 func TestJudgingPairsFullSimulation(t *testing.T) {
 	require.NotEmpty(t, pairingTestSuperUserToken, "superuser token should be initialized")
@@ -423,6 +869,10 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 	)
 	require.Equal(t, http.StatusOK, statusCode)
 
+	// Judgments are only accepted once the wait window has passed, so skip it
+	waitMinutesSetting := &models.Setting{Name: models.SettingWaitMinutes, Value: "0"}
+	require.Equal(t, errmsg.EmptyStatusError, waitMinutesSetting.Save(env.SUPERUSER_USERNAME))
+
 	fmt.Printf("\n========================================\n")
 	fmt.Printf("    FULL JUDGING SIMULATION\n")
 	fmt.Printf("========================================\n")
@@ -462,6 +912,20 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 	}
 	fmt.Printf("All %d judges upgraded\n\n", len(createdPairingJudges))
 
+	// Judges see the conflicts declared for them
+	for judgeID := range pairingConflicts {
+		bodyBytes, statusCode := helpers.API_JudgeConflicts(t, app, judgeTokens[judgeID])
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var conflicts []models.JudgeConflict
+		require.NoError(t, json.Unmarshal(bodyBytes, &conflicts))
+		require.Len(t, conflicts, 1)
+		require.Equal(t, judgeID, conflicts[0].JudgeID)
+
+		_, statusCode = helpers.API_JudgeDeclareConflict(t, app, "", "", judgeTokens[judgeID])
+		require.Equal(t, http.StatusBadRequest, statusCode, "a conflict must name a team or a university")
+	}
+
 	// Fetch judge to group mapping
 	judgeToGroupIndexSetting := &models.Setting{Name: models.SettingJudgeToGroupIndex}
 	errStatus := judgeToGroupIndexSetting.Get()
@@ -496,6 +960,18 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 		fmt.Printf("\n")
 	}
 
+	// Pick a solo judge to exercise skipping, so no pair partner diverges
+	skipJudgeID := ""
+	for _, judge := range createdPairingJudges {
+		if len(groupToJudges[judgeToGroupIdx[judge.ID]]) == 1 {
+			skipJudgeID = judge.ID
+			break
+		}
+	}
+	require.NotEmpty(t, skipJudgeID, "need a solo judge to test skipping")
+	skippedTeamID := ""
+	skippedTeamRetried := false
+
 	// Track statistics
 	type StepStats struct {
 		AssignedGroups   map[int]string // groupIdx -> teamID
@@ -509,6 +985,9 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 	totalCollisionsAcrossSteps := 0
 	judgeTeamHistory := make(map[string][]string) // judgeID -> list of team IDs in order
 	totalJudgmentsCreated := 0
+	rejectedJudgmentsChecked := false
+	rubricRejectionChecked := false
+	noteRejectionChecked := false
 	unreliableJudgmentCount := make(map[string]int)   // Track biased judgments per unreliable judge
 	unreliableJudgmentBias := make(map[string]string) // Track bias direction per unreliable judge
 
@@ -560,6 +1039,45 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 
 				require.NoError(t, json.Unmarshal(currentBytes, &currentTeam))
 				require.Equal(t, teamID, currentTeam.ID, "current team should match recently assigned team")
+
+				// Fill the rubric for the current team
+				rubricBytes, rubricStatus := helpers.API_JudgeRubric(t, app, judgeTokens[judge.ID])
+				require.Equal(t, http.StatusOK, rubricStatus)
+
+				var rubricResp struct {
+					Rubric models.Rubric `json:"rubric"`
+					TeamID string        `json:"teamID"`
+				}
+				require.NoError(t, json.Unmarshal(rubricBytes, &rubricResp))
+				require.Equal(t, teamID, rubricResp.TeamID, "rubric should be for the current team")
+
+				rubricScores := map[string]int{}
+				for _, criterion := range rubricResp.Rubric.Criteria {
+					rubricScores[criterion.ID] = criterion.Min + rand.Intn(criterion.Max-criterion.Min+1)
+				}
+
+				if !rubricRejectionChecked {
+					incomplete := map[string]int{}
+					for criterionID, score := range rubricScores {
+						incomplete[criterionID] = score
+						break
+					}
+					_, rubricStatus = helpers.API_JudgeSubmitRubricScore(t, app, incomplete, judgeTokens[judge.ID])
+					require.Equal(t, http.StatusBadRequest, rubricStatus, "rubric scores missing criteria should be rejected")
+					rubricRejectionChecked = true
+				}
+
+				_, rubricStatus = helpers.API_JudgeSubmitRubricScore(t, app, rubricScores, judgeTokens[judge.ID])
+				require.Equal(t, http.StatusOK, rubricStatus)
+
+				// Jot a private note on the team to read back when comparing at the next step
+				if !noteRejectionChecked {
+					_, noteStatus := helpers.API_JudgeSaveNote(t, app, teamID, strings.Repeat("x", models.JudgeNoteMaxLength+1), judgeTokens[judge.ID])
+					require.Equal(t, http.StatusBadRequest, noteStatus, "overlong notes should be rejected")
+					noteRejectionChecked = true
+				}
+				_, noteStatus := helpers.API_JudgeSaveNote(t, app, teamID, pairingNoteText(judge.ID, teamID), judgeTokens[judge.ID])
+				require.Equal(t, http.StatusOK, noteStatus)
 			case http.StatusAccepted:
 				var restResp struct {
 					Message string `json:"message"`
@@ -597,6 +1115,35 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 			globalTeamAssignments[teamID]++
 			globalGroupTeamPairs[fmt.Sprintf("%d-%s", groupIdx, teamID)] = true
 
+			// One solo judge finds their team absent once; the team is queued for a retry
+			if judge.ID == skipJudgeID && skippedTeamID == "" && len(judgeTeamHistory[judge.ID]) > 0 {
+				_, statusCode := helpers.API_JudgeSkip(t, app, "bored", judgeTokens[judge.ID])
+				require.Equal(t, http.StatusBadRequest, statusCode, "unknown skip reasons should be rejected")
+
+				bodyBytes, statusCode := helpers.API_JudgeSkip(t, app, models.JudgeSkipAbsent, judgeTokens[judge.ID])
+				require.Equal(t, http.StatusOK, statusCode)
+
+				var skip models.JudgeSkip
+				require.NoError(t, json.Unmarshal(bodyBytes, &skip))
+				require.Equal(t, teamID, skip.TeamID)
+				require.True(t, skip.Queued, "absent teams should be queued for a retry")
+
+				_, statusCode = helpers.API_JudgeSkip(t, app, models.JudgeSkipAbsent, judgeTokens[judge.ID])
+				require.Equal(t, http.StatusConflict, statusCode, "a team can only be skipped once per visit")
+
+				previousTeam := judgeTeamHistory[judge.ID][len(judgeTeamHistory[judge.ID])-1]
+				_, statusCode = helpers.API_JudgeCreateJudgment(t, app, teamID, previousTeam, judgeTokens[judge.ID])
+				require.Equal(t, http.StatusConflict, statusCode, "a skipped team cannot be judged")
+
+				skippedTeamID = teamID
+				fmt.Printf("    ⤼ %s skipped %s (absent)\n", judge.ID, teamID)
+				continue
+			}
+			if judge.ID == skipJudgeID && teamID == skippedTeamID {
+				skippedTeamRetried = true
+				fmt.Printf("    ↻ %s retrying %s\n", judge.ID, teamID)
+			}
+
 			// Create a judgment if this judge has seen a previous team
 			if len(judgeTeamHistory[judge.ID]) > 0 {
 				previousTeam := judgeTeamHistory[judge.ID][len(judgeTeamHistory[judge.ID])-1]
@@ -631,7 +1178,46 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 					}
 				}
 
-				_, statusCode := helpers.API_JudgeCreateJudgment(
+				// The previous team comes back with the note the judge left on it
+				previousBytes, previousStatus := helpers.API_JudgePreviousTeam(t, app, judgeTokens[judge.ID])
+				require.Equal(t, http.StatusOK, previousStatus)
+
+				var previousResp struct {
+					ID   string            `json:"id"`
+					Note *models.JudgeNote `json:"note"`
+				}
+				require.NoError(t, json.Unmarshal(previousBytes, &previousResp))
+				require.Equal(t, previousTeam, previousResp.ID)
+				require.NotNil(t, previousResp.Note, "previous team should include the judge's note")
+				require.Equal(t, pairingNoteText(judge.ID, previousTeam), previousResp.Note.Text)
+
+				if !rejectedJudgmentsChecked {
+					_, statusCode := helpers.API_JudgeCreateJudgment(
+						t,
+						app,
+						teamID,
+						teamID,
+						judgeTokens[judge.ID],
+					)
+					require.Equal(t, http.StatusBadRequest, statusCode, "comparing a team with itself should be rejected")
+
+					for _, other := range createdPairingTeams {
+						if other.ID == teamID || other.ID == previousTeam {
+							continue
+						}
+						_, statusCode := helpers.API_JudgeCreateJudgment(
+							t,
+							app,
+							other.ID,
+							teamID,
+							judgeTokens[judge.ID],
+						)
+						require.Equal(t, http.StatusForbidden, statusCode, "teams the judge was not assigned should be rejected")
+						break
+					}
+				}
+
+				bodyBytes, statusCode := helpers.API_JudgeCreateJudgment(
 					t,
 					app,
 					winningTeam,
@@ -640,6 +1226,35 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 				)
 				require.Equal(t, http.StatusOK, statusCode)
 				totalJudgmentsCreated++
+
+				if !rejectedJudgmentsChecked {
+					_, statusCode := helpers.API_JudgeCreateJudgment(
+						t,
+						app,
+						winningTeam,
+						losingTeam,
+						judgeTokens[judge.ID],
+					)
+					require.Equal(t, http.StatusConflict, statusCode, "a second judgment for the same step should be rejected")
+
+					// Retracting frees the step for a corrected judgment
+					var created models.Judgment
+					require.NoError(t, json.Unmarshal(bodyBytes, &created))
+					_, statusCode = helpers.API_JudgeRetractJudgment(t, app, created.ID, judgeTokens[judge.ID])
+					require.Equal(t, http.StatusOK, statusCode)
+					_, statusCode = helpers.API_JudgeRetractJudgment(t, app, created.ID, judgeTokens[judge.ID])
+					require.Equal(t, http.StatusConflict, statusCode, "a retracted judgment cannot be retracted again")
+
+					_, statusCode = helpers.API_JudgeCreateJudgment(
+						t,
+						app,
+						winningTeam,
+						losingTeam,
+						judgeTokens[judge.ID],
+					)
+					require.Equal(t, http.StatusOK, statusCode, "the step should accept a judgment again after retraction")
+					rejectedJudgmentsChecked = true
+				}
 				fmt.Printf("    → Created judgment: %s vs %s (winner: %s)%s\n", previousTeam, teamID, winningTeam, biasMarker)
 			}
 
@@ -698,6 +1313,26 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 		fmt.Printf("\n")
 	}
 
+	// If no rest step came up after the skip, the retry is served once the matrix runs out
+	require.NotEmpty(t, skippedTeamID, "the skip judge should have skipped a team")
+	if !skippedTeamRetried {
+		bodyBytes, statusCode := helpers.API_JudgeNextTeam(t, app, judgeTokens[skipJudgeID])
+		require.Equal(t, http.StatusOK, statusCode, "the skipped team should be retried after the matrix")
+
+		var retryTeam models.Team
+		require.NoError(t, json.Unmarshal(bodyBytes, &retryTeam))
+		require.Equal(t, skippedTeamID, retryTeam.ID)
+
+		history := judgeTeamHistory[skipJudgeID]
+		_, statusCode = helpers.API_JudgeCreateJudgment(t, app, retryTeam.ID, history[len(history)-1], judgeTokens[skipJudgeID])
+		require.Equal(t, http.StatusOK, statusCode, "the retried team can be judged against the last visited team")
+		totalJudgmentsCreated++
+		judgeTeamHistory[skipJudgeID] = append(history, retryTeam.ID)
+		skippedTeamRetried = true
+	}
+	_, statusCode = helpers.API_JudgeNextTeam(t, app, judgeTokens[skipJudgeID])
+	require.Equal(t, http.StatusGone, statusCode, "judging should finish once retries are done")
+
 	// Final verification and summary
 	fmt.Printf("========================================\n")
 	fmt.Printf("      SIMULATION SUMMARY\n")
@@ -765,7 +1400,7 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 	fmt.Printf("Total judgments created during simulation: %d\n", totalJudgmentsCreated)
 
 	// Fetch actual judgments from database to verify
-	cursor, err := db.Judgments.Find(db.Ctx, bson.M{})
+	cursor, err := db.Judgments.Find(db.Ctx, bson.M{"status": models.JudgmentStatusValid})
 	require.NoError(t, err)
 	defer cursor.Close(db.Ctx)
 
@@ -821,52 +1456,216 @@ func TestJudgingPairsFullSimulation(t *testing.T) {
 	fmt.Printf("========================================\n")
 }
 
-// TestJudgingPairsCrowdBTScoring runs the Crowd Bradley-Terry algorithm on the created judgments
-func TestJudgingPairsCrowdBTScoring(t *testing.T) {
-	fmt.Printf("\n========================================\n")
-	fmt.Printf("    CROWD BT SCORING ALGORITHM\n")
-	fmt.Printf("========================================\n\n")
+// TestJudgingPairsSkipReport tests that admins see the team skipped during the simulation
+func TestJudgingPairsSkipReport(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
 
-	// Fetch all judgments from database
-	cursor, err := db.Judgments.Find(db.Ctx, bson.M{})
-	require.NoError(t, err)
-	defer cursor.Close(db.Ctx)
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingSkips(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
 
-	var dbJudgments []models.Judgment
-	err = cursor.All(db.Ctx, &dbJudgments)
-	require.NoError(t, err)
+	var report struct {
+		Teams []models.TeamSkipSummary `json:"teams"`
+		Skips []models.JudgeSkip       `json:"skips"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &report))
 
-	fmt.Printf("Loaded %d judgments from database\n", len(dbJudgments))
-	if len(dbJudgments) == 0 {
-		fmt.Printf("⚠ No judgments found - skipping scoring\n")
-		return
+	require.Len(t, report.Skips, 1)
+	require.Len(t, report.Teams, 1)
+	require.Equal(t, report.Skips[0].TeamID, report.Teams[0].TeamID)
+	require.Equal(t, 1, report.Teams[0].ByReason[models.JudgeSkipAbsent])
+	require.Equal(t, []string{report.Skips[0].JudgeID}, report.Teams[0].JudgeIDs)
+}
+
+// TestJudgingPairsDashboard tests the progress dashboard once every judge has finished
+func TestJudgingPairsDashboard(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingDashboard(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var dashboard struct {
+		TotalSteps   int            `json:"totalSteps"`
+		StatusCounts map[string]int `json:"statusCounts"`
+		Judges       []struct {
+			JudgeID         string     `json:"judgeID"`
+			Status          string     `json:"status"`
+			JudgmentsMade   int        `json:"judgmentsMade"`
+			Reliability     float64    `json:"reliability"`
+			ProjectedFinish *time.Time `json:"projectedFinish"`
+		} `json:"judges"`
+		Teams []struct {
+			TeamID          string `json:"teamID"`
+			TimesJudged     int    `json:"timesJudged"`
+			UniqueOpponents int    `json:"uniqueOpponents"`
+		} `json:"teams"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &dashboard))
+
+	require.Equal(t, pairingInitMatrix.Steps, dashboard.TotalSteps)
+	require.Len(t, dashboard.Judges, totalPairingJudges)
+	require.Equal(t, totalPairingJudges, dashboard.StatusCounts["finished"])
+
+	judgmentsMade := 0
+	for _, judge := range dashboard.Judges {
+		require.Equal(t, "finished", judge.Status, "judge %s", judge.JudgeID)
+		require.Nil(t, judge.ProjectedFinish)
+		require.Greater(t, judge.Reliability, 0.0)
+		require.Less(t, judge.Reliability, 1.0)
+		judgmentsMade += judge.JudgmentsMade
 	}
 
-	// Convert to utils.JudgmentWithJudge format
-	judgments := make([]utils.JudgmentWithJudge, len(dbJudgments))
-	for i, j := range dbJudgments {
-		judgments[i] = utils.JudgmentWithJudge{
-			WinningTeamID: j.WinningTeamID,
-			LosingTeamID:  j.LosingTeamID,
-			JudgeID:       j.JudgeID,
+	timesJudged := 0
+	require.Len(t, dashboard.Teams, numPairingTeams)
+	for i, team := range dashboard.Teams {
+		require.LessOrEqual(t, team.UniqueOpponents, team.TimesJudged)
+		if i > 0 {
+			require.GreaterOrEqual(t, team.TimesJudged, dashboard.Teams[i-1].TimesJudged, "least judged teams come first")
 		}
+		timesJudged += team.TimesJudged
 	}
+	require.Equal(t, 2*judgmentsMade, timesJudged, "each judgment covers two teams")
+}
 
-	fmt.Printf("Running Crowd BT algorithm...\n")
+// pairingNoteText is the note a simulated judge leaves on a team
+func pairingNoteText(judgeID string, teamID string) string {
+	return fmt.Sprintf("%s notes on %s", judgeID, teamID)
+}
 
-	// Time the algorithm
-	startTime := time.Now()
+// TestJudgingPairsJudgeNotes tests that admins can read the notes judges left during the simulation
+func TestJudgingPairsJudgeNotes(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+	require.NotEmpty(t, createdPairingJudges)
 
-	// Run the algorithm
-	scorer := utils.NewCrowdBTScorer()
-	scores := scorer.Score(judgments)
-	ranking := scorer.RankTeams()
-	judgeReliability := scorer.GetJudgeReliabilityAll()
-	teamUncertainty := scorer.GetTeamUncertainty()
+	judge := createdPairingJudges[0]
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingNotes(t, app, "?judgeID="+judge.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
 
-	elapsedTime := time.Since(startTime)
+	var notes []models.JudgeNote
+	require.NoError(t, json.Unmarshal(bodyBytes, &notes))
+	require.NotEmpty(t, notes, "the judge left notes during the simulation")
+	for _, note := range notes {
+		require.Equal(t, judge.ID, note.JudgeID)
+		require.Equal(t, pairingNoteText(judge.ID, note.TeamID), note.Text)
+	}
 
-	fmt.Printf("✓ Algorithm completed in %v\n\n", elapsedTime)
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingNotes(t, app, "?teamID="+notes[0].TeamID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var teamNotes []models.JudgeNote
+	require.NoError(t, json.Unmarshal(bodyBytes, &teamNotes))
+	require.NotEmpty(t, teamNotes)
+	for _, note := range teamNotes {
+		require.Equal(t, notes[0].TeamID, note.TeamID)
+	}
+}
+
+// TestJudgingPairsJudgmentAudit tests superuser invalidation and the judgment audit trail
+func TestJudgingPairsJudgmentAudit(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	// The simulation retracted exactly one judgment
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingJudgments(
+		t,
+		app,
+		"?status="+models.JudgmentStatusRetracted,
+		pairingTestSuperUserToken,
+	)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var retracted []models.Judgment
+	require.NoError(t, json.Unmarshal(bodyBytes, &retracted))
+	require.Len(t, retracted, 1)
+	require.Len(t, retracted[0].History, 2, "history should record creation and retraction")
+	require.Equal(t, models.JudgmentStatusRetracted, retracted[0].History[1].Status)
+	require.Equal(t, retracted[0].JudgeID, retracted[0].History[1].ActorID)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingJudgments(
+		t,
+		app,
+		"?status="+models.JudgmentStatusValid,
+		pairingTestSuperUserToken,
+	)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var valid []models.Judgment
+	require.NoError(t, json.Unmarshal(bodyBytes, &valid))
+	require.NotEmpty(t, valid)
+	target := valid[0]
+
+	_, statusCode = helpers.API_SuperUsersJudgingInvalidateJudgment(t, app, target.ID, " ", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "invalidation should require a reason")
+
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingInvalidateJudgment(t, app, target.ID, "duplicate submission", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var invalidated models.Judgment
+	require.NoError(t, json.Unmarshal(bodyBytes, &invalidated))
+	require.Equal(t, models.JudgmentStatusInvalidated, invalidated.Status)
+	last := invalidated.History[len(invalidated.History)-1]
+	require.Equal(t, "duplicate submission", last.Reason)
+	require.Equal(t, "superuser", last.ActorRole)
+
+	_, statusCode = helpers.API_SuperUsersJudgingInvalidateJudgment(t, app, target.ID, "again", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusConflict, statusCode, "an invalidated judgment cannot be invalidated again")
+
+	_, statusCode = helpers.API_SuperUsersJudgingInvalidateJudgment(t, app, "missing", "typo", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusNotFound, statusCode)
+
+	// Invalidated judgments no longer feed the scorer
+	judgments, serr := models.GetAllJudgments()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+	require.Len(t, judgments, len(valid)-1)
+	for _, judgment := range judgments {
+		require.NotEqual(t, target.ID, judgment.ID)
+	}
+}
+
+// TestJudgingPairsCrowdBTScoring runs the Crowd Bradley-Terry algorithm on the created judgments
+func TestJudgingPairsCrowdBTScoring(t *testing.T) {
+	fmt.Printf("\n========================================\n")
+	fmt.Printf("    CROWD BT SCORING ALGORITHM\n")
+	fmt.Printf("========================================\n\n")
+
+	// Fetch all judgments from database
+	cursor, err := db.Judgments.Find(db.Ctx, bson.M{"status": models.JudgmentStatusValid})
+	require.NoError(t, err)
+	defer cursor.Close(db.Ctx)
+
+	var dbJudgments []models.Judgment
+	err = cursor.All(db.Ctx, &dbJudgments)
+	require.NoError(t, err)
+
+	fmt.Printf("Loaded %d judgments from database\n", len(dbJudgments))
+	if len(dbJudgments) == 0 {
+		fmt.Printf("⚠ No judgments found - skipping scoring\n")
+		return
+	}
+
+	// Convert to utils.JudgmentWithJudge format
+	judgments := make([]utils.JudgmentWithJudge, len(dbJudgments))
+	for i, j := range dbJudgments {
+		judgments[i] = utils.JudgmentWithJudge{
+			WinningTeamID: j.WinningTeamID,
+			LosingTeamID:  j.LosingTeamID,
+			JudgeID:       j.JudgeID,
+		}
+	}
+
+	fmt.Printf("Running Crowd BT algorithm...\n")
+
+	// Time the algorithm
+	startTime := time.Now()
+
+	// Run the algorithm
+	scorer := utils.NewCrowdBTScorer()
+	scores := scorer.Score(judgments)
+	ranking := scorer.RankTeams()
+	judgeReliability := scorer.GetJudgeReliabilityAll()
+	teamUncertainty := scorer.GetTeamUncertainty()
+
+	elapsedTime := time.Since(startTime)
+
+	fmt.Printf("✓ Algorithm completed in %v\n\n", elapsedTime)
 
 	// Print team rankings
 	fmt.Printf("========================================\n")
@@ -1062,6 +1861,7 @@ func TestJudgingPairsComputeRankingsEndpoint(t *testing.T) {
 		TeamUncertainty  map[string]float64            `json:"teamUncertainty"`
 		JudgeReliability map[string]map[string]float64 `json:"judgeReliability"`
 		JudgmentCount    int                           `json:"judgmentCount"`
+		Diagnostics      utils.ScorerDiagnostics       `json:"diagnostics"`
 	}
 
 	err := json.Unmarshal(bodyBytes, &response)
@@ -1077,6 +1877,10 @@ func TestJudgingPairsComputeRankingsEndpoint(t *testing.T) {
 	require.Equal(t, len(response.RankedTeams), len(response.TeamScores), "ranked teams should match team scores")
 	require.Equal(t, len(response.RankedTeams), len(response.TeamUncertainty), "ranked teams should match team uncertainty")
 	require.Greater(t, response.JudgmentCount, 0, "should have processed judgments")
+	require.Greater(t, response.Diagnostics.Iterations, 0, "should report at least one iteration")
+	require.Len(t, response.Diagnostics.LogLikelihood, response.Diagnostics.Iterations, "should report log-likelihood per iteration")
+	fmt.Printf("Scorer iterations: %d (converged: %v, final delta: %.6f)\n",
+		response.Diagnostics.Iterations, response.Diagnostics.Converged, response.Diagnostics.FinalDelta)
 
 	// Verify team scores are in descending order
 	for i := 0; i < len(response.RankedTeams)-1; i++ {
@@ -1168,6 +1972,197 @@ func TestJudgingPairsComputeRankingsEndpoint(t *testing.T) {
 	fmt.Printf("========================================\n\n")
 }
 
+// TestJudgingPairsLeaderboardEndpoint tests that the live leaderboard reflects the last refit
+func TestJudgingPairsLeaderboardEndpoint(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingLeaderboard(
+		t,
+		app,
+		pairingTestSuperUserToken,
+	)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var leaderboard models.Leaderboard
+	require.NoError(t, json.Unmarshal(bodyBytes, &leaderboard))
+
+	judgmentCount, err := db.Judgments.CountDocuments(db.Ctx, bson.M{"status": models.JudgmentStatusValid})
+	require.NoError(t, err)
+
+	require.Equal(t, models.LeaderboardSourceRefit, leaderboard.Source, "compute-rankings should have replaced the live standings")
+	require.Equal(t, int(judgmentCount), leaderboard.JudgmentCount)
+	require.NotEmpty(t, leaderboard.Teams)
+	require.False(t, leaderboard.UpdatedAt.IsZero(), "should report when standings were updated")
+	require.False(t, leaderboard.LastJudgmentAt.IsZero(), "should report the latest judgment time")
+
+	totalAppearances := 0
+	for i, team := range leaderboard.Teams {
+		require.Equal(t, i+1, team.Rank)
+		if i > 0 {
+			require.GreaterOrEqual(t, leaderboard.Teams[i-1].Mu, team.Mu, "teams should be sorted by mu descending")
+		}
+		totalAppearances += team.Judgments
+	}
+	require.Equal(t, 2*int(judgmentCount), totalAppearances, "every judgment counts for both of its teams")
+}
+
+// TestJudgingPairsJudgeOutliers tests the outlier analysis and that excluding a
+// judge takes their judgments out of the leaderboard until they are restored
+func TestJudgingPairsJudgeOutliers(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	_, statusCode := helpers.API_SuperUsersJudgingOutliers(t, app, "?sideBias=0.2", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingOutliers(t, app, "", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var outliers struct {
+		Judges []struct {
+			JudgeID       string   `json:"judgeID"`
+			Judgments     int      `json:"judgments"`
+			Agreement     float64  `json:"agreement"`
+			FirstPickRate float64  `json:"firstPickRate"`
+			Weight        float64  `json:"weight"`
+			Flags         []string `json:"flags"`
+		} `json:"judges"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &outliers))
+	require.NotEmpty(t, outliers.Judges)
+
+	judgmentCount, err := db.Judgments.CountDocuments(db.Ctx, bson.M{"status": models.JudgmentStatusValid})
+	require.NoError(t, err)
+
+	analyzed := 0
+	for _, judge := range outliers.Judges {
+		require.Equal(t, models.JudgeWeightDefault, judge.Weight)
+		require.GreaterOrEqual(t, judge.Agreement, 0.0)
+		require.LessOrEqual(t, judge.Agreement, 1.0)
+		require.GreaterOrEqual(t, judge.FirstPickRate, 0.0)
+		require.LessOrEqual(t, judge.FirstPickRate, 1.0)
+		require.NotNil(t, judge.Flags)
+		analyzed += judge.Judgments
+	}
+	require.Equal(t, int(judgmentCount), analyzed)
+
+	excluded := outliers.Judges[0]
+
+	_, statusCode = helpers.API_SuperUsersJudgingSetJudgeWeight(t, app, excluded.JudgeID, 1.5, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersJudgingSetJudgeWeight(t, app, "missing-judge", 0.5, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusNotFound, statusCode)
+
+	leaderboardCount := func() int {
+		bodyBytes, statusCode := helpers.API_SuperUsersJudgingLeaderboard(t, app, pairingTestSuperUserToken)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var leaderboard models.Leaderboard
+		require.NoError(t, json.Unmarshal(bodyBytes, &leaderboard))
+		return leaderboard.JudgmentCount
+	}
+
+	_, statusCode = helpers.API_SuperUsersJudgingSetJudgeWeight(t, app, excluded.JudgeID, 0, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Equal(t, int(judgmentCount)-excluded.Judgments, leaderboardCount(), "an excluded judge's judgments stop counting")
+
+	_, statusCode = helpers.API_SuperUsersJudgingSetJudgeWeight(t, app, excluded.JudgeID, 1, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Equal(t, int(judgmentCount), leaderboardCount())
+
+	weights, serr := models.GetJudgeWeights()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+	require.Empty(t, weights, "restoring the default weight removes the adjustment")
+}
+
+// TestJudgingPairsRubricRankings tests per-criterion rankings from the simulation's rubric scores
+func TestJudgingPairsRubricRankings(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingRubricRankings(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var response struct {
+		Criteria   map[string][]utils.RubricTeamScore `json:"criteria"`
+		Overall    []utils.RubricTeamScore            `json:"overall"`
+		Rubric     models.Rubric                      `json:"rubric"`
+		ScoreCount int                                `json:"scoreCount"`
+		Pairwise   []models.LeaderboardTeam           `json:"pairwise"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &response))
+
+	require.Greater(t, response.ScoreCount, 0)
+	require.NotEmpty(t, response.Pairwise, "pairwise standings should be included")
+	require.Len(t, response.Criteria, len(response.Rubric.Criteria))
+	require.NotEmpty(t, response.Overall)
+
+	for _, criterion := range response.Rubric.Criteria {
+		scores := response.Criteria[criterion.ID]
+		require.Len(t, scores, len(response.Overall), "every scored team should be ranked on %s", criterion.ID)
+		for i, score := range scores {
+			require.Equal(t, i+1, score.Rank)
+			require.GreaterOrEqual(t, score.RawMean, float64(criterion.Min))
+			require.LessOrEqual(t, score.RawMean, float64(criterion.Max))
+		}
+	}
+
+	fmt.Printf("\nRubric overall ranking (%d score sheets):\n", response.ScoreCount)
+	for _, score := range response.Overall {
+		fmt.Printf("  #%2d %s  z=%.3f raw=%.2f judges=%d\n", score.Rank, score.TeamID, score.Score, score.RawMean, score.Count)
+	}
+}
+
+// TestJudgingPairsRankStabilityEndpoint tests the bootstrap rank stability analysis
+func TestJudgingPairsRankStabilityEndpoint(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	_, statusCode := helpers.API_SuperUsersJudgingRankStability(
+		t,
+		app,
+		"?samples=0",
+		pairingTestSuperUserToken,
+	)
+	require.Equal(t, http.StatusBadRequest, statusCode, "zero samples should be rejected")
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingRankStability(
+		t,
+		app,
+		"?samples=30&topN=3&seed=7",
+		pairingTestSuperUserToken,
+	)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var response struct {
+		Samples int `json:"samples"`
+		TopN    int `json:"topN"`
+		Teams   []struct {
+			TeamID           string  `json:"teamID"`
+			Rank             int     `json:"rank"`
+			RankLow          int     `json:"rankLow"`
+			RankHigh         int     `json:"rankHigh"`
+			RankDistribution []int   `json:"rankDistribution"`
+			TopNProbability  float64 `json:"topNProbability"`
+		} `json:"teams"`
+		PairwiseWinProbability map[string]map[string]float64 `json:"pairwiseWinProbability"`
+		JudgmentCount          int                           `json:"judgmentCount"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &response))
+
+	require.Equal(t, 30, response.Samples)
+	require.Equal(t, 3, response.TopN)
+	require.Greater(t, response.JudgmentCount, 0)
+	require.NotEmpty(t, response.Teams)
+
+	fmt.Printf("\nRank stability (30 resamples):\n")
+	for _, team := range response.Teams {
+		require.LessOrEqual(t, team.RankLow, team.RankHigh)
+		require.Len(t, team.RankDistribution, len(response.Teams))
+		require.Len(t, response.PairwiseWinProbability[team.TeamID], len(response.Teams)-1)
+		fmt.Printf("  #%2d %s  95%% rank [%d, %d]  P(top 3)=%.2f\n",
+			team.Rank, team.TeamID, team.RankLow, team.RankHigh, team.TopNProbability)
+	}
+}
+
 // TestJudgingPairsParticipantVoting tests the participant voting system
 func TestJudgingPairsParticipantVoting(t *testing.T) {
 	require.NotNil(t, app, "app should be initialized")
@@ -1220,24 +2215,38 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 	fmt.Printf("  3. %s\n", finalist3)
 	fmt.Printf("\n")
 
-	// Verify finalists are saved to settings
-	f1Setting := &models.Setting{Name: models.SettingFinalist1}
-	f2Setting := &models.Setting{Name: models.SettingFinalist2}
-	f3Setting := &models.Setting{Name: models.SettingFinalist3}
-
-	serr := f1Setting.Get()
+	// Verify the top teams became the finalists
+	savedFinalists, serr := models.GetFinalists()
 	require.Equal(t, errmsg.EmptyStatusError, serr)
-	require.Equal(t, finalist1, f1Setting.Value.(string))
+	require.Equal(t, models.FinalistsSourceComputed, savedFinalists.Source)
+	require.Equal(t, rankingResp.RankedTeams[:savedFinalists.Count], savedFinalists.Teams)
 
-	serr = f2Setting.Get()
-	require.Equal(t, errmsg.EmptyStatusError, serr)
-	require.Equal(t, finalist2, f2Setting.Value.(string))
+	fmt.Printf("✓ Finalists saved\n\n")
 
-	serr = f3Setting.Get()
-	require.Equal(t, errmsg.EmptyStatusError, serr)
-	require.Equal(t, finalist3, f3Setting.Value.(string))
+	// Schedule a semifinal that is open now on the saved finalists, and a later
+	// people's choice round between the top two
+	now := time.Now()
+	_, statusCode = helpers.API_SuperUsersVotingCreateSession(t, app, "Backwards", "", now.Add(time.Hour), now, nil, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "a session must close after it opens")
+
+	type votingSessionResp struct {
+		ID        string   `json:"id"`
+		Finalists []string `json:"finalists"`
+		Status    string   `json:"status"`
+	}
 
-	fmt.Printf("✓ Finalists saved to settings\n\n")
+	sessionBody, statusCode := helpers.API_SuperUsersVotingCreateSession(t, app, "Semifinal", "", now.Add(-time.Minute), now.Add(30*time.Minute), nil, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var semifinal votingSessionResp
+	require.NoError(t, json.Unmarshal(sessionBody, &semifinal))
+	require.Equal(t, models.VotingSessionOpen, semifinal.Status)
+	require.Equal(t, savedFinalists.Teams, semifinal.Finalists, "the session should snapshot the finalists")
+
+	sessionBody, statusCode = helpers.API_SuperUsersVotingCreateSession(t, app, "People's Choice", "", now.Add(time.Hour), now.Add(2*time.Hour), []string{finalist1, finalist2}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var peoplesChoice votingSessionResp
+	require.NoError(t, json.Unmarshal(sessionBody, &peoplesChoice))
+	require.Equal(t, models.VotingSessionScheduled, peoplesChoice.Status)
 
 	// Cast votes for all participants
 	fmt.Printf("========================================\n")
@@ -1245,6 +2254,7 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 	fmt.Printf("========================================\n\n")
 
 	voteCastLog := make(map[string]string) // accountID -> finalist team ID
+	participantTokens := make([]string, 0, len(createdPairingAccounts))
 
 	finalists := []string{finalist1, finalist2, finalist3}
 
@@ -1272,11 +2282,14 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 		require.Equal(t, http.StatusOK, statusCode, "should be able to login")
 
 		var loginResp struct {
-			Token string `json:"token"`
+			Token   string         `json:"token"`
+			Account models.Account `json:"account"`
 		}
 		err := json.Unmarshal(loginBody, &loginResp)
 		require.NoError(t, err)
+		require.False(t, loginResp.Account.HasVoted)
 		participantToken := loginResp.Token
+		participantTokens = append(participantTokens, participantToken)
 
 		// Get voting status
 		statusBody, statusCode := helpers.API_AccountsVotingStatus(
@@ -1287,13 +2300,17 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 		require.Equal(t, http.StatusOK, statusCode)
 
 		var statusResp struct {
-			VotingOpen bool          `json:"votingOpen"`
-			HasVoted   bool          `json:"hasVoted"`
-			Finalists  []models.Team `json:"finalists"`
+			VotingOpen        bool          `json:"votingOpen"`
+			SessionID         string        `json:"sessionID"`
+			SecondsUntilClose int64         `json:"secondsUntilClose"`
+			HasVoted          bool          `json:"hasVoted"`
+			Finalists         []models.Team `json:"finalists"`
 		}
 		err = json.Unmarshal(statusBody, &statusResp)
 		require.NoError(t, err)
 		require.True(t, statusResp.VotingOpen, "voting should be open")
+		require.Equal(t, semifinal.ID, statusResp.SessionID, "the open semifinal should be the current session")
+		require.Greater(t, statusResp.SecondsUntilClose, int64(0))
 		require.False(t, statusResp.HasVoted, "participant should not have voted yet")
 		require.Equal(t, 5, len(statusResp.Finalists), "should have 5 finalists")
 
@@ -1315,6 +2332,16 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 		// Randomly select a finalist to vote for
 		selectedFinalist := finalists[rand.Intn(len(finalists))]
 
+		// The people's choice round hasn't opened yet
+		_, statusCode = helpers.API_AccountsVotingCastVoteInSession(
+			t,
+			app,
+			finalist1,
+			peoplesChoice.ID,
+			participantToken,
+		)
+		require.Equal(t, http.StatusForbidden, statusCode, "votes before a session opens should be rejected")
+
 		// Cast vote
 		voteBody, statusCode := helpers.API_AccountsVotingCastVote(
 			t,
@@ -1349,6 +2376,12 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, statusResp2.HasVoted, "participant should have voted after casting vote")
 
+		// account responses keep reporting it for clients from before sessions
+		voter := models.Account{ID: account.ID}
+		require.NoError(t, voter.Get())
+		require.Equal(t, errmsg.EmptyStatusError, voter.LoadHasVoted())
+		require.True(t, voter.HasVoted)
+
 		// Try to vote again - should fail
 		_, statusCode = helpers.API_AccountsVotingCastVote(
 			t,
@@ -1409,7 +2442,38 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 			}
 		}
 		require.True(t, isValid, "vote for %s should be for a valid finalist", vote.Choice)
+		require.Equal(t, semifinal.ID, vote.SessionID, "vote should belong to the semifinal")
+	}
+
+	// Once a session has opened its finalists are fixed
+	_, statusCode = helpers.API_SuperUsersVotingUpdateSession(t, app, semifinal.ID, nil, nil, []string{finalist1}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusConflict, statusCode)
+
+	// Open the people's choice round early; its votes are kept apart
+	opensAt := time.Now().Add(-time.Second)
+	_, statusCode = helpers.API_SuperUsersVotingUpdateSession(t, app, peoplesChoice.ID, &opensAt, nil, nil, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, finalist3, peoplesChoice.ID, participantTokens[0])
+	require.Equal(t, http.StatusBadRequest, statusCode, "the round only has its own finalists")
+
+	_, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, finalist2, peoplesChoice.ID, participantTokens[0])
+	require.Equal(t, http.StatusOK, statusCode, "having voted in the semifinal shouldn't block another round")
+
+	_, statusCode = helpers.API_AccountsVotingCastVote(t, app, finalist2, participantTokens[0])
+	require.Equal(t, http.StatusConflict, statusCode, "the current session is now the people's choice round")
+
+	peoplesVotes, err := models.GetVotes(peoplesChoice.ID)
+	require.NoError(t, err)
+	require.Len(t, peoplesVotes, 1)
+
+	semifinalResults, err := models.GetVoteResults(semifinal.ID, finalists)
+	require.NoError(t, err)
+	semifinalTotal := int64(0)
+	for _, count := range semifinalResults {
+		semifinalTotal += count
 	}
+	require.Equal(t, int64(len(voteCastLog)), semifinalTotal, "semifinal results shouldn't include other rounds")
 
 	fmt.Printf("\n✓ All votes verified\n")
 	fmt.Printf("========================================\n")
@@ -1417,6 +2481,543 @@ func TestJudgingPairsParticipantVoting(t *testing.T) {
 	fmt.Printf("========================================\n\n")
 }
 
+// TestJudgingPairsRankedVoting tests ranked ballots with instant-runoff and Borda tallies
+func TestJudgingPairsRankedVoting(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	saved, serr := models.GetFinalists()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+	require.GreaterOrEqual(t, len(saved.Teams), 3)
+	finalists := saved.Teams[:3]
+
+	now := time.Now()
+	_, statusCode := helpers.API_SuperUsersVotingCreateSession(t, app, "Final", "plurality", now, now.Add(time.Hour), finalists, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "unknown voting modes are rejected")
+
+	sessionBody, statusCode := helpers.API_SuperUsersVotingCreateSession(t, app, "Final", models.VotingModeRanked, now.Add(-time.Second), now.Add(time.Hour), finalists, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var final struct {
+		ID   string `json:"id"`
+		Mode string `json:"mode"`
+	}
+	require.NoError(t, json.Unmarshal(sessionBody, &final))
+	require.Equal(t, models.VotingModeRanked, final.Mode)
+
+	ballots := make([][]string, 0, len(createdPairingAccounts))
+	for i, account := range createdPairingAccounts {
+		loginBody, statusCode := helpers.API_AccountsAuthLogin(t, app, account.Email, "testpassword123")
+		require.Equal(t, http.StatusOK, statusCode)
+		var loginResp struct {
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(loginBody, &loginResp))
+
+		if i == 0 {
+			_, statusCode = helpers.API_AccountsVotingCastBallot(t, app, []string{finalists[0], finalists[0]}, final.ID, loginResp.Token)
+			require.Equal(t, http.StatusBadRequest, statusCode, "a team can't be ranked twice")
+		}
+
+		// Rank a random order of the finalists, sometimes leaving the last one out
+		ballot := slices.Clone(finalists)
+		rand.Shuffle(len(ballot), func(a, b int) { ballot[a], ballot[b] = ballot[b], ballot[a] })
+		if rand.Intn(2) == 0 {
+			ballot = ballot[:2]
+		}
+
+		_, statusCode = helpers.API_AccountsVotingCastBallot(t, app, ballot, final.ID, loginResp.Token)
+		require.Equal(t, http.StatusOK, statusCode)
+		ballots = append(ballots, ballot)
+	}
+
+	resultsBody, statusCode := helpers.API_SuperUsersVotingResults(t, app, final.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var results struct {
+		Mode    string `json:"mode"`
+		Ballots int64  `json:"ballots"`
+		Results []struct {
+			Team        models.Team `json:"team"`
+			Count       int64       `json:"count"`
+			BordaPoints int64       `json:"bordaPoints"`
+		} `json:"results"`
+		Rounds []models.RunoffRound `json:"rounds"`
+		Winner *models.Team         `json:"winner"`
+	}
+	require.NoError(t, json.Unmarshal(resultsBody, &results))
+	require.Equal(t, models.VotingModeRanked, results.Mode)
+	require.Equal(t, int64(len(ballots)), results.Ballots)
+	require.Len(t, results.Results, len(finalists))
+
+	expectedRounds, expectedWinner := models.TallyInstantRunoff(ballots, finalists)
+	expectedBorda := models.TallyBorda(ballots, finalists)
+	expectedFirst := models.TallyPlurality(ballots, finalists)
+
+	for i, entry := range results.Results {
+		require.Equal(t, finalists[i], entry.Team.ID, "results follow finalist order")
+		require.Equal(t, expectedFirst[entry.Team.ID], entry.Count)
+		require.Equal(t, expectedBorda[entry.Team.ID], entry.BordaPoints)
+	}
+
+	require.Equal(t, expectedRounds, results.Rounds)
+	require.NotNil(t, results.Winner)
+	require.Equal(t, expectedWinner, results.Winner.ID)
+
+	last := results.Rounds[len(results.Rounds)-1]
+	require.Empty(t, last.Eliminated)
+	require.Greater(t, last.Counts[expectedWinner]*2, results.Ballots-last.Exhausted, "the winner holds a majority of continuing ballots")
+
+	fmt.Printf("Ranked final: %d ballots, %d rounds, winner %s\n", results.Ballots, len(results.Rounds), results.Winner.ID)
+}
+
+// TestJudgingPairsVotingEligibility tests check-in, badge scan and own-team voting rules
+func TestJudgingPairsVotingEligibility(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	saved, serr := models.GetFinalists()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+
+	// A participant whose team is a finalist
+	var ownTeam models.Team
+	for _, team := range createdPairingTeams {
+		if saved.IsFinalist(team.ID) {
+			ownTeam = team
+			break
+		}
+	}
+	require.NotEmpty(t, ownTeam.ID)
+	otherFinalist := saved.Teams[0]
+	if otherFinalist == ownTeam.ID {
+		otherFinalist = saved.Teams[1]
+	}
+
+	voter := models.Account{ID: ownTeam.Members[0]}
+	require.NoError(t, voter.Get())
+	require.NoError(t, voter.AddToTeam(ownTeam.ID))
+	require.False(t, voter.CheckedIn, "participants start out not checked in")
+
+	loginBody, statusCode := helpers.API_AccountsAuthLogin(t, app, voter.Email, "testpassword123")
+	require.Equal(t, http.StatusOK, statusCode)
+	var loginResp struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(loginBody, &loginResp))
+	voterToken := loginResp.Token
+
+	var errResp struct {
+		Message string `json:"message"`
+	}
+
+	now := time.Now()
+	_, statusCode = helpers.API_SuperUsersVotingCreateSessionWithEligibility(t, app, "Too Long Ago", now, now.Add(time.Hour), saved.Teams,
+		models.VotingEligibility{ScannedWithinMinutes: models.MaxScannedWithinMinutes + 1}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	sessionBody, statusCode := helpers.API_SuperUsersVotingCreateSessionWithEligibility(t, app, "Checked-in Choice", now.Add(-time.Second), now.Add(time.Hour), saved.Teams,
+		models.VotingEligibility{RequireCheckedIn: true, ExcludeOwnTeam: true}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var checkedInSession struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(sessionBody, &checkedInSession))
+
+	// The status explains why the participant can't vote yet
+	statusBody, statusCode := helpers.API_AccountsVotingStatus(t, app, voterToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var statusResp struct {
+		SessionID        string `json:"sessionID"`
+		Eligible         bool   `json:"eligible"`
+		IneligibleReason string `json:"ineligibleReason"`
+		OwnTeamID        string `json:"ownTeamID"`
+	}
+	require.NoError(t, json.Unmarshal(statusBody, &statusResp))
+	require.Equal(t, checkedInSession.ID, statusResp.SessionID)
+	require.False(t, statusResp.Eligible)
+	require.Equal(t, errmsg.VotingNotCheckedIn.Message, statusResp.IneligibleReason)
+	require.Equal(t, ownTeam.ID, statusResp.OwnTeamID)
+
+	voteBody, statusCode := helpers.API_AccountsVotingCastVoteInSession(t, app, otherFinalist, checkedInSession.ID, voterToken)
+	require.Equal(t, http.StatusForbidden, statusCode)
+	require.NoError(t, json.Unmarshal(voteBody, &errResp))
+	require.Equal(t, errmsg.VotingNotCheckedIn.Message, errResp.Message)
+
+	_, statusCode = helpers.API_SuperUsersStaffRegister(t, app, voter.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	voteBody, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, ownTeam.ID, checkedInSession.ID, voterToken)
+	require.Equal(t, http.StatusForbidden, statusCode)
+	require.NoError(t, json.Unmarshal(voteBody, &errResp))
+	require.Equal(t, errmsg.VotingOwnTeam.Message, errResp.Message)
+
+	_, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, otherFinalist, checkedInSession.ID, voterToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// A session requiring a recent badge scan
+	sessionBody, statusCode = helpers.API_SuperUsersVotingCreateSessionWithEligibility(t, app, "Scanned Choice", now.Add(-time.Second), now.Add(time.Hour), saved.Teams,
+		models.VotingEligibility{ScannedWithinMinutes: 10}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var scannedSession struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(sessionBody, &scannedSession))
+
+	voteBody, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, ownTeam.ID, scannedSession.ID, voterToken)
+	require.Equal(t, http.StatusForbidden, statusCode)
+	require.NoError(t, json.Unmarshal(voteBody, &errResp))
+	require.Equal(t, errmsg.VotingScanRequired.Message, errResp.Message)
+
+	tagID := "test_pair_voting_tag"
+	_, statusCode = helpers.API_SuperUsersStaffTagsAssign(t, app, tagID, voter.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	_, statusCode = helpers.API_SuperUsersStaffTagsGet(t, app, tagID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, ownTeam.ID, scannedSession.ID, voterToken)
+	require.Equal(t, http.StatusOK, statusCode, "own team votes are allowed when the session doesn't exclude them")
+
+	_, err := db.Tags.DeleteOne(db.Ctx, bson.M{"id": tagID})
+	require.NoError(t, err)
+}
+
+// TestJudgingPairsLiveTally tests the hidden and revealed live tally and its event stream
+func TestJudgingPairsLiveTally(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	saved, serr := models.GetFinalists()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+
+	now := time.Now()
+	sessionBody, statusCode := helpers.API_SuperUsersVotingCreateSession(t, app, "Stage", "", now.Add(-time.Second), now.Add(time.Hour), saved.Teams, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var stage struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(sessionBody, &stage))
+
+	_, statusCode = helpers.API_SuperUsersVotingSetTallyHidden(t, app, stage.ID, true, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode, "the tally can be hidden while the session is open")
+
+	const numVoters = 3
+	for _, account := range createdPairingAccounts[:numVoters] {
+		loginBody, statusCode := helpers.API_AccountsAuthLogin(t, app, account.Email, "testpassword123")
+		require.Equal(t, http.StatusOK, statusCode)
+		var loginResp struct {
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(loginBody, &loginResp))
+
+		_, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, saved.Teams[0], stage.ID, loginResp.Token)
+		require.Equal(t, http.StatusOK, statusCode)
+	}
+
+	type tallyUpdate struct {
+		Status  string `json:"status"`
+		Hidden  bool   `json:"hidden"`
+		Ballots *int64 `json:"ballots"`
+		Teams   []struct {
+			TeamID string  `json:"teamID"`
+			Count  *int64  `json:"count"`
+			Share  float64 `json:"share"`
+		} `json:"teams"`
+	}
+
+	// While open, the hidden tally only shows rounded shares
+	bodyBytes, statusCode := helpers.API_SuperUsersVotingTally(t, app, stage.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var hidden tallyUpdate
+	require.NoError(t, json.Unmarshal(bodyBytes, &hidden))
+	require.True(t, hidden.Hidden)
+	require.Nil(t, hidden.Ballots)
+	require.Len(t, hidden.Teams, len(saved.Teams))
+	require.Nil(t, hidden.Teams[0].Count)
+	require.Equal(t, 1.0, hidden.Teams[0].Share)
+
+	// Closing early reveals the counts, and the stream ends with the final tally
+	closesAt := time.Now()
+	_, statusCode = helpers.API_SuperUsersVotingUpdateSession(t, app, stage.ID, nil, &closesAt, nil, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersVotingTallyStream(t, app, stage.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	stream := string(bodyBytes)
+	require.Contains(t, stream, "event: tally\n")
+	require.True(t, strings.HasSuffix(stream, "event: closed\ndata: {}\n\n"), stream)
+
+	// A browser's EventSource can't send headers, so it opens the stream with a stream token
+	bodyBytes, statusCode = helpers.API_SuperUsersVotingTallyStreamToken(t, app, stage.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var streamToken struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &streamToken))
+	require.NotEmpty(t, streamToken.Token)
+	require.True(t, streamToken.ExpiresAt.After(time.Now()))
+
+	bodyBytes, statusCode = helpers.API_SuperUsersVotingTallyStreamWithToken(t, app, stage.ID, streamToken.Token)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.True(t, strings.HasSuffix(string(bodyBytes), "event: closed\ndata: {}\n\n"), string(bodyBytes))
+
+	_, statusCode = helpers.API_SuperUsersVotingTallyStreamWithToken(t, app, stage.ID, "not-a-token")
+	require.Equal(t, http.StatusUnauthorized, statusCode)
+	_, statusCode = helpers.API_SuperUsersVotingTallyStreamWithToken(t, app, "another-session", streamToken.Token)
+	require.Equal(t, http.StatusUnauthorized, statusCode)
+	_, statusCode = helpers.API_SuperUsersVotingTallyStreamWithToken(t, app, stage.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// The stream token is no superuser token
+	_, statusCode = helpers.API_SuperUsersVotingTally(t, app, stage.ID, streamToken.Token)
+	require.Equal(t, http.StatusUnauthorized, statusCode)
+
+	var final tallyUpdate
+	for _, line := range strings.Split(stream, "\n") {
+		if data, ok := strings.CutPrefix(line, "data: "); ok && data != "{}" {
+			require.NoError(t, json.Unmarshal([]byte(data), &final))
+		}
+	}
+	require.Equal(t, models.VotingSessionClosed, final.Status)
+	require.False(t, final.Hidden)
+	require.NotNil(t, final.Ballots)
+	require.Equal(t, int64(numVoters), *final.Ballots)
+	require.NotNil(t, final.Teams[0].Count)
+	require.Equal(t, int64(numVoters), *final.Teams[0].Count)
+
+	counts, err := models.AggregateVoteCounts(stage.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(numVoters), counts.Ballots)
+	require.Equal(t, int64(numVoters), counts.First[saved.Teams[0]])
+}
+
+// TestJudgingPairsVoteAnomalies tests flagging bursts of votes and voiding the buckets they fall in
+func TestJudgingPairsVoteAnomalies(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	saved, serr := models.GetFinalists()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+
+	now := time.Now()
+	sessionBody, statusCode := helpers.API_SuperUsersVotingCreateSession(t, app, "Anomalies", "", now.Add(-time.Second), now.Add(time.Hour), saved.Teams, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var session struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(sessionBody, &session))
+
+	const numVoters = 3
+	for _, account := range createdPairingAccounts[:numVoters] {
+		loginBody, statusCode := helpers.API_AccountsAuthLogin(t, app, account.Email, "testpassword123")
+		require.Equal(t, http.StatusOK, statusCode)
+		var loginResp struct {
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(loginBody, &loginResp))
+
+		_, statusCode = helpers.API_AccountsVotingCastVoteInSession(t, app, saved.Teams[0], session.ID, loginResp.Token)
+		require.Equal(t, http.StatusOK, statusCode)
+	}
+
+	_, statusCode = helpers.API_SuperUsersVotingAnomalies(t, app, session.ID, "&burstFactor=0.5", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	type anomalies struct {
+		BucketMinutes int `json:"bucketMinutes"`
+		Buckets       []struct {
+			Bucket time.Time `json:"bucket"`
+			Votes  int64     `json:"votes"`
+		} `json:"buckets"`
+		Bursts []struct {
+			Bucket time.Time `json:"bucket"`
+			TeamID string    `json:"teamID"`
+			Votes  int64     `json:"votes"`
+		} `json:"bursts"`
+		Voids []models.VoteVoid `json:"voids"`
+	}
+
+	// The default thresholds don't flag a handful of votes
+	bodyBytes, statusCode := helpers.API_SuperUsersVotingAnomalies(t, app, session.ID, "", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var report anomalies
+	require.NoError(t, json.Unmarshal(bodyBytes, &report))
+	require.Equal(t, 5, report.BucketMinutes)
+	require.NotEmpty(t, report.Buckets)
+	require.Empty(t, report.Bursts)
+
+	var counted int64
+	for _, bucket := range report.Buckets {
+		counted += bucket.Votes
+	}
+	require.Equal(t, int64(numVoters), counted)
+
+	// With every vote counting as a burst, each bucket holding votes is flagged
+	bodyBytes, statusCode = helpers.API_SuperUsersVotingAnomalies(t, app, session.ID, "&minVotes=1&burstFactor=1", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.NoError(t, json.Unmarshal(bodyBytes, &report))
+	require.NotEmpty(t, report.Bursts)
+	for _, burst := range report.Bursts {
+		require.Equal(t, saved.Teams[0], burst.TeamID)
+	}
+
+	burst := report.Bursts[0]
+	_, statusCode = helpers.API_SuperUsersVotingVoidVotes(t, app, session.ID, burst.Bucket, saved.Teams[0], "  ", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "a justification is required")
+
+	_, statusCode = helpers.API_SuperUsersVotingVoidVotes(t, app, session.ID, burst.Bucket.Add(time.Minute), saved.Teams[0], "burst", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode, "the bucket must start on a bucket boundary")
+
+	var voided int64
+	for _, burst := range report.Bursts {
+		bodyBytes, statusCode = helpers.API_SuperUsersVotingVoidVotes(t, app, session.ID, burst.Bucket, saved.Teams[0], "votes right after a wave of logins", pairingTestSuperUserToken)
+		require.Equal(t, http.StatusOK, statusCode)
+		var void models.VoteVoid
+		require.NoError(t, json.Unmarshal(bodyBytes, &void))
+		require.Equal(t, burst.Votes, void.Votes)
+		require.Equal(t, "votes right after a wave of logins", void.Justification)
+		require.NotEmpty(t, void.CreatedBy)
+		voided += void.Votes
+	}
+	require.Equal(t, int64(numVoters), voided)
+
+	_, statusCode = helpers.API_SuperUsersVotingVoidVotes(t, app, session.ID, burst.Bucket, saved.Teams[0], "again", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusNotFound, statusCode, "voided votes can't be voided twice")
+
+	// Voided votes are no longer counted anywhere, and the voids are listed
+	bodyBytes, statusCode = helpers.API_SuperUsersVotingAnomalies(t, app, session.ID, "&minVotes=1&burstFactor=1", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.NoError(t, json.Unmarshal(bodyBytes, &report))
+	require.Empty(t, report.Bursts)
+	require.NotEmpty(t, report.Voids)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersVotingResults(t, app, session.ID, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var results struct {
+		Ballots int64 `json:"ballots"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &results))
+	require.Equal(t, int64(0), results.Ballots)
+
+	counts, err := models.GetVoteCounts(session.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), counts.Ballots)
+}
+
+// TestJudgingPairsFinalistManagement tests overriding, reordering and locking finalists
+func TestJudgingPairsFinalistManagement(t *testing.T) {
+	require.NotNil(t, app, "app should be initialized")
+
+	bodyBytes, statusCode := helpers.API_SuperUsersJudgingFinalists(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var finalistsResp struct {
+		Finalists []models.Team `json:"finalists"`
+		Count     int           `json:"count"`
+		Locked    bool          `json:"locked"`
+		Source    string        `json:"source"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &finalistsResp))
+	require.Equal(t, models.DefaultFinalistCount, finalistsResp.Count)
+	require.Len(t, finalistsResp.Finalists, finalistsResp.Count)
+	require.False(t, finalistsResp.Locked)
+
+	computed := make([]string, len(finalistsResp.Finalists))
+	for i, team := range finalistsResp.Finalists {
+		computed[i] = team.ID
+	}
+
+	// Duplicates, more teams than the count, and unknown teams are rejected
+	_, statusCode = helpers.API_SuperUsersJudgingUpdateFinalists(t, app, nil, []string{computed[0], computed[0]}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	two := 2
+	_, statusCode = helpers.API_SuperUsersJudgingUpdateFinalists(t, app, &two, nil, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersJudgingUpdateFinalists(t, app, nil, []string{"missing-team"}, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusNotFound, statusCode)
+
+	// Deliberation keeps three finalists, in a new order
+	three := 3
+	override := []string{computed[2], computed[0], computed[1]}
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingUpdateFinalists(t, app, &three, override, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.NoError(t, json.Unmarshal(bodyBytes, &finalistsResp))
+	require.Equal(t, 3, finalistsResp.Count)
+	require.Equal(t, models.FinalistsSourceManual, finalistsResp.Source)
+	require.Len(t, finalistsResp.Finalists, 3)
+	for i, team := range finalistsResp.Finalists {
+		require.Equal(t, override[i], team.ID)
+	}
+
+	_, statusCode = helpers.API_SuperUsersJudgingLockFinalists(t, app, true, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersJudgingUpdateFinalists(t, app, nil, computed[:3], pairingTestSuperUserToken)
+	require.Equal(t, http.StatusConflict, statusCode)
+
+	// Recomputing rankings keeps locked finalists; computing needs the judging stage
+	_, statusCode = helpers.API_SuperUsersFlagStagesExecute(t, app, "6", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingComputeRankings(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersFlagStagesExecute(t, app, "8", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	var rankingResp struct {
+		Finalists       []models.Team `json:"finalists"`
+		FinalistsLocked bool          `json:"finalistsLocked"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &rankingResp))
+	require.True(t, rankingResp.FinalistsLocked)
+	require.Len(t, rankingResp.Finalists, 3)
+	for i, team := range rankingResp.Finalists {
+		require.Equal(t, override[i], team.ID)
+	}
+
+	// Voters see the same list
+	saved, serr := models.GetFinalists()
+	require.Equal(t, errmsg.EmptyStatusError, serr)
+	require.Equal(t, override, saved.Teams)
+	require.True(t, saved.IsFinalist(override[0]))
+
+	_, statusCode = helpers.API_SuperUsersJudgingLockFinalists(t, app, false, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersJudgingUpdateFinalists(t, app, nil, computed[:3], pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// A team deleted after it was judged is passed over for the next one down
+	_, statusCode = helpers.API_SuperUsersFlagStagesExecute(t, app, "6", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingComputeRankings(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	rankingResp.Finalists = nil
+	require.NoError(t, json.Unmarshal(bodyBytes, &rankingResp))
+	require.Len(t, rankingResp.Finalists, 3)
+	top := make([]string, len(rankingResp.Finalists))
+	for i, team := range rankingResp.Finalists {
+		top[i] = team.ID
+	}
+
+	_, err := db.Teams.UpdateOne(db.Ctx, bson.M{"id": top[0]}, bson.M{"$set": bson.M{"deleted": true}})
+	require.NoError(t, err)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersJudgingComputeRankings(t, app, pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	rankingResp.Finalists = nil
+	require.NoError(t, json.Unmarshal(bodyBytes, &rankingResp))
+	require.Len(t, rankingResp.Finalists, 3)
+	require.Equal(t, top[1], rankingResp.Finalists[0].ID)
+	require.Equal(t, top[2], rankingResp.Finalists[1].ID)
+	require.NotEqual(t, top[0], rankingResp.Finalists[2].ID)
+
+	_, err = db.Teams.UpdateOne(db.Ctx, bson.M{"id": top[0]}, bson.M{"$set": bson.M{"deleted": false}})
+	require.NoError(t, err)
+
+	_, statusCode = helpers.API_SuperUsersFlagStagesExecute(t, app, "8", pairingTestSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+}
+
 // TestJudgingPairsCleanup cleans up resources
 func TestJudgingPairsCleanup(t *testing.T) {
 	_, err := db.Judges.DeleteMany(db.Ctx, bson.M{})
@@ -1434,5 +3035,35 @@ func TestJudgingPairsCleanup(t *testing.T) {
 	_, err = db.Votes.DeleteMany(db.Ctx, bson.M{})
 	require.NoError(t, err)
 
+	_, err = db.Leaderboard.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.RubricScores.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.JudgeNotes.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.JudgeSkips.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.JudgeConflicts.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.Settings.DeleteOne(db.Ctx, bson.M{"name": models.SettingJudgingRubric})
+	require.NoError(t, err)
+
+	_, err = db.Settings.DeleteOne(db.Ctx, bson.M{"name": models.SettingJudgeWeights})
+	require.NoError(t, err)
+
+	_, err = db.Settings.DeleteOne(db.Ctx, bson.M{"name": models.SettingFinalists})
+	require.NoError(t, err)
+
+	_, err = db.VotingSessions.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.VoteVoids.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
 	fmt.Printf("Cleanup complete\n")
 }
@@ -21,6 +21,7 @@ func TestMain(m *testing.M) {
 	flag.Parse()
 
 	app = internal.SetupApp("test", *envRootFlag, *appVersionFlag)
+	helpers.RegisterTestFlags()
 	helpers.ResetTestCache()
 	helpers.ResetTestEvents()
 
@@ -1,6 +1,7 @@
 package superusers
 
 import (
+	"backend/internal/db"
 	"backend/internal/env"
 	"backend/internal/errmsg"
 	"backend/internal/models"
@@ -10,10 +11,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 var (
@@ -282,6 +286,201 @@ func TestSuperUsersFlagsReset(t *testing.T) {
 	}
 }
 
+func TestSuperUsersConfigHistory(t *testing.T) {
+	_, statusCode := helpers.API_SuperUsersFlagsSet(t, app, "testing", true, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	time.Sleep(20 * time.Millisecond)
+	before := time.Now()
+	time.Sleep(20 * time.Millisecond)
+
+	_, statusCode = helpers.API_SuperUsersFlagsSet(t, app, "testing", false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// the timeline says who turned the flag off, and how
+	bodyBytes, statusCode := helpers.API_SuperUsersHistoryGet(t, app, models.ConfigKindFlag, "testing", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var history []models.ConfigChange
+	require.NoError(t, json.Unmarshal(bodyBytes, &history))
+	require.GreaterOrEqual(t, len(history), 2)
+	require.Equal(t, true, history[0].Old)
+	require.Equal(t, false, history[0].New)
+	require.Equal(t, env.SUPERUSER_USERNAME, history[0].ChangedBy)
+	require.Equal(t, "flag set", history[0].Source)
+	require.True(t, history[0].ChangedAt.After(before))
+
+	bodyBytes, statusCode = helpers.API_SuperUsersHistoryGet(t, app, "flags", "", testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.ConfigHistoryInvalidQuery, bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersHistoryRestore(t, app, time.Now().Add(time.Hour), "", nil, false, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.ConfigRestoreInvalid, bodyBytes, statusCode)
+
+	// a dry run lists the restore without applying it
+	bodyBytes, statusCode = helpers.API_SuperUsersHistoryRestore(t, app, before, models.ConfigKindFlag, []string{"testing"}, true, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var restore struct {
+		DryRun  bool                  `json:"dryRun"`
+		Changes []models.ConfigChange `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &restore))
+	require.True(t, restore.DryRun)
+	require.Len(t, restore.Changes, 1)
+	require.Equal(t, "testing", restore.Changes[0].Key)
+	require.Equal(t, true, restore.Changes[0].New)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var flags models.Flags
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.False(t, flags.Flags["testing"])
+
+	_, statusCode = helpers.API_SuperUsersHistoryRestore(t, app, before, models.ConfigKindFlag, []string{"testing"}, false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.True(t, flags.Flags["testing"])
+
+	// the restore is part of the history too
+	bodyBytes, statusCode = helpers.API_SuperUsersHistoryGet(t, app, models.ConfigKindFlag, "testing", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	history = nil
+	require.NoError(t, json.Unmarshal(bodyBytes, &history))
+	require.Equal(t, true, history[0].New)
+	require.Equal(t, "restored to "+before.UTC().Format(time.RFC3339), history[0].Source)
+
+	_, statusCode = helpers.API_SuperUsersFlagsSet(t, app, "testing", false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestSuperUsersFlagsRegistry(t *testing.T) {
+	bodyBytes, statusCode := helpers.API_SuperUsersFlagsRegistry(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var registry []models.FlagDefinition
+	require.NoError(t, json.Unmarshal(bodyBytes, &registry))
+	idx := slices.IndexFunc(registry, func(def models.FlagDefinition) bool {
+		return def.Name == "submissions_write"
+	})
+	require.NotEqual(t, -1, idx)
+	require.Contains(t, registry[idx].Requires, "teams_read")
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsSetBulk(t, app, map[string]bool{"flagsregistry_unknown": true}, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagUnknown([]string{"flagsregistry_unknown"}), bodyBytes, statusCode)
+
+	// every flag is off after the reset, so the ones it requires are missing
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsSet(t, app, "submissions_write", true, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagInconsistent([]string{
+		"submissions_write requires submissions_read",
+		"submissions_write requires teams_read",
+	}), bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsSetResolved(t, app, "submissions_write", true, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var flags map[string]bool
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.True(t, flags["teams_read"])
+	require.True(t, flags["submissions_read"])
+	require.True(t, flags["submissions_write"])
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsSetBulk(t, app, map[string]bool{"teams_read": false}, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagInconsistent([]string{
+		"submissions_read requires teams_read",
+		"submissions_write requires teams_read",
+	}), bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsSetBulkResolved(t, app, map[string]bool{"teams_read": false}, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	flags = nil
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.False(t, flags["teams_read"])
+	require.False(t, flags["submissions_read"])
+	require.False(t, flags["submissions_write"])
+
+	// stages are checked against the registry when they are created
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesCreate(t, app, models.FlagStage{
+		Name:   "flagsregistry",
+		TurnOn: []string{"flagsregistry_unknown"},
+	}, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagUnknown([]string{"flagsregistry_unknown"}), bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesCreate(t, app, models.FlagStage{
+		Name:    "flagsregistry",
+		TurnOn:  []string{"submissions_write", "submissions_read"},
+		TurnOff: []string{"teams_read"},
+	}, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagInconsistent([]string{
+		"submissions_read requires teams_read",
+		"submissions_write requires teams_read",
+	}), bodyBytes, statusCode)
+}
+
+func TestSuperUsersFlagRules(t *testing.T) {
+	bodyBytes, statusCode := helpers.API_SuperUsersFlagRulesSet(t, app, "flagrules", models.FlagRule{Percentage: 101}, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagRuleInvalid, bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagRulesSet(t, app, " ", models.FlagRule{Percentage: 10}, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagRuleInvalid, bodyBytes, statusCode)
+
+	rule := models.FlagRule{
+		AccountIDs:   []string{"acc_1"},
+		TeamIDs:      []string{"team_1"},
+		Universities: []string{"Politehnica Bucharest"},
+		Percentage:   25,
+	}
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagRulesSet(t, app, "flagrules", rule, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var rules map[string]models.FlagRule
+	require.NoError(t, json.Unmarshal(bodyBytes, &rules))
+	require.Equal(t, rule, rules["flagrules"])
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var flags models.Flags
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.Equal(t, rule, flags.Rules["flagrules"])
+
+	// a superuser is not an account, so only the global value applies to them
+	_, statusCode = helpers.API_SuperUsersFlagRulesSet(t, app, "test", models.FlagRule{Percentage: 100}, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	_, statusCode = helpers.API_SuperUsersFlagsSet(t, app, "test", false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsMiddleware(t, app, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagRequired, bodyBytes, statusCode)
+
+	_, statusCode = helpers.API_SuperUsersFlagsUnset(t, app, "test", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagRulesUnset(t, app, "flagrules", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	rules = nil
+	require.NoError(t, json.Unmarshal(bodyBytes, &rules))
+	require.NotContains(t, rules, "flagrules")
+	require.NotContains(t, rules, "test", "unsetting a flag removes its rule")
+
+	// rules are part of the history, and can be restored like flags
+	bodyBytes, statusCode = helpers.API_SuperUsersHistoryGet(t, app, models.ConfigKindRule, "flagrules", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var history []models.ConfigChange
+	require.NoError(t, json.Unmarshal(bodyBytes, &history))
+	require.GreaterOrEqual(t, len(history), 2)
+	require.True(t, history[0].Removed)
+	require.Equal(t, "rule unset", history[0].Source)
+	require.Equal(t, env.SUPERUSER_USERNAME, history[0].ChangedBy)
+
+	_, statusCode = helpers.API_SuperUsersHistoryRestore(t, app, history[0].ChangedAt.Add(-time.Millisecond), models.ConfigKindRule, []string{"flagrules"}, false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	flags = models.Flags{}
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.Equal(t, rule, flags.Rules["flagrules"])
+
+	_, statusCode = helpers.API_SuperUsersFlagRulesUnset(t, app, "flagrules", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+}
+
 func TestSuperUsersFlagStagesGet(t *testing.T) {
 	bodyBytes, statusCode := helpers.API_SuperUsersFlagStagesGet(
 		t,
@@ -345,6 +544,147 @@ func TestSuperUsersFlagStagesExecute(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, body.Stage.ID, testFlagStageID)
+
+	// the stage and its flags are applied together
+	stored := models.Flags{}
+	require.NoError(t, db.Flags.FindOne(db.Ctx, bson.M{}).Decode(&stored))
+	require.Equal(t, testFlagStageID, stored.Stage.ID)
+	for flag, value := range body.Stage.Instructions() {
+		require.Equal(t, value, stored.Flags[flag], flag)
+	}
+}
+
+func TestSuperUsersFlagStagesSchedule(t *testing.T) {
+	_, statusCode := helpers.API_SuperUsersFlagsSet(t, app, "flagstageson", false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// schedules need an existing stage and a time in the future
+	bodyBytes, statusCode := helpers.API_SuperUsersFlagStageScheduleCreate(t, app, "123", time.Now().Add(time.Hour), testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageNotFound, bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleCreate(t, app, testFlagStageID, time.Now().Add(-time.Minute), testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageScheduleInvalid, bodyBytes, statusCode)
+
+	// a schedule far off can be moved and cancelled, but only once
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleCreate(t, app, testFlagStageID, time.Now().Add(time.Hour), testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var later models.FlagStageSchedule
+	require.NoError(t, json.Unmarshal(bodyBytes, &later))
+	require.Equal(t, models.FlagStageSchedulePending, later.Status)
+	require.Equal(t, "test", later.StageName)
+
+	runAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleUpdate(t, app, later.ID, runAt, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.NoError(t, json.Unmarshal(bodyBytes, &later))
+	require.True(t, runAt.Equal(later.RunAt))
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleCancel(t, app, later.ID, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.NoError(t, json.Unmarshal(bodyBytes, &later))
+	require.Equal(t, models.FlagStageScheduleCancelled, later.Status)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleCancel(t, app, later.ID, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageScheduleNotPending, bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleCancel(t, app, "123", testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageScheduleNotFound, bodyBytes, statusCode)
+
+	// a schedule due shortly is executed by the scheduler
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleCreate(t, app, testFlagStageID, time.Now().Add(300*time.Millisecond), testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var soon models.FlagStageSchedule
+	require.NoError(t, json.Unmarshal(bodyBytes, &soon))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for soon.Status != models.FlagStageScheduleDone && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+
+		bodyBytes, statusCode = helpers.API_SuperUsersFlagStageSchedulesGet(t, app, testSuperUserToken)
+		require.Equal(t, http.StatusOK, statusCode)
+		var schedules []models.FlagStageSchedule
+		require.NoError(t, json.Unmarshal(bodyBytes, &schedules))
+		for _, schedule := range schedules {
+			if schedule.ID == soon.ID {
+				soon = schedule
+			}
+		}
+	}
+	require.Equal(t, models.FlagStageScheduleDone, soon.Status)
+	require.NotNil(t, soon.ExecutedAt)
+	require.Empty(t, soon.Error)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var flags models.Flags
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.True(t, flags.Flags["flagstageson"])
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageScheduleUpdate(t, app, soon.ID, time.Now().Add(time.Hour), testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageScheduleNotPending, bodyBytes, statusCode)
+}
+
+func TestSuperUsersFlagStagesDryRunAndRollback(t *testing.T) {
+	_, statusCode := helpers.API_SuperUsersFlagsSet(t, app, "flagstageson", false, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	_, statusCode = helpers.API_SuperUsersFlagsUnset(t, app, "flagstagesoff", testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode := helpers.API_SuperUsersFlagStagesDryRun(t, app, "123", testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageNotFound, bodyBytes, statusCode)
+
+	// the dry run lists the changes without applying them
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesDryRun(t, app, testFlagStageID, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var dryRun struct {
+		Changes   []models.FlagChange `json:"changes"`
+		Unchanged []string            `json:"unchanged"`
+	}
+	require.NoError(t, json.Unmarshal(bodyBytes, &dryRun))
+	require.Equal(t, []models.FlagChange{
+		{Flag: "flagstagesoff", Existed: false, From: false, To: false},
+		{Flag: "flagstageson", Existed: true, From: false, To: true},
+	}, dryRun.Changes)
+	require.Empty(t, dryRun.Unchanged)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var flags models.Flags
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.False(t, flags.Flags["flagstageson"])
+	require.NotContains(t, flags.Flags, "flagstagesoff")
+
+	_, statusCode = helpers.API_SuperUsersFlagStagesExecute(t, app, testFlagStageID, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStageExecutionsGet(t, app, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var executions []models.FlagStageExecution
+	require.NoError(t, json.Unmarshal(bodyBytes, &executions))
+	require.GreaterOrEqual(t, len(executions), 2)
+	latest := executions[0]
+	require.Equal(t, testFlagStageID, latest.StageID)
+	require.Equal(t, dryRun.Changes, latest.Changes)
+	require.False(t, latest.PreviousFlags["flagstageson"])
+	require.NotEmpty(t, latest.ExecutedBy)
+
+	// executions are rolled back latest first
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesRollback(t, app, executions[1].ID, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageRollbackConflict, bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesRollback(t, app, "123", testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageExecutionNotFound, bodyBytes, statusCode)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesRollback(t, app, latest.ID, testSuperUserToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	flags = models.Flags{}
+	require.NoError(t, json.Unmarshal(bodyBytes, &flags))
+	require.False(t, flags.Flags["flagstageson"])
+	require.NotContains(t, flags.Flags, "flagstagesoff", "flags the stage added are removed again")
+	require.Equal(t, latest.PreviousStage.ID, flags.Stage.ID)
+
+	bodyBytes, statusCode = helpers.API_SuperUsersFlagStagesRollback(t, app, latest.ID, testSuperUserToken)
+	helpers.ResponseErrorCheck(t, app, errmsg.FlagStageRollbackConflict, bodyBytes, statusCode)
 }
 
 func TestSuperUsersFlagStagesDelete(t *testing.T) {
@@ -364,6 +704,12 @@ func TestSuperUsersFlagStagesDelete(t *testing.T) {
 }
 
 func TestSuperUsersFlagStagesCleanup(t *testing.T) {
+	_, err := db.FlagStageSchedules.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
+	_, err = db.FlagStageExecutions.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
+
 	_, statusCode := helpers.API_SuperUsersFlagsUnset(
 		t,
 		app,
@@ -379,4 +725,7 @@ func TestSuperUsersFlagStagesCleanup(t *testing.T) {
 		testSuperUserToken,
 	)
 	require.Equal(t, http.StatusOK, statusCode)
+
+	_, err = db.ConfigHistory.DeleteMany(db.Ctx, bson.M{})
+	require.NoError(t, err)
 }